@@ -14,6 +14,37 @@ const (
 	// OvnKubeReady indicates that the ovnkube-node DaemonSet is ready
 	OvnKubeReady string = "OvnKubeReady"
 
+	// TenantDiscoveryProgressing indicates that the ovnkube-master pods of
+	// the tenant cluster are still being discovered
+	TenantDiscoveryProgressing string = "TenantDiscoveryProgressing"
+
+	// Degraded indicates that the DpuClusterConfig cannot be reconciled until
+	// its spec is fixed
+	Degraded string = "Degraded"
+
+	// CertificateExpiring indicates that a certificate used to reach the
+	// tenant cluster expires soon
+	CertificateExpiring string = "CertificateExpiring"
+
+	// TenantReachable indicates that the API server of the tenant cluster
+	// answers the heartbeat of the operator
+	TenantReachable string = "TenantReachable"
+
+	// TenantKubeconfigValid indicates that the tenant kubeconfig is found,
+	// parses and reaches the tenant cluster, the tenant syncer being started
+	// only then
+	TenantKubeconfigValid string = "TenantKubeconfigValid"
+
+	// RepresentorNetworksValid indicates that the VFs of the representor
+	// networks do not overlap, and that their NetworkAttachmentDefinitions
+	// exist in the tenant cluster
+	RepresentorNetworksValid string = "RepresentorNetworksValid"
+
+	// OvnTLSValid indicates that the ovn-cert synced from the tenant cluster
+	// is signed by the ovn-ca, and valid for the server name of the OVN
+	// databases when the OVSDB relay serves it
+	OvnTLSValid string = "OvnTLSValid"
+
 	// ReasonCreated is used when desired objects are created
 	ReasonCreated = "Created"
 
@@ -28,6 +59,89 @@ const (
 
 	// ReasonCreated is used when desired objects failed to start
 	ReasonFailedStart = "FailedStart"
+
+	// ReasonDiscovered is used when all the expected objects are found
+	ReasonDiscovered = "Discovered"
+
+	// ReasonInvalidPoolName is used when the poolName refers to a pool
+	// reserved by OpenShift
+	ReasonInvalidPoolName = "InvalidPoolName"
+
+	// ReasonInvalidOvsOption is used when an OVS other_config option of the
+	// hardwareOffload cannot be applied
+	ReasonInvalidOvsOption = "InvalidOvsOption"
+
+	// ReasonInvalidHostNetwork is used when an address or a route of the
+	// hostNetwork cannot be parsed
+	ReasonInvalidHostNetwork = "InvalidHostNetwork"
+
+	// ReasonInvalidJoin is used when the joining DPUs cannot be added to the
+	// pool of the join
+	ReasonInvalidJoin = "InvalidJoin"
+
+	// ReasonCrashLooping is used when a background task keeps failing
+	ReasonCrashLooping = "CrashLooping"
+
+	// ReasonSimulated is used when a step is skipped because the DPU
+	// hardware is simulated
+	ReasonSimulated = "Simulated"
+
+	// ReasonPoolMigrating is used while the nodes are moved to a renamed
+	// MachineConfigPool
+	ReasonPoolMigrating = "PoolMigrating"
+
+	// ReasonPoolConflict is used when the MachineConfigPool or the
+	// MachineConfig of a pool belongs to the DpuClusterConfig of another
+	// namespace
+	ReasonPoolConflict = "PoolConflict"
+
+	// ReasonTokenRenewalFailed is used when the token of the tenant
+	// kubeconfig cannot be renewed
+	ReasonTokenRenewalFailed = "TokenRenewalFailed"
+
+	// ReasonValid is used when the certificates are not about to expire, or
+	// when the tenant kubeconfig or the representor networks passed their
+	// checks
+	ReasonValid = "Valid"
+
+	// ReasonRepresentorConflict is used when a VF backs several representor
+	// networks, or the management port and a representor network
+	ReasonRepresentorConflict = "RepresentorConflict"
+
+	// ReasonNetworkNotFound is used when the NetworkAttachmentDefinition of
+	// a representor network does not exist in the tenant cluster
+	ReasonNetworkNotFound = "NetworkNotFound"
+
+	// ReasonKubeconfigNotFound is used when the secret or the file of the
+	// tenant kubeconfig does not exist
+	ReasonKubeconfigNotFound = "KubeconfigNotFound"
+
+	// ReasonKubeconfigKeyMissing is used when the secret of the tenant
+	// kubeconfig has none of the accepted keys
+	ReasonKubeconfigKeyMissing = "KubeconfigKeyMissing"
+
+	// ReasonKubeconfigInvalid is used when the tenant kubeconfig cannot be
+	// parsed, or lacks the context to use
+	ReasonKubeconfigInvalid = "KubeconfigInvalid"
+
+	// ReasonExpiringSoon is used when a certificate is about to expire
+	ReasonExpiringSoon = "ExpiringSoon"
+
+	// ReasonCertificateInvalid is used when the ovn-cert cannot be parsed,
+	// or is not signed by the ovn-ca
+	ReasonCertificateInvalid = "CertificateInvalid"
+
+	// ReasonMissingSubjectAltName is used when the ovn-cert is not valid for
+	// the server name the ovnkube-node pods verify
+	ReasonMissingSubjectAltName = "MissingSubjectAltName"
+
+	// ReasonHeartbeatSucceeded is used when the tenant API server answers
+	// the heartbeat
+	ReasonHeartbeatSucceeded = "HeartbeatSucceeded"
+
+	// ReasonTenantUnreachable is used when the tenant API server misses
+	// several heartbeats in a row
+	ReasonTenantUnreachable = "TenantUnreachable"
 )
 
 type conditionsBuilder struct {
@@ -86,6 +200,84 @@ func (builder *conditionsBuilder) McpReady() *conditionsBuilder {
 	return builder
 }
 
+func (builder *conditionsBuilder) TenantDiscoveryProgressing() *conditionsBuilder {
+	builder.status = v1.ConditionTrue
+	builder.cndType = TenantDiscoveryProgressing
+	return builder
+}
+
+func (builder *conditionsBuilder) TenantDiscoveryDone() *conditionsBuilder {
+	builder.status = v1.ConditionFalse
+	builder.cndType = TenantDiscoveryProgressing
+	return builder
+}
+
+func (builder *conditionsBuilder) Degraded() *conditionsBuilder {
+	builder.status = v1.ConditionTrue
+	builder.cndType = Degraded
+	return builder
+}
+
+func (builder *conditionsBuilder) CertificateExpiring() *conditionsBuilder {
+	builder.status = v1.ConditionTrue
+	builder.cndType = CertificateExpiring
+	return builder
+}
+
+func (builder *conditionsBuilder) NotCertificateExpiring() *conditionsBuilder {
+	builder.status = v1.ConditionFalse
+	builder.cndType = CertificateExpiring
+	return builder
+}
+
+func (builder *conditionsBuilder) TenantReachable() *conditionsBuilder {
+	builder.status = v1.ConditionTrue
+	builder.cndType = TenantReachable
+	return builder
+}
+
+func (builder *conditionsBuilder) NotTenantReachable() *conditionsBuilder {
+	builder.status = v1.ConditionFalse
+	builder.cndType = TenantReachable
+	return builder
+}
+
+func (builder *conditionsBuilder) TenantKubeconfigValid() *conditionsBuilder {
+	builder.status = v1.ConditionTrue
+	builder.cndType = TenantKubeconfigValid
+	return builder
+}
+
+func (builder *conditionsBuilder) NotTenantKubeconfigValid() *conditionsBuilder {
+	builder.status = v1.ConditionFalse
+	builder.cndType = TenantKubeconfigValid
+	return builder
+}
+
+func (builder *conditionsBuilder) RepresentorNetworksValid() *conditionsBuilder {
+	builder.status = v1.ConditionTrue
+	builder.cndType = RepresentorNetworksValid
+	return builder
+}
+
+func (builder *conditionsBuilder) NotRepresentorNetworksValid() *conditionsBuilder {
+	builder.status = v1.ConditionFalse
+	builder.cndType = RepresentorNetworksValid
+	return builder
+}
+
+func (builder *conditionsBuilder) OvnTLSValid() *conditionsBuilder {
+	builder.status = v1.ConditionTrue
+	builder.cndType = OvnTLSValid
+	return builder
+}
+
+func (builder *conditionsBuilder) NotOvnTLSValid() *conditionsBuilder {
+	builder.status = v1.ConditionFalse
+	builder.cndType = OvnTLSValid
+	return builder
+}
+
 func (builder *conditionsBuilder) Reason(r string) *conditionsBuilder {
 	builder.reason = r
 	return builder
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,17 +25,699 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // DpuClusterConfigSpec defines the desired state of DpuClusterConfig
+// +kubebuilder:validation:XValidation:rule="!has(self.ovsMetrics) || !has(self.deploymentMode) || self.deploymentMode == 'Full'",message="ovsMetrics requires the Full deploymentMode"
 type DpuClusterConfigSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
 	// KubeConfigFile is the secret name of the tenant cluster kubeconfig file
 	KubeConfigFile string `json:"kubeConfigFile,omitempty"`
+	// KubeConfigPath is the path of the tenant cluster kubeconfig file
+	// mounted in the operator pod, e.g. from a projected secret or a CSI
+	// volume. It takes precedence over KubeConfigFile.
+	KubeConfigPath string `json:"kubeConfigPath,omitempty"`
+	// KubeConfigContext is the context of the tenant kubeconfig used to
+	// reach the tenant cluster, when the kubeconfig holds several contexts.
+	// The current-context of the kubeconfig is used when unset.
+	// +optional
+	KubeConfigContext string `json:"kubeConfigContext,omitempty"`
+	// TokenRenewal enables the renewal of the ServiceAccount token of the
+	// tenant kubeconfig with the TokenRequest API. The ServiceAccount must
+	// be allowed to create its own tokens in the tenant cluster.
+	// +optional
+	TokenRenewal *TokenRenewalSpec `json:"tokenRenewal,omitempty"`
+	// TenantRateLimit caps the requests of the operator to the API server
+	// of the tenant cluster, so that the reconciles of many DPU clusters do
+	// not overwhelm a small tenant control plane. The client-go defaults
+	// apply to each client when unset.
+	// +optional
+	TenantRateLimit *TenantRateLimitSpec `json:"tenantRateLimit,omitempty"`
+	// MasterDiscoveryTTL is how long the ovnkube-master pods discovered in
+	// the tenant cluster are cached. The reconciles use the cached IPs, which
+	// are refreshed in the background.
+	// +kubebuilder:default="1m"
+	// +optional
+	MasterDiscoveryTTL *metav1.Duration `json:"masterDiscoveryTTL,omitempty"`
+	// TenantNamespace is the namespace of OVN-Kubernetes in the tenant
+	// cluster
+	// +kubebuilder:default=openshift-ovn-kubernetes
+	// +optional
+	TenantNamespace string `json:"tenantNamespace,omitempty"`
 	// PoolName is the name of the MachineConfigPool CR which contains
 	// the BF2 nodes in the infra cluster.
+	// +kubebuilder:validation:XValidation:rule="self != 'master' && self != 'worker'",message="the master and worker pools are not allowed"
 	PoolName string `json:"poolName"`
 	// nodeSelector specifies a label selector for Machines
 	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	// Pools are additional MachineConfigPools of DPU nodes served by the
+	// same tenant cluster, e.g. to segment the BF2 and BF3 cards. Each pool
+	// gets its own MachineConfig and ovnkube-node DaemonSet.
+	// +listType=map
+	// +listMapKey=poolName
+	// +optional
+	Pools []DpuPoolSpec `json:"pools,omitempty"`
+	// OvnkubeImage is the ovnkube image of the ovnkube-node pods, it
+	// defaults to the image of the ovnkube-node pods of the cluster.
+	// +optional
+	OvnkubeImage string `json:"ovnkubeImage,omitempty"`
+	// HostPFRepresentor is the name of the host PF representor added to the
+	// br-ex bridge of the DPU nodes, e.g. pf0hpf. It defaults to the
+	// representor of the PF of the default route.
+	// +optional
+	HostPFRepresentor string `json:"hostPFRepresentor,omitempty"`
+	// HostConfig declares additional kernel modules, sysctls and udev rules
+	// of the DPU nodes, merged into the MachineConfig of the operator
+	// +optional
+	HostConfig *HostConfigSpec `json:"hostConfig,omitempty"`
+	// TimeSync configures the clock synchronization of the DPU nodes. The
+	// OVN SSL handshakes of the DPU nodes fail when their clock drifts.
+	// +optional
+	TimeSync *TimeSyncSpec `json:"timeSync,omitempty"`
+	// HostNetwork configures secondary interfaces and static routes of the
+	// DPU nodes, e.g. for the out-of-band network
+	// +optional
+	HostNetwork *HostNetworkSpec `json:"hostNetwork,omitempty"`
+	// HardwareOffload tunes the OVS hardware offload of the DPU nodes
+	// +optional
+	HardwareOffload *HardwareOffloadSpec `json:"hardwareOffload,omitempty"`
+	// IPsec runs the OVN IPsec daemons on the DPU nodes, so that the pod
+	// traffic stays encrypted when the tenant cluster has IPsec enabled. The
+	// certificates of the DPUs are signed by the tenant cluster.
+	// +optional
+	IPsec bool `json:"ipsec,omitempty"`
+	// OvnkubeNode configures the pods of the ovnkube-node DaemonSets
+	// +optional
+	OvnkubeNode *OvnkubeNodeSpec `json:"ovnkubeNode,omitempty"`
+	// DeploymentMode selects the components of the ovnkube-node DaemonSets.
+	// Full runs ovnkube-node and ovn-controller on the DPU nodes.
+	// OvnControllerOnly only runs ovn-controller, for deployments running
+	// the management plane of ovnkube on the tenant hosts, the OVN settings
+	// of the DPU chassis being set by the ovn-controller pods instead.
+	// +kubebuilder:validation:Enum=Full;OvnControllerOnly
+	// +kubebuilder:default=Full
+	// +optional
+	DeploymentMode DeploymentMode `json:"deploymentMode,omitempty"`
+	// Cni makes the ovnkube-node pods install the CNI plugin and
+	// configuration of ovn-kubernetes on the DPU nodes, and revert their
+	// drift. They are left to the DPU OS image when unset, and in the
+	// OvnControllerOnly deploymentMode.
+	// +optional
+	Cni *CniSpec `json:"cni,omitempty"`
+	// HostedCluster shall be set when the tenant cluster is a HyperShift
+	// hosted cluster. In that case KubeConfigFile refers to the admin
+	// kubeconfig secret of the HostedCluster.
+	HostedCluster *HostedClusterSpec `json:"hostedCluster,omitempty"`
+	// OpiBridge configures the Open Programmable Infrastructure (OPI) bridge
+	// running on the DPU nodes. The bridge is not deployed when unset.
+	OpiBridge *OpiBridgeSpec `json:"opiBridge,omitempty"`
+	// DocaTelemetry configures the NVIDIA DOCA telemetry service running on
+	// the DPU nodes. The service is not deployed when unset.
+	DocaTelemetry *DocaTelemetrySpec `json:"docaTelemetry,omitempty"`
+	// OvsMetrics makes the ovnkube-node pods export the OVS and
+	// ovn-controller metrics of the DPU nodes, e.g. the datapath flows, the
+	// hardware offload and the ovn-controller statistics. The metrics are not
+	// exported when unset.
+	// +optional
+	OvsMetrics *OvsMetricsSpec `json:"ovsMetrics,omitempty"`
+	// OvsdbRelay deploys a relay of the OVN southbound database of the
+	// tenant cluster, to which the ovn-controllers of the DPU nodes connect
+	// instead of the tenant cluster. The ovn-controllers connect to the
+	// tenant cluster directly when unset.
+	// +optional
+	OvsdbRelay *OvsdbRelaySpec `json:"ovsdbRelay,omitempty"`
+	// OvnTLS hardens the TLS connections of the ovn-controllers, ovnkube-node
+	// and the OVSDB relay to the OVN databases, and makes the operator check
+	// the ovn-cert synced from the tenant cluster. The OVN defaults apply
+	// when unset.
+	// +optional
+	OvnTLS *OvnTLSSpec `json:"ovnTLS,omitempty"`
+	// NodeIdentity makes ovnkube-node authenticate to the tenant cluster
+	// with a per-node certificate, as with the ovnkube-identity of recent
+	// ovn-kubernetes, rather than with the tenant kubeconfig. The
+	// certificates are requested with CertificateSigningRequests the
+	// operator approves.
+	// +optional
+	NodeIdentity *NodeIdentitySpec `json:"nodeIdentity,omitempty"`
+	// Join lets freshly imaged DPUs register with the infra cluster with a
+	// short-lived bootstrap token. The operator approves the certificates of
+	// their kubelets, adds them to a MachineConfigPool and creates their
+	// DpuNodeConfig. The DPU nodes are only added manually when unset.
+	// +optional
+	Join *JoinSpec `json:"join,omitempty"`
+	// SriovIntegration selects how the operator coordinates with the
+	// sriov-network-operator when both manage the NICs of the DPU nodes.
+	// None ignores the sriov-network-operator. Compose leaves the naming of
+	// the VF representors to the sriov-network-operator. Defer in addition
+	// leaves the switchdev configuration of the NICs found in the
+	// SriovNetworkNodeStates to the sriov-network-operator.
+	// +kubebuilder:validation:Enum=None;Compose;Defer
+	// +kubebuilder:default=None
+	// +optional
+	SriovIntegration SriovIntegrationMode `json:"sriovIntegration,omitempty"`
+	// TenantAgent configures the agent publishing the serial number of the
+	// DPU of each tenant worker as an annotation of its Node in the tenant
+	// cluster. The agent is not deployed when unset.
+	TenantAgent *TenantAgentSpec `json:"tenantAgent,omitempty"`
+	// DpuHost makes the operator deploy ovnkube in dpu-host mode on the
+	// tenant workers backed by a DPU, with the ovnkube image of the DPU
+	// nodes. It is not deployed when unset.
+	// +optional
+	DpuHost *DpuHostSpec `json:"dpuHost,omitempty"`
+	// RepresentorNetworks are the Multus secondary networks of the tenant
+	// cluster backed by VFs of the DPUs. The VFs of each network are checked
+	// against the other networks and the management port, and their
+	// representors on the DPU nodes are reported in the status.
+	// +optional
+	RepresentorNetworks []RepresentorNetworkSpec `json:"representorNetworks,omitempty"`
+	// OvsAgent configures the agent enforcing the OVS settings of the DPU
+	// nodes, which reverts the manual ovs-vsctl edits. The agent is not
+	// deployed when unset.
+	// +optional
+	OvsAgent *OvsAgentSpec `json:"ovsAgent,omitempty"`
+	// DrainBlocker configures the scheduling of the pods blocking the drain
+	// of the DPU nodes
+	// +optional
+	DrainBlocker *DrainBlockerSpec `json:"drainBlocker,omitempty"`
+	// Remediation creates a remediation request for the tenant node of a
+	// DPU node that stays unreachable, so that the workloads are moved off
+	// a host whose NIC is dead. No remediation is requested when unset.
+	// +optional
+	Remediation *RemediationSpec `json:"remediation,omitempty"`
+	// TaintTenantNodes makes the operator keep the
+	// network.openshift.io/dpu-network-unavailable NoSchedule taint on the
+	// tenant nodes until the network of their DPU is ready, and remove it
+	// once it is. The DpuNetworkReady condition of the tenant nodes is set
+	// regardless.
+	// +optional
+	TaintTenantNodes bool `json:"taintTenantNodes,omitempty"`
+	// LabelTenantNodes makes the operator label the tenant nodes backed by a
+	// DPU with network.openshift.io/dpu=true, along with the serial number
+	// of their DPU when the tenant agent published it. The tenant nodes are
+	// not labelled when unset.
+	// +optional
+	LabelTenantNodes bool `json:"labelTenantNodes,omitempty"`
+	// ConnectivityCheck deploys checker pods on the tenant workers backed by
+	// a DPU, validating the pod-to-pod and pod-to-service paths through the
+	// offloaded datapath. The results are reported in the
+	// DpuConnectivityCheck named after the DpuClusterConfig. It is not
+	// deployed when unset.
+	// +optional
+	ConnectivityCheck *ConnectivityCheckSpec `json:"connectivityCheck,omitempty"`
+	// PriorityClassName is the PriorityClass of the ovnkube-node and drain
+	// blocker pods. The eviction of a drain blocker pod under node pressure
+	// would unblock the drain of its DPU node.
+	// +kubebuilder:default=system-node-critical
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// CommonLabels are added to all the objects managed by the operator for
+	// this DpuClusterConfig. They do not override the labels set by the
+	// operator itself.
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+	// CommonAnnotations are added to all the objects managed by the operator
+	// for this DpuClusterConfig. They do not override the annotations set by
+	// the operator itself.
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+}
+
+// DpuPoolSpec defines an additional pool of DPU nodes, its fields override
+// the ones of the DpuClusterConfigSpec for the nodes of the pool
+type DpuPoolSpec struct {
+	// PoolName is the name of the MachineConfigPool of the pool
+	// +kubebuilder:validation:XValidation:rule="self != 'master' && self != 'worker'",message="the master and worker pools are not allowed"
+	// +kubebuilder:validation:XValidation:rule="self != 'dpu-worker'",message="the dpu-worker pool name is reserved for the main pool"
+	PoolName string `json:"poolName"`
+	// NodeSelector selects the nodes of the pool
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector"`
+	// OvnkubeImage overrides the ovnkube image of the pool
+	// +optional
+	OvnkubeImage string `json:"ovnkubeImage,omitempty"`
+	// HostPFRepresentor overrides the host PF representor of the pool
+	// +optional
+	HostPFRepresentor string `json:"hostPFRepresentor,omitempty"`
+	// DrainBlockerImage overrides the image of the drain blocker pods of the
+	// pool
+	// +optional
+	DrainBlockerImage string `json:"drainBlockerImage,omitempty"`
+}
+
+// DeploymentMode is the set of components of the ovnkube-node DaemonSets
+type DeploymentMode string
+
+const (
+	DeploymentModeFull              DeploymentMode = "Full"
+	DeploymentModeOvnControllerOnly DeploymentMode = "OvnControllerOnly"
+)
+
+// SriovIntegrationMode is the mode of coordination with the
+// sriov-network-operator
+type SriovIntegrationMode string
+
+const (
+	SriovIntegrationNone    SriovIntegrationMode = "None"
+	SriovIntegrationCompose SriovIntegrationMode = "Compose"
+	SriovIntegrationDefer   SriovIntegrationMode = "Defer"
+)
+
+// HardwareOffloadSpec defines the OVS other_config options of the DPU nodes
+// related to the hardware offload of the datapath flows
+type HardwareOffloadSpec struct {
+	// Enabled sets other_config:hw-offload, it defaults to true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// TcPolicy sets other_config:tc-policy, the policy of the flows
+	// offloaded to TC. OVS defaults to none.
+	// +kubebuilder:validation:Enum=none;skip_sw;skip_hw
+	// +optional
+	TcPolicy string `json:"tcPolicy,omitempty"`
+	// MaxIdle sets other_config:max-idle, the time in milliseconds after
+	// which the idle datapath flows are removed
+	// +kubebuilder:validation:Minimum=500
+	// +optional
+	MaxIdle *int32 `json:"maxIdle,omitempty"`
+	// OtherConfig sets additional other_config options, e.g.
+	// n-handler-threads. The options set by the other fields take
+	// precedence.
+	// +optional
+	OtherConfig map[string]string `json:"otherConfig,omitempty"`
+}
+
+// HostConfigSpec defines additional configuration files of the DPU nodes
+type HostConfigSpec struct {
+	// KernelModules are the kernel modules loaded at boot
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_-]+$`
+	// +optional
+	KernelModules []string `json:"kernelModules,omitempty"`
+	// Sysctls are the kernel parameters set at boot, e.g.
+	// net.core.rmem_max: "16777216"
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+	// UdevRules are the additional udev rules, one rule per item
+	// +optional
+	UdevRules []string `json:"udevRules,omitempty"`
+}
+
+// HostNetworkSpec defines the NetworkManager connections of the DPU nodes
+type HostNetworkSpec struct {
+	// Interfaces are the secondary interfaces of the DPU nodes
+	// +optional
+	Interfaces []HostInterfaceSpec `json:"interfaces,omitempty"`
+}
+
+// HostInterfaceSpec defines the addresses and routes of a secondary interface
+type HostInterfaceSpec struct {
+	// Name is the name of the interface, e.g. oob_net0
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+$`
+	// +kubebuilder:validation:MaxLength=15
+	Name string `json:"name"`
+	// Addresses are the static addresses of the interface in CIDR notation.
+	// The address families without one use DHCP or SLAAC.
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+	// Routes are the static routes through the interface
+	// +optional
+	Routes []StaticRoute `json:"routes,omitempty"`
+}
+
+// StaticRoute is a static route of a secondary interface
+type StaticRoute struct {
+	// Destination is the destination network in CIDR notation
+	Destination string `json:"destination"`
+	// Gateway is the next hop of the route, the destination is on-link when
+	// it is not set
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+	// Metric is the metric of the route
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Metric *int32 `json:"metric,omitempty"`
+}
+
+// TimeSyncSpec defines the chrony and PTP configuration of the DPU nodes
+type TimeSyncSpec struct {
+	// NTPServers are the NTP servers of chrony, replacing the ones of the
+	// default chrony configuration
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9.:-]+$`
+	// +optional
+	NTPServers []string `json:"ntpServers,omitempty"`
+	// PTPInterface is the DPU interface on which ptp4l synchronizes the
+	// hardware clock of the NIC. The hardware clock is then the preferred
+	// time source of chrony, the NTP servers being the fallback.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]*$`
+	// +optional
+	PTPInterface string `json:"ptpInterface,omitempty"`
+	// PTPDomain is the PTP domain number of ptp4l
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=255
+	// +optional
+	PTPDomain *int32 `json:"ptpDomain,omitempty"`
+}
+
+// HostedClusterSpec defines how to reach the OVN databases of a HyperShift
+// hosted cluster. The databases run as pods of the hosted control plane in
+// the management cluster, so they cannot be discovered from the tenant
+// cluster itself.
+type HostedClusterSpec struct {
+	// NbDbAddress is the address of the OVN northbound database exposed by
+	// the management cluster, e.g. ssl:ovnkube-nbdb.apps.example.com:443
+	NbDbAddress string `json:"nbDbAddress"`
+	// SbDbAddress is the address of the OVN southbound database exposed by
+	// the management cluster, e.g. ssl:ovnkube-sbdb.apps.example.com:443
+	SbDbAddress string `json:"sbDbAddress"`
+}
+
+// DocaTelemetrySpec defines the DOCA telemetry service deployed on the DPU
+// nodes. The counters it exports are forwarded to the operator metrics
+// endpoint.
+type DocaTelemetrySpec struct {
+	// Image is the DOCA telemetry service image, e.g. nvcr.io/nvidia/doca/doca_telemetry
+	Image string `json:"image"`
+	// PrometheusPort is the port of the Prometheus endpoint of the service
+	// +kubebuilder:default=9100
+	// +optional
+	PrometheusPort int32 `json:"prometheusPort,omitempty"`
+}
+
+// OvsMetricsSpec defines the OVS and ovn-controller metrics endpoint of the
+// ovnkube-node pods. The metrics it exports are forwarded to the operator
+// metrics endpoint.
+type OvsMetricsSpec struct {
+	// Port is the port of the metrics endpoint on the DPU nodes
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=9105
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// OvsdbRelaySpec defines the relay of the OVN southbound database of the
+// tenant cluster, which runs as a Deployment behind a Service
+type OvsdbRelaySpec struct {
+	// Image is the image of the relay pods, which run ovsdb-server in it.
+	// The ovnkube image of the DPU nodes is used when unset, which only runs
+	// on nodes of the same architecture.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Replicas is the number of relay pods
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=2
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// NodeSelector selects the nodes running the relay pods, e.g. the
+	// infrastructure nodes
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
+
+// OvnTLSSpec defines the TLS settings of the connections to the OVN
+// databases
+type OvnTLSSpec struct {
+	// MinVersion is the lowest TLS version accepted by the ovn-controllers
+	// and the OVSDB relay. ovnkube-node uses the defaults of Go, TLS 1.2 at
+	// least.
+	// +kubebuilder:validation:Enum=TLSv1.2;TLSv1.3
+	// +optional
+	MinVersion string `json:"minVersion,omitempty"`
+	// Ciphers is the OpenSSL cipher list of the TLS 1.2 connections of the
+	// ovn-controllers and the OVSDB relay, e.g.
+	// ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9!:+@_.-]+$`
+	// +optional
+	Ciphers string `json:"ciphers,omitempty"`
+	// ServerName is the name ovnkube-node verifies the certificates of the
+	// OVN databases against, it defaults to ovn. With the OVSDB relay, the
+	// ovn-cert served by the relay must be valid for it.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9]([-a-zA-Z0-9.]*[a-zA-Z0-9])?$`
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// JoinSpec defines the bootstrap token join of new DPUs
+type JoinSpec struct {
+	// TokenTTL is the lifetime of the bootstrap tokens, which are rotated
+	// once half of it elapsed. It defaults to 1h.
+	// +optional
+	TokenTTL *metav1.Duration `json:"tokenTTL,omitempty"`
+	// PoolName is the MachineConfigPool the joining DPUs are added to,
+	// either the pool of the DpuClusterConfig or one of its pools, whose
+	// nodeSelector must have matchLabels. It defaults to the pool of the
+	// DpuClusterConfig.
+	// +optional
+	PoolName string `json:"poolName,omitempty"`
+	// APIServerURL is the URL of the API server the DPUs join, it defaults
+	// to the internal API server URL of the cluster infrastructure.
+	// +kubebuilder:validation:Pattern=`^https://`
+	// +optional
+	APIServerURL string `json:"apiServerURL,omitempty"`
+}
+
+// NodeIdentitySpec defines the per-node certificates of ovnkube-node
+type NodeIdentitySpec struct {
+	// CertDuration is the lifetime of the certificates requested by
+	// ovnkube-node, which renews them before they expire. It defaults to
+	// the ovnkube default.
+	// +optional
+	CertDuration *metav1.Duration `json:"certDuration,omitempty"`
+}
+
+// OpiBridgeSpec defines the OPI bridge deployed on the DPU nodes
+type OpiBridgeSpec struct {
+	// Image is the OPI bridge image, e.g. ghcr.io/opiproject/opi-nvidia-bridge
+	Image string `json:"image"`
+	// Port is the port on which the OPI gRPC API is served on the DPU nodes
+	// +kubebuilder:default=50051
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// DrainBlockerSpec defines the scheduling of the drain blocker pods, which
+// run on each DPU node to block its drain with a PodDisruptionBudget
+type DrainBlockerSpec struct {
+	// Image is the image of the drain blocker pods, which run sleep in it.
+	// The pods run the operator image when unset.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// ImagePullSecrets are the secrets used to pull the image of the drain
+	// blocker pods. They must exist in the namespace of the operator, where
+	// the pods run.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Tolerations of the drain blocker pods. The pods tolerate all the
+	// taints when unset, so that the drain of tainted DPU nodes is blocked
+	// as well.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity of the drain blocker pods, the pods are pinned to their DPU
+	// node in any case
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+}
+
+// RemediationSpec defines when and how the tenant node of an unreachable DPU
+// node is remediated
+type RemediationSpec struct {
+	// UnreachableTimeout is how long the Ready condition of a DPU node has
+	// to be False or Unknown before its tenant node is remediated
+	// +kubebuilder:default="5m"
+	// +optional
+	UnreachableTimeout *metav1.Duration `json:"unreachableTimeout,omitempty"`
+	// Template is the remediation template in the tenant cluster, as in the
+	// NodeHealthCheck API, e.g. the SelfNodeRemediationTemplate of the
+	// self-node-remediation operator
+	Template RemediationTemplateReference `json:"template"`
+}
+
+// RemediationTemplateReference references a remediation template. The
+// remediation requests are of the kind of the template without its Template
+// suffix, e.g. SelfNodeRemediation, and are created in its namespace.
+type RemediationTemplateReference struct {
+	// APIVersion of the template, e.g.
+	// self-node-remediation.medik8s.io/v1alpha1
+	APIVersion string `json:"apiVersion"`
+	// Kind of the template, e.g. SelfNodeRemediationTemplate
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9]+Template$`
+	Kind string `json:"kind"`
+	// Name of the template
+	Name string `json:"name"`
+	// Namespace of the template
+	Namespace string `json:"namespace"`
+}
+
+// CniSpec defines the CNI plugin and configuration of ovn-kubernetes on the
+// DPU nodes
+type CniSpec struct {
+	// BinDir is the directory of the CNI plugins on the DPU nodes
+	// +kubebuilder:default="/var/lib/cni/bin"
+	// +optional
+	BinDir string `json:"binDir,omitempty"`
+	// ConfDir is the directory of the CNI configuration files on the DPU
+	// nodes
+	// +kubebuilder:default="/etc/cni/net.d"
+	// +optional
+	ConfDir string `json:"confDir,omitempty"`
+	// LogLevel is the log level of the CNI plugin
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=5
+	// +kubebuilder:default=4
+	// +optional
+	LogLevel int32 `json:"logLevel,omitempty"`
+}
+
+// OvnkubeNodeSpec defines the pod settings of the ovnkube-node DaemonSets,
+// which some DPU OS builds need to change
+type OvnkubeNodeSpec struct {
+	// DNSPolicy of the ovnkube-node pods, e.g. ClusterFirstWithHostNet. The
+	// default policy of the pods applies when unset.
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	// +optional
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// HostNetwork runs the ovnkube-node pods in the network namespace of the
+	// host, it defaults to true
+	// +optional
+	HostNetwork *bool `json:"hostNetwork,omitempty"`
+	// HostPID runs the ovnkube-node pods in the PID namespace of the host,
+	// it defaults to true
+	// +optional
+	HostPID *bool `json:"hostPID,omitempty"`
+}
+
+// TenantAgentSpec defines the agent deployed on the tenant workers
+type TenantAgentSpec struct {
+	// Image is the image of the operator, which runs the agent
+	Image string `json:"image"`
+}
+
+// DpuHostSpec defines the ovnkube DaemonSet in dpu-host mode of the tenant
+// cluster
+type DpuHostSpec struct {
+	// ManagementPortNetdev is the netdev of the tenant workers used as the
+	// ovn-kubernetes management port, e.g. a VF of the DPU
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+$`
+	ManagementPortNetdev string `json:"managementPortNetdev"`
+	// NodeSelector selects the tenant workers backed by a DPU. It defaults
+	// to the network.openshift.io/dpu label set by the operator.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// ManagementPort is the PF and VF of the DPU backing the management
+	// port netdev. The operator sets it as the k8s.ovn.org/node-mgmt-port
+	// annotation of the tenant nodes when set.
+	// +optional
+	ManagementPort *ManagementPortSpec `json:"managementPort,omitempty"`
+	// NodeAnnotations are other ovn-kubernetes annotations the operator
+	// keeps on the tenant nodes, e.g. the gateway interface of the
+	// ovn-kubernetes release in use
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k.startsWith('k8s.ovn.org/'))",message="only the k8s.ovn.org/ annotations are allowed"
+	// +optional
+	NodeAnnotations map[string]string `json:"nodeAnnotations,omitempty"`
+}
+
+// ManagementPortSpec identifies the function of the DPU used as the
+// ovn-kubernetes management port of the tenant nodes
+type ManagementPortSpec struct {
+	// PfID is the index of the PF of the DPU
+	// +kubebuilder:validation:Minimum=0
+	PfID int32 `json:"pfId"`
+	// FuncID is the index of the VF of the PF
+	// +kubebuilder:validation:Minimum=0
+	FuncID int32 `json:"funcId"`
+}
+
+// RepresentorNetworkSpec defines the VFs of the DPUs backing a Multus
+// secondary network of the tenant cluster
+// +kubebuilder:validation:XValidation:rule="self.lastVf >= self.firstVf",message="lastVf must not be lower than firstVf"
+type RepresentorNetworkSpec struct {
+	// Network is the NetworkAttachmentDefinition of the tenant cluster, as
+	// namespace/name
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?/[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	Network string `json:"network"`
+	// PfID is the index of the PF of the DPU
+	// +kubebuilder:validation:Minimum=0
+	PfID int32 `json:"pfId"`
+	// FirstVF is the index of the first VF of the PF backing the network
+	// +kubebuilder:validation:Minimum=0
+	FirstVF int32 `json:"firstVf"`
+	// LastVF is the index of the last VF of the PF backing the network
+	// +kubebuilder:validation:Minimum=0
+	LastVF int32 `json:"lastVf"`
+}
+
+// OvsAgentSpec defines the agent deployed on the DPU nodes and the OVS
+// settings it enforces, in addition to the other_config options of the
+// hardwareOffload
+type OvsAgentSpec struct {
+	// Image is the image of the operator, which runs the agent
+	Image string `json:"image"`
+	// BridgeMappings sets external_ids:ovn-bridge-mappings, e.g.
+	// physnet:br-ex
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+:[a-zA-Z0-9_.-]+(,[a-zA-Z0-9_.-]+:[a-zA-Z0-9_.-]+)*$`
+	// +optional
+	BridgeMappings string `json:"bridgeMappings,omitempty"`
+	// FlowLimit sets other_config:flow-limit, the maximum number of flows
+	// in the datapath
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FlowLimit *int32 `json:"flowLimit,omitempty"`
+	// Interval is the period at which the agent checks the OVS settings, it
+	// defaults to 1m
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Statistics makes the agent sample the datapath flows and the conntrack
+	// entries of the DPU nodes. They are exported as metrics labeled with the
+	// tenant node of each DPU, and forwarded to the operator metrics
+	// endpoint. They are not sampled when unset.
+	// +optional
+	Statistics *OvsStatisticsSpec `json:"statistics,omitempty"`
+}
+
+// OvsStatisticsSpec defines how the OVS agent samples the datapath flows and
+// the conntrack entries
+type OvsStatisticsSpec struct {
+	// Interval is the period at which the statistics are sampled, it
+	// defaults to 30s
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Port is the port of the metrics endpoint of the agent on the DPU nodes
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=9106
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// ConnectivityCheckSpec defines the checker pods of the tenant cluster
+type ConnectivityCheckSpec struct {
+	// Image is the image of the operator, which runs the checker
+	Image string `json:"image"`
+	// Interval is the period at which each checker pod reaches the other
+	// ones and the service in front of them, it defaults to 30s
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// NodeSelector selects the tenant workers backed by a DPU. It defaults
+	// to the network.openshift.io/dpu label set by the operator.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
+
+// TokenRenewalSpec defines how the ServiceAccount token of the tenant
+// kubeconfig is renewed
+type TokenRenewalSpec struct {
+	// ExpirationSeconds is the requested lifetime of the tokens, which are
+	// renewed once 80% of it elapsed
+	// +kubebuilder:validation:Minimum=600
+	// +kubebuilder:default=3600
+	// +optional
+	ExpirationSeconds int64 `json:"expirationSeconds,omitempty"`
+}
+
+// TenantRateLimitSpec defines the client-side rate limit shared by all the
+// clients of the operator reaching the tenant cluster, the tenant syncer
+// included
+type TenantRateLimitSpec struct {
+	// QPS is the sustained number of requests per second
+	// +kubebuilder:validation:Minimum=1
+	QPS int32 `json:"qps"`
+	// Burst is the number of requests allowed above QPS for short periods
+	// +kubebuilder:validation:Minimum=1
+	Burst int32 `json:"burst"`
 }
 
 // DpuClusterConfigStatus defines the observed state of DpuClusterConfig
@@ -44,6 +727,62 @@ type DpuClusterConfigStatus struct {
 
 	// Conditions represent the latest available observations of an object's state
 	Conditions []metav1.Condition `json:"conditions"`
+	// SyncedObjects are the versions of the objects mirrored from the tenant
+	// cluster
+	// +optional
+	SyncedObjects []SyncedObjectStatus `json:"syncedObjects,omitempty"`
+	// RepresentorNetworks are the representors of the DPU nodes backing the
+	// representorNetworks of the spec
+	// +optional
+	RepresentorNetworks []RepresentorNetworkStatus `json:"representorNetworks,omitempty"`
+	// Join is the state of the bootstrap token join of new DPUs
+	// +optional
+	Join *JoinStatus `json:"join,omitempty"`
+}
+
+// JoinStatus is the state of the bootstrap token join of new DPUs
+type JoinStatus struct {
+	// TokenSecret is the Secret of the namespace holding the bootstrap
+	// kubeconfig of the DPUs
+	// +optional
+	TokenSecret string `json:"tokenSecret,omitempty"`
+	// TokenExpiration is the expiration of the current bootstrap token
+	// +optional
+	TokenExpiration *metav1.Time `json:"tokenExpiration,omitempty"`
+	// JoiningNodes are the nodes whose kubelet certificate was approved,
+	// until they are added to the MachineConfigPool
+	// +optional
+	JoiningNodes []string `json:"joiningNodes,omitempty"`
+}
+
+// RepresentorNetworkStatus maps a Multus secondary network of the tenant
+// cluster to the representors backing it on the DPU nodes
+type RepresentorNetworkStatus struct {
+	// Network is the NetworkAttachmentDefinition of the tenant cluster, as
+	// namespace/name
+	Network string `json:"network"`
+	// Representors are the names of the VF representors on the DPU nodes,
+	// e.g. pf0vf2
+	Representors []string `json:"representors"`
+	// Found tells whether the NetworkAttachmentDefinition exists in the
+	// tenant cluster
+	Found bool `json:"found"`
+}
+
+// SyncedObjectStatus is the version of an object mirrored from the tenant
+// cluster
+type SyncedObjectStatus struct {
+	// Kind is the kind of the object, ConfigMap or Secret
+	Kind string `json:"kind"`
+	// Name is the name of the object
+	Name string `json:"name"`
+	// ResourceVersion is the resourceVersion of the object in the tenant
+	// cluster
+	ResourceVersion string `json:"resourceVersion"`
+	// Hash is the SHA-256 of the data of the object
+	Hash string `json:"hash"`
+	// LastSyncTime is the time the current version was mirrored
+	LastSyncTime metav1.Time `json:"lastSyncTime"`
 }
 
 //+kubebuilder:object:root=true
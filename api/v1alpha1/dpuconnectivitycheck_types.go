@@ -0,0 +1,86 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConnectivityCheckPodToPod tells whether the checker pod of a tenant
+	// node reaches the checker pods of the other tenant nodes
+	ConnectivityCheckPodToPod = "PodToPod"
+	// ConnectivityCheckPodToService tells whether the checker pod of a
+	// tenant node reaches the ClusterIP service of the checker pods
+	ConnectivityCheckPodToService = "PodToService"
+)
+
+// ConnectivityCheckNodeStatus is the result of the checks of the checker pod
+// of a tenant node
+type ConnectivityCheckNodeStatus struct {
+	// Node is the name of the tenant node
+	Node string `json:"node"`
+	// Pod is the name of the checker pod of the node
+	Pod string `json:"pod"`
+	// Conditions are the PodToPod and PodToService results of the pod
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// DpuConnectivityCheckStatus defines the observed state of
+// DpuConnectivityCheck
+type DpuConnectivityCheckStatus struct {
+	// Nodes are the results of the checker pods, by tenant node
+	// +listType=map
+	// +listMapKey=node
+	// +optional
+	Nodes []ConnectivityCheckNodeStatus `json:"nodes,omitempty"`
+	// FailingNodes is the number of tenant nodes with a failing check
+	// +optional
+	FailingNodes int32 `json:"failingNodes,omitempty"`
+	// LastUpdateTime is the time the results last changed
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// DpuConnectivityCheck is the Schema for the dpuconnectivitychecks API. It
+// holds the results of the connectivity checks of the DpuClusterConfig of
+// the same name, and is managed by the operator.
+type DpuConnectivityCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status DpuConnectivityCheckStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DpuConnectivityCheckList contains a list of DpuConnectivityCheck
+type DpuConnectivityCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DpuConnectivityCheck `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DpuConnectivityCheck{}, &DpuConnectivityCheckList{})
+}
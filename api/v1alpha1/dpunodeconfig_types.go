@@ -0,0 +1,78 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DpuNodeConfigSpec defines the deviations of a single DPU node from the
+// DpuClusterConfig of its namespace
+type DpuNodeConfigSpec struct {
+	// HostPFRepresentor is the host PF representor added to the br-ex bridge
+	// of the DPU node by its ovnkube-node pod, when the one of the
+	// DpuClusterConfig is not found on the node
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]*$`
+	// +optional
+	HostPFRepresentor string `json:"hostPFRepresentor,omitempty"`
+	// Env are additional environment variables of the ovnkube-node
+	// container of the DPU node
+	// +optional
+	Env []DpuNodeEnvVar `json:"env,omitempty"`
+	// Zone is the OVN interconnect zone of the DPU node
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]*$`
+	// +optional
+	Zone string `json:"zone,omitempty"`
+	// Excluded keeps the ovnkube-node pods off the DPU node
+	// +optional
+	Excluded bool `json:"excluded,omitempty"`
+}
+
+// DpuNodeEnvVar is an environment variable of the ovnkube-node container
+type DpuNodeEnvVar struct {
+	// Name is the name of the variable
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+	// Value is the value of the variable
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DpuNodeConfig is the Schema for the dpunodeconfigs API. It is named after
+// the DPU node it applies to, and merged with the DpuClusterConfig of its
+// namespace when rendering the manifests of that node only.
+type DpuNodeConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DpuNodeConfigSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DpuNodeConfigList contains a list of DpuNodeConfig
+type DpuNodeConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DpuNodeConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DpuNodeConfig{}, &DpuNodeConfigList{})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DpuFunctionType is the type of the PCI functions a DPU exposes to its host
+type DpuFunctionType string
+
+const (
+	// DpuFunctionVF are SR-IOV virtual functions of the host PF
+	DpuFunctionVF DpuFunctionType = "VF"
+	// DpuFunctionSF are scalable functions of the host PF
+	DpuFunctionSF DpuFunctionType = "SF"
+)
+
+// DpuResourceSpec defines the functions the selected DPUs expose to their
+// host
+type DpuResourceSpec struct {
+	// NodeSelector selects the DPU nodes of the namespace the functions
+	// apply to, all of them when empty
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// PfID is the index of the host PF exposing the functions
+	// +kubebuilder:validation:Minimum=0
+	PfID int32 `json:"pfId"`
+	// Type is the type of the functions
+	// +kubebuilder:validation:Enum=VF;SF
+	Type DpuFunctionType `json:"type"`
+	// Count is the number of functions exposed to the host
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1024
+	Count int32 `json:"count"`
+	// Bridge is the OVS bridge of the DPU the representors of the SFs are
+	// attached to, they are left unattached when empty
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]*$`
+	// +optional
+	Bridge string `json:"bridge,omitempty"`
+}
+
+// DpuFunctionStatus is the state of a scalable function of a DPU node
+type DpuFunctionStatus struct {
+	// SfNum is the number of the SF on the host PF
+	SfNum int32 `json:"sfNum"`
+	// Representor is the netdev of the representor of the SF on the DPU
+	// +optional
+	Representor string `json:"representor,omitempty"`
+	// State is the administrative state of the SF, active or inactive
+	// +optional
+	State string `json:"state,omitempty"`
+	// OpState is the operational state of the SF, attached once the driver
+	// of the host is bound to it
+	// +optional
+	OpState string `json:"opState,omitempty"`
+	// Bridge is the OVS bridge the representor is attached to
+	// +optional
+	Bridge string `json:"bridge,omitempty"`
+}
+
+// DpuResourceNodeStatus is the state of the functions of a DPU node
+type DpuResourceNodeStatus struct {
+	// Node is the name of the DPU node
+	Node string `json:"node"`
+	// Available is the number of functions exposed to the host
+	Available int32 `json:"available"`
+	// Message tells why the functions are not all available, e.g. a
+	// reboot of the host is pending
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Functions are the SFs of the node, for the SF type
+	// +optional
+	Functions []DpuFunctionStatus `json:"functions,omitempty"`
+	// LastUpdateTime is the time the state of the node last changed
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
+
+// DpuResourceStatus defines the observed state of DpuResource
+type DpuResourceStatus struct {
+	// Nodes are the states of the selected DPU nodes, as reported by their
+	// OVS agent
+	// +listType=map
+	// +listMapKey=node
+	// +optional
+	Nodes []DpuResourceNodeStatus `json:"nodes,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// DpuResource is the Schema for the dpuresources API. It declares the VFs or
+// SFs of a PF that the DPU nodes of its namespace expose to their host, they
+// are applied by the OVS agent of the DpuClusterConfig.
+type DpuResource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DpuResourceSpec   `json:"spec,omitempty"`
+	Status DpuResourceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DpuResourceList contains a list of DpuResource
+type DpuResourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DpuResource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DpuResource{}, &DpuResourceList{})
+}
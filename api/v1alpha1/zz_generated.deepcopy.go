@@ -22,10 +22,90 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CniSpec) DeepCopyInto(out *CniSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CniSpec.
+func (in *CniSpec) DeepCopy() *CniSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CniSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckNodeStatus) DeepCopyInto(out *ConnectivityCheckNodeStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckNodeStatus.
+func (in *ConnectivityCheckNodeStatus) DeepCopy() *ConnectivityCheckNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckSpec) DeepCopyInto(out *ConnectivityCheckSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckSpec.
+func (in *ConnectivityCheckSpec) DeepCopy() *ConnectivityCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DocaTelemetrySpec) DeepCopyInto(out *DocaTelemetrySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocaTelemetrySpec.
+func (in *DocaTelemetrySpec) DeepCopy() *DocaTelemetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DocaTelemetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DpuClusterConfig) DeepCopyInto(out *DpuClusterConfig) {
 	*out = *in
@@ -88,11 +168,152 @@ func (in *DpuClusterConfigList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DpuClusterConfigSpec) DeepCopyInto(out *DpuClusterConfigSpec) {
 	*out = *in
+	if in.TokenRenewal != nil {
+		in, out := &in.TokenRenewal, &out.TokenRenewal
+		*out = new(TokenRenewalSpec)
+		**out = **in
+	}
+	if in.TenantRateLimit != nil {
+		in, out := &in.TenantRateLimit, &out.TenantRateLimit
+		*out = new(TenantRateLimitSpec)
+		**out = **in
+	}
+	if in.MasterDiscoveryTTL != nil {
+		in, out := &in.MasterDiscoveryTTL, &out.MasterDiscoveryTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]DpuPoolSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HostConfig != nil {
+		in, out := &in.HostConfig, &out.HostConfig
+		*out = new(HostConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeSync != nil {
+		in, out := &in.TimeSync, &out.TimeSync
+		*out = new(TimeSyncSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostNetwork != nil {
+		in, out := &in.HostNetwork, &out.HostNetwork
+		*out = new(HostNetworkSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HardwareOffload != nil {
+		in, out := &in.HardwareOffload, &out.HardwareOffload
+		*out = new(HardwareOffloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OvnkubeNode != nil {
+		in, out := &in.OvnkubeNode, &out.OvnkubeNode
+		*out = new(OvnkubeNodeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cni != nil {
+		in, out := &in.Cni, &out.Cni
+		*out = new(CniSpec)
+		**out = **in
+	}
+	if in.HostedCluster != nil {
+		in, out := &in.HostedCluster, &out.HostedCluster
+		*out = new(HostedClusterSpec)
+		**out = **in
+	}
+	if in.OpiBridge != nil {
+		in, out := &in.OpiBridge, &out.OpiBridge
+		*out = new(OpiBridgeSpec)
+		**out = **in
+	}
+	if in.DocaTelemetry != nil {
+		in, out := &in.DocaTelemetry, &out.DocaTelemetry
+		*out = new(DocaTelemetrySpec)
+		**out = **in
+	}
+	if in.OvsMetrics != nil {
+		in, out := &in.OvsMetrics, &out.OvsMetrics
+		*out = new(OvsMetricsSpec)
+		**out = **in
+	}
+	if in.OvsdbRelay != nil {
+		in, out := &in.OvsdbRelay, &out.OvsdbRelay
+		*out = new(OvsdbRelaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OvnTLS != nil {
+		in, out := &in.OvnTLS, &out.OvnTLS
+		*out = new(OvnTLSSpec)
+		**out = **in
+	}
+	if in.NodeIdentity != nil {
+		in, out := &in.NodeIdentity, &out.NodeIdentity
+		*out = new(NodeIdentitySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Join != nil {
+		in, out := &in.Join, &out.Join
+		*out = new(JoinSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TenantAgent != nil {
+		in, out := &in.TenantAgent, &out.TenantAgent
+		*out = new(TenantAgentSpec)
+		**out = **in
+	}
+	if in.DpuHost != nil {
+		in, out := &in.DpuHost, &out.DpuHost
+		*out = new(DpuHostSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RepresentorNetworks != nil {
+		in, out := &in.RepresentorNetworks, &out.RepresentorNetworks
+		*out = make([]RepresentorNetworkSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.OvsAgent != nil {
+		in, out := &in.OvsAgent, &out.OvsAgent
+		*out = new(OvsAgentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrainBlocker != nil {
+		in, out := &in.DrainBlocker, &out.DrainBlocker
+		*out = new(DrainBlockerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectivityCheck != nil {
+		in, out := &in.ConnectivityCheck, &out.ConnectivityCheck
+		*out = new(ConnectivityCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuClusterConfigSpec.
@@ -115,6 +336,25 @@ func (in *DpuClusterConfigStatus) DeepCopyInto(out *DpuClusterConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SyncedObjects != nil {
+		in, out := &in.SyncedObjects, &out.SyncedObjects
+		*out = make([]SyncedObjectStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RepresentorNetworks != nil {
+		in, out := &in.RepresentorNetworks, &out.RepresentorNetworks
+		*out = make([]RepresentorNetworkStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Join != nil {
+		in, out := &in.Join, &out.Join
+		*out = new(JoinStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuClusterConfigStatus.
@@ -126,3 +366,931 @@ func (in *DpuClusterConfigStatus) DeepCopy() *DpuClusterConfigStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuConnectivityCheck) DeepCopyInto(out *DpuConnectivityCheck) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuConnectivityCheck.
+func (in *DpuConnectivityCheck) DeepCopy() *DpuConnectivityCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuConnectivityCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DpuConnectivityCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuConnectivityCheckList) DeepCopyInto(out *DpuConnectivityCheckList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DpuConnectivityCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuConnectivityCheckList.
+func (in *DpuConnectivityCheckList) DeepCopy() *DpuConnectivityCheckList {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuConnectivityCheckList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DpuConnectivityCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuConnectivityCheckStatus) DeepCopyInto(out *DpuConnectivityCheckStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]ConnectivityCheckNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuConnectivityCheckStatus.
+func (in *DpuConnectivityCheckStatus) DeepCopy() *DpuConnectivityCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuConnectivityCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuFunctionStatus) DeepCopyInto(out *DpuFunctionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuFunctionStatus.
+func (in *DpuFunctionStatus) DeepCopy() *DpuFunctionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuFunctionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuHostSpec) DeepCopyInto(out *DpuHostSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ManagementPort != nil {
+		in, out := &in.ManagementPort, &out.ManagementPort
+		*out = new(ManagementPortSpec)
+		**out = **in
+	}
+	if in.NodeAnnotations != nil {
+		in, out := &in.NodeAnnotations, &out.NodeAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuHostSpec.
+func (in *DpuHostSpec) DeepCopy() *DpuHostSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuHostSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuNodeConfig) DeepCopyInto(out *DpuNodeConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuNodeConfig.
+func (in *DpuNodeConfig) DeepCopy() *DpuNodeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuNodeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DpuNodeConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuNodeConfigList) DeepCopyInto(out *DpuNodeConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DpuNodeConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuNodeConfigList.
+func (in *DpuNodeConfigList) DeepCopy() *DpuNodeConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuNodeConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DpuNodeConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuNodeConfigSpec) DeepCopyInto(out *DpuNodeConfigSpec) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]DpuNodeEnvVar, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuNodeConfigSpec.
+func (in *DpuNodeConfigSpec) DeepCopy() *DpuNodeConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuNodeConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuNodeEnvVar) DeepCopyInto(out *DpuNodeEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuNodeEnvVar.
+func (in *DpuNodeEnvVar) DeepCopy() *DpuNodeEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuNodeEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuPoolSpec) DeepCopyInto(out *DpuPoolSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuPoolSpec.
+func (in *DpuPoolSpec) DeepCopy() *DpuPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuResource) DeepCopyInto(out *DpuResource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuResource.
+func (in *DpuResource) DeepCopy() *DpuResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DpuResource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuResourceList) DeepCopyInto(out *DpuResourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DpuResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuResourceList.
+func (in *DpuResourceList) DeepCopy() *DpuResourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuResourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DpuResourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuResourceNodeStatus) DeepCopyInto(out *DpuResourceNodeStatus) {
+	*out = *in
+	if in.Functions != nil {
+		in, out := &in.Functions, &out.Functions
+		*out = make([]DpuFunctionStatus, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuResourceNodeStatus.
+func (in *DpuResourceNodeStatus) DeepCopy() *DpuResourceNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuResourceNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuResourceSpec) DeepCopyInto(out *DpuResourceSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuResourceSpec.
+func (in *DpuResourceSpec) DeepCopy() *DpuResourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuResourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DpuResourceStatus) DeepCopyInto(out *DpuResourceStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]DpuResourceNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DpuResourceStatus.
+func (in *DpuResourceStatus) DeepCopy() *DpuResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DpuResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainBlockerSpec) DeepCopyInto(out *DrainBlockerSpec) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainBlockerSpec.
+func (in *DrainBlockerSpec) DeepCopy() *DrainBlockerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainBlockerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareOffloadSpec) DeepCopyInto(out *HardwareOffloadSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxIdle != nil {
+		in, out := &in.MaxIdle, &out.MaxIdle
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OtherConfig != nil {
+		in, out := &in.OtherConfig, &out.OtherConfig
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareOffloadSpec.
+func (in *HardwareOffloadSpec) DeepCopy() *HardwareOffloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareOffloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostConfigSpec) DeepCopyInto(out *HostConfigSpec) {
+	*out = *in
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UdevRules != nil {
+		in, out := &in.UdevRules, &out.UdevRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostConfigSpec.
+func (in *HostConfigSpec) DeepCopy() *HostConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostInterfaceSpec) DeepCopyInto(out *HostInterfaceSpec) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]StaticRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostInterfaceSpec.
+func (in *HostInterfaceSpec) DeepCopy() *HostInterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostInterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostNetworkSpec) DeepCopyInto(out *HostNetworkSpec) {
+	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]HostInterfaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostNetworkSpec.
+func (in *HostNetworkSpec) DeepCopy() *HostNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostedClusterSpec) DeepCopyInto(out *HostedClusterSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostedClusterSpec.
+func (in *HostedClusterSpec) DeepCopy() *HostedClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostedClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JoinSpec) DeepCopyInto(out *JoinSpec) {
+	*out = *in
+	if in.TokenTTL != nil {
+		in, out := &in.TokenTTL, &out.TokenTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JoinSpec.
+func (in *JoinSpec) DeepCopy() *JoinSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JoinSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JoinStatus) DeepCopyInto(out *JoinStatus) {
+	*out = *in
+	if in.TokenExpiration != nil {
+		in, out := &in.TokenExpiration, &out.TokenExpiration
+		*out = (*in).DeepCopy()
+	}
+	if in.JoiningNodes != nil {
+		in, out := &in.JoiningNodes, &out.JoiningNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JoinStatus.
+func (in *JoinStatus) DeepCopy() *JoinStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JoinStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementPortSpec) DeepCopyInto(out *ManagementPortSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementPortSpec.
+func (in *ManagementPortSpec) DeepCopy() *ManagementPortSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementPortSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeIdentitySpec) DeepCopyInto(out *NodeIdentitySpec) {
+	*out = *in
+	if in.CertDuration != nil {
+		in, out := &in.CertDuration, &out.CertDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeIdentitySpec.
+func (in *NodeIdentitySpec) DeepCopy() *NodeIdentitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeIdentitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpiBridgeSpec) DeepCopyInto(out *OpiBridgeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpiBridgeSpec.
+func (in *OpiBridgeSpec) DeepCopy() *OpiBridgeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpiBridgeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvnTLSSpec) DeepCopyInto(out *OvnTLSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvnTLSSpec.
+func (in *OvnTLSSpec) DeepCopy() *OvnTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OvnTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvnkubeNodeSpec) DeepCopyInto(out *OvnkubeNodeSpec) {
+	*out = *in
+	if in.HostNetwork != nil {
+		in, out := &in.HostNetwork, &out.HostNetwork
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HostPID != nil {
+		in, out := &in.HostPID, &out.HostPID
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvnkubeNodeSpec.
+func (in *OvnkubeNodeSpec) DeepCopy() *OvnkubeNodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OvnkubeNodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvsAgentSpec) DeepCopyInto(out *OvsAgentSpec) {
+	*out = *in
+	if in.FlowLimit != nil {
+		in, out := &in.FlowLimit, &out.FlowLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Statistics != nil {
+		in, out := &in.Statistics, &out.Statistics
+		*out = new(OvsStatisticsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvsAgentSpec.
+func (in *OvsAgentSpec) DeepCopy() *OvsAgentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OvsAgentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvsMetricsSpec) DeepCopyInto(out *OvsMetricsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvsMetricsSpec.
+func (in *OvsMetricsSpec) DeepCopy() *OvsMetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OvsMetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvsStatisticsSpec) DeepCopyInto(out *OvsStatisticsSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvsStatisticsSpec.
+func (in *OvsStatisticsSpec) DeepCopy() *OvsStatisticsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OvsStatisticsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvsdbRelaySpec) DeepCopyInto(out *OvsdbRelaySpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvsdbRelaySpec.
+func (in *OvsdbRelaySpec) DeepCopy() *OvsdbRelaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OvsdbRelaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationSpec) DeepCopyInto(out *RemediationSpec) {
+	*out = *in
+	if in.UnreachableTimeout != nil {
+		in, out := &in.UnreachableTimeout, &out.UnreachableTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	out.Template = in.Template
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationSpec.
+func (in *RemediationSpec) DeepCopy() *RemediationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationTemplateReference) DeepCopyInto(out *RemediationTemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationTemplateReference.
+func (in *RemediationTemplateReference) DeepCopy() *RemediationTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepresentorNetworkSpec) DeepCopyInto(out *RepresentorNetworkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepresentorNetworkSpec.
+func (in *RepresentorNetworkSpec) DeepCopy() *RepresentorNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepresentorNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepresentorNetworkStatus) DeepCopyInto(out *RepresentorNetworkStatus) {
+	*out = *in
+	if in.Representors != nil {
+		in, out := &in.Representors, &out.Representors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepresentorNetworkStatus.
+func (in *RepresentorNetworkStatus) DeepCopy() *RepresentorNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepresentorNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticRoute) DeepCopyInto(out *StaticRoute) {
+	*out = *in
+	if in.Metric != nil {
+		in, out := &in.Metric, &out.Metric
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticRoute.
+func (in *StaticRoute) DeepCopy() *StaticRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncedObjectStatus) DeepCopyInto(out *SyncedObjectStatus) {
+	*out = *in
+	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncedObjectStatus.
+func (in *SyncedObjectStatus) DeepCopy() *SyncedObjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncedObjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAgentSpec) DeepCopyInto(out *TenantAgentSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAgentSpec.
+func (in *TenantAgentSpec) DeepCopy() *TenantAgentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAgentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantRateLimitSpec) DeepCopyInto(out *TenantRateLimitSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantRateLimitSpec.
+func (in *TenantRateLimitSpec) DeepCopy() *TenantRateLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantRateLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeSyncSpec) DeepCopyInto(out *TimeSyncSpec) {
+	*out = *in
+	if in.NTPServers != nil {
+		in, out := &in.NTPServers, &out.NTPServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PTPDomain != nil {
+		in, out := &in.PTPDomain, &out.PTPDomain
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeSyncSpec.
+func (in *TimeSyncSpec) DeepCopy() *TimeSyncSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeSyncSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenRenewalSpec) DeepCopyInto(out *TokenRenewalSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenRenewalSpec.
+func (in *TokenRenewalSpec) DeepCopy() *TokenRenewalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenRenewalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// dpf-migrate converts NVIDIA DPF objects into DpuClusterConfigs
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/dpu-network-operator/pkg/dpf"
+)
+
+func main() {
+	var file string
+	opts := dpf.Options{}
+	flag.StringVar(&file, "f", "-", "The file holding the DPF objects, - for stdin.")
+	flag.StringVar(&opts.PoolName, "pool-name", "dpu", "The MachineConfigPool of the DPU nodes.")
+	flag.StringVar(&opts.KubeConfigFile, "kubeconfig-secret", "", "The secret name of the tenant cluster kubeconfig.")
+	flag.StringVar(&opts.Namespace, "namespace", "", "The namespace of the generated objects, defaults to the one of the DPF objects.")
+	flag.Parse()
+
+	if err := run(file, opts, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(file string, opts dpf.Options, out io.Writer) error {
+	in := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	objs, err := dpf.ReadObjects(in)
+	if err != nil {
+		return err
+	}
+	cfgs, warnings, err := dpf.Convert(objs, opts)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range cfgs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cfg)
+		if err != nil {
+			return err
+		}
+		// Leave out the fields filled by the API server
+		delete(u, "status")
+		unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "---\n%s", b)
+	}
+	return nil
+}
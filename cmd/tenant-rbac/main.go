@@ -0,0 +1,78 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// tenant-rbac creates the least-privileged identity of the operator in the
+// tenant cluster and prints a kubeconfig authenticating as it
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/dpu-network-operator/pkg/tenantrbac"
+)
+
+func main() {
+	var kubeconfig, namespace string
+	var dryRun bool
+	flag.StringVar(&kubeconfig, "kubeconfig", os.Getenv("KUBECONFIG"), "The admin kubeconfig of the tenant cluster.")
+	flag.StringVar(&namespace, "namespace", "openshift-ovn-kubernetes", "The namespace of ovn-kubernetes in the tenant cluster, TENANT_NAMESPACE of the operator.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print the objects instead of creating them.")
+	flag.Parse()
+
+	if err := run(kubeconfig, namespace, dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(kubeconfig, namespace string, dryRun bool) error {
+	if dryRun {
+		for _, obj := range tenantrbac.Objects(namespace) {
+			b, err := yaml.Marshal(obj)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("---\n%s", b)
+		}
+		return nil
+	}
+
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return err
+	}
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := tenantrbac.Apply(ctx, c, namespace); err != nil {
+		return err
+	}
+	b, err := tenantrbac.Kubeconfig(ctx, c, cfg, namespace)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
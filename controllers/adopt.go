@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+// adoptDaemonSet prepares the adoption of a DaemonSet created outside of the
+// operator, e.g. by a manual install or a previous operator version, before
+// the rendered one in obj is applied. Applying obj takes the ownership of the
+// existing DaemonSet, except when its selector differs from the rendered one,
+// which cannot be updated, in which case it is deleted to be recreated. The
+// same goes for a DaemonSet already controlled by cfg. A DaemonSet controlled
+// by another existing DpuClusterConfig is not adopted.
+func (r *DpuClusterConfigReconciler) adoptDaemonSet(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, obj *unstructured.Unstructured) error {
+	existing := &appsv1.DaemonSet{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	rendered := &appsv1.DaemonSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, rendered); err != nil {
+		return err
+	}
+	sameSelector := equality.Semantic.DeepEqual(existing.Spec.Selector, rendered.Spec.Selector)
+	owner := metav1.GetControllerOf(existing)
+	if owner != nil && owner.UID == cfg.UID {
+		if sameSelector {
+			return nil
+		}
+		// e.g. the pools got distinct selectors
+		logger.Info("The selector of the DaemonSet changed, recreate it", "namespace", existing.Namespace, "name", existing.Name)
+		return client.IgnoreNotFound(r.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+	}
+	if owner != nil && owner.Kind == "DpuClusterConfig" && owner.Name != cfg.Name {
+		other := &dpuv1alpha1.DpuClusterConfig{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: owner.Name}, other)
+		if err == nil && other.UID == owner.UID {
+			return fmt.Errorf("DaemonSet %s/%s is controlled by DpuClusterConfig %s", existing.Namespace, existing.Name, owner.Name)
+		}
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if sameSelector {
+		logger.Info("Adopt existing DaemonSet", "namespace", existing.Namespace, "name", existing.Name)
+		return nil
+	}
+	logger.Info("Existing DaemonSet has a different selector, recreate it", "namespace", existing.Namespace, "name", existing.Name)
+	return client.IgnoreNotFound(r.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openshift/dpu-network-operator/api"
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+const (
+	// certificateExpiryWarning is how long before their expiry the
+	// certificates are reported as expiring
+	certificateExpiryWarning = 14 * 24 * time.Hour
+
+	tenantKubeconfigCertificate = "tenant-kubeconfig"
+	ovnCertCertificate          = "ovn-cert"
+)
+
+var certificateExpiry = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "dpu_operator_certificate_expiry_timestamp_seconds",
+		Help: "Expiry time of the client certificates used to reach the tenant clusters, in seconds since the epoch.",
+	},
+	[]string{"namespace", "certificate"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(certificateExpiry)
+}
+
+// checkCertificateExpiry publishes the expiry time of the client certificate
+// of the tenant kubeconfig and of the ovn-cert synced from the tenant cluster,
+// and sets the CertificateExpiring condition of cfg when one of them expires
+// within certificateExpiryWarning. It returns how long until the condition
+// needs to be checked again, 0 if no certificate is found.
+func (r *DpuClusterConfigReconciler) checkCertificateExpiry(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) time.Duration {
+	expiries := map[string]time.Time{}
+	if notAfter, err := r.getTenantKubeconfigCertificateExpiry(ctx, cfg); err != nil {
+		logger.Error(err, "Fail to read the client certificate of the tenant kubeconfig")
+	} else if !notAfter.IsZero() {
+		expiries[tenantKubeconfigCertificate] = notAfter
+	}
+	if notAfter, err := r.getOvnCertExpiry(ctx, cfg.Namespace); err != nil {
+		logger.Error(err, "Fail to read the ovn-cert certificate")
+	} else if !notAfter.IsZero() {
+		expiries[ovnCertCertificate] = notAfter
+	}
+
+	var soonest string
+	for name, notAfter := range expiries {
+		certificateExpiry.WithLabelValues(cfg.Namespace, name).Set(float64(notAfter.Unix()))
+		if soonest == "" || notAfter.Before(expiries[soonest]) {
+			soonest = name
+		}
+	}
+	if soonest == "" {
+		meta.RemoveStatusCondition(&cfg.Status.Conditions, api.CertificateExpiring)
+		return 0
+	}
+
+	notAfter := expiries[soonest]
+	untilWarning := time.Until(notAfter.Add(-certificateExpiryWarning))
+	if untilWarning > 0 {
+		meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().NotCertificateExpiring().Reason(api.ReasonValid).Build())
+		return untilWarning
+	}
+	msg := fmt.Sprintf("certificate %s expires at %s", soonest, notAfter.UTC().Format(time.RFC3339))
+	meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().CertificateExpiring().Reason(api.ReasonExpiringSoon).Msg(msg).Build())
+	if untilExpiry := time.Until(notAfter); untilExpiry > 0 {
+		return untilExpiry
+	}
+	return 0
+}
+
+// getTenantKubeconfigCertificateExpiry returns the expiry time of the client
+// certificate of the current context of the tenant kubeconfig. The zero time
+// is returned when it authenticates otherwise, e.g. with a token.
+func (r *DpuClusterConfigReconciler) getTenantKubeconfigCertificateExpiry(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (time.Time, error) {
+	bytes, _, err := r.getTenantKubeconfig(ctx, cfg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	kubeconfig, err := clientcmd.Load(bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	kubeContext, ok := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if !ok {
+		return time.Time{}, nil
+	}
+	authInfo, ok := kubeconfig.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return time.Time{}, nil
+	}
+	certData := authInfo.ClientCertificateData
+	if len(certData) == 0 && authInfo.ClientCertificate != "" {
+		if certData, err = os.ReadFile(authInfo.ClientCertificate); err != nil {
+			return time.Time{}, err
+		}
+	}
+	if len(certData) == 0 {
+		return time.Time{}, nil
+	}
+	return certificateExpiryTime(certData)
+}
+
+// getOvnCertExpiry returns the expiry time of the ovn-cert secret synced
+// from the tenant cluster, the zero time when it is not synced yet
+func (r *DpuClusterConfigReconciler) getOvnCertExpiry(ctx context.Context, namespace string) (time.Time, error) {
+	s := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: utils.SecretNameOvnCert}, s); err != nil {
+		return time.Time{}, client.IgnoreNotFound(err)
+	}
+	certData, ok := s.Data[corev1.TLSCertKey]
+	if !ok {
+		return time.Time{}, nil
+	}
+	return certificateExpiryTime(certData)
+}
+
+// certificateExpiryTime returns the expiry time of the first certificate of
+// the PEM data
+func certificateExpiryTime(data []byte) (time.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, fmt.Errorf("no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// deleteCertificateExpiryMetrics drops the metrics of the certificates of the
+// namespace once its DpuClusterConfig is deleted
+func deleteCertificateExpiryMetrics(namespace string) {
+	certificateExpiry.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"github.com/openshift/cluster-network-operator/pkg/render"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+const (
+	defaultCniBinDir   = "/var/lib/cni/bin"
+	defaultCniConfDir  = "/etc/cni/net.d"
+	defaultCniLogLevel = 4
+)
+
+// managesCni returns whether the ovnkube-node pods of cfg install the CNI
+// plugin and configuration of ovn-kubernetes on the DPU nodes. ovn-controller
+// alone has no use for them.
+func managesCni(cfg *dpuv1alpha1.DpuClusterConfig) bool {
+	return cfg.Spec.Cni != nil && cfg.Spec.DeploymentMode != dpuv1alpha1.DeploymentModeOvnControllerOnly
+}
+
+// setCniRenderData sets the CNI settings of cfg in the render data of the
+// ovnkube-node manifests, with their defaults
+func setCniRenderData(cfg *dpuv1alpha1.DpuClusterConfig, data *render.RenderData) {
+	data.Data["Cni"] = managesCni(cfg)
+	data.Data["CniBinDir"] = defaultCniBinDir
+	data.Data["CniConfDir"] = defaultCniConfDir
+	data.Data["CniLogLevel"] = int32(defaultCniLogLevel)
+	if !managesCni(cfg) {
+		return
+	}
+	if cfg.Spec.Cni.BinDir != "" {
+		data.Data["CniBinDir"] = cfg.Spec.Cni.BinDir
+	}
+	if cfg.Spec.Cni.ConfDir != "" {
+		data.Data["CniConfDir"] = cfg.Spec.Cni.ConfDir
+	}
+	if cfg.Spec.Cni.LogLevel != 0 {
+		data.Data["CniLogLevel"] = cfg.Spec.Cni.LogLevel
+	}
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+// setCommonMetadata adds the common labels and annotations of cfg to obj,
+// without overriding the ones set by the operator. Labels and annotations
+// removed from cfg are left on the objects.
+func setCommonMetadata(cfg *dpuv1alpha1.DpuClusterConfig, obj metav1.Object) {
+	if cfg == nil {
+		return
+	}
+	obj.SetLabels(mergeStringMap(obj.GetLabels(), cfg.Spec.CommonLabels, false))
+	obj.SetAnnotations(mergeStringMap(obj.GetAnnotations(), cfg.Spec.CommonAnnotations, false))
+}
+
+// syncMetadata copies the labels and annotations of expected into found and
+// returns whether found changed
+func syncMetadata(expected, found metav1.Object) bool {
+	changed := !isSubset(expected.GetLabels(), found.GetLabels()) || !isSubset(expected.GetAnnotations(), found.GetAnnotations())
+	if changed {
+		found.SetLabels(mergeStringMap(found.GetLabels(), expected.GetLabels(), true))
+		found.SetAnnotations(mergeStringMap(found.GetAnnotations(), expected.GetAnnotations(), true))
+	}
+	return changed
+}
+
+func mergeStringMap(dst, src map[string]string, override bool) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for k, v := range src {
+		if _, ok := dst[k]; ok && !override {
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+func isSubset(sub, m map[string]string) bool {
+	for k, v := range sub {
+		if w, ok := m[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
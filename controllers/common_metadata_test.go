@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// failingGetClient fails the reads of the MachineConfigPools
+type failingGetClient struct {
+	client.Client
+}
+
+func (c *failingGetClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if _, ok := obj.(*mcfgv1.MachineConfigPool); ok {
+		return fmt.Errorf("connection refused")
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func TestSyncMachineConfigPoolKeepsMetadata(t *testing.T) {
+	g := NewWithT(t)
+	chdirRepoRoot(t)
+	ctx := context.Background()
+	cfg := newTestConfig()
+	r := &DpuClusterConfigReconciler{Client: newFakeClient()}
+	g.Expect(r.syncMachineConfigObjs(ctx, cfg)).To(Succeed())
+
+	// Other tools label and annotate the MachineConfig and the
+	// MachineConfigPool
+	mc := &mcfgv1.MachineConfig{}
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: machineConfigName(cfg)}, mc)).To(Succeed())
+	metav1.SetMetaDataLabel(&mc.ObjectMeta, "gitops.example.com/owner", "team-a")
+	mc.Annotations = map[string]string{"policy.example.com/checked": "true"}
+	g.Expect(r.Update(ctx, mc)).To(Succeed())
+	mcp := &mcfgv1.MachineConfigPool{}
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: cfg.Spec.PoolName}, mcp)).To(Succeed())
+	metav1.SetMetaDataLabel(&mcp.ObjectMeta, "gitops.example.com/owner", "team-a")
+	g.Expect(r.Update(ctx, mcp)).To(Succeed())
+
+	// A new common label updates both, keeping the metadata of the others
+	cfg.Spec.CommonLabels = map[string]string{"cost-center": "dpu"}
+	g.Expect(r.syncMachineConfigObjs(ctx, cfg)).To(Succeed())
+
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: machineConfigName(cfg)}, mc)).To(Succeed())
+	g.Expect(mc.Labels).To(HaveKeyWithValue("gitops.example.com/owner", "team-a"))
+	g.Expect(mc.Labels).To(HaveKeyWithValue("cost-center", "dpu"))
+	g.Expect(mc.Labels).To(HaveKeyWithValue(mcfgv1.MachineConfigRoleLabelKey, cfg.Spec.PoolName))
+	g.Expect(mc.Annotations).To(HaveKeyWithValue("policy.example.com/checked", "true"))
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: cfg.Spec.PoolName}, mcp)).To(Succeed())
+	g.Expect(mcp.Labels).To(HaveKeyWithValue("gitops.example.com/owner", "team-a"))
+	g.Expect(mcp.Labels).To(HaveKeyWithValue("cost-center", "dpu"))
+
+	// A change of the rendered config keeps them as well
+	mc.Spec.Config.Raw = []byte(`{"ignition":{"version":"3.2.0"}}`)
+	g.Expect(r.Update(ctx, mc)).To(Succeed())
+	g.Expect(r.syncMachineConfigObjs(ctx, cfg)).To(Succeed())
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: machineConfigName(cfg)}, mc)).To(Succeed())
+	g.Expect(string(mc.Spec.Config.Raw)).NotTo(Equal(`{"ignition":{"version":"3.2.0"}}`))
+	g.Expect(mc.Labels).To(HaveKeyWithValue("gitops.example.com/owner", "team-a"))
+}
+
+func TestSyncMachineConfigPoolGetError(t *testing.T) {
+	g := NewWithT(t)
+	chdirRepoRoot(t)
+	cfg := newTestConfig()
+	r := &DpuClusterConfigReconciler{Client: &failingGetClient{Client: newFakeClient()}}
+
+	g.Expect(r.syncMachineConfigObjs(context.Background(), cfg)).To(MatchError(ContainSubstring("connection refused")))
+	// Nothing is created while the pool cannot be read
+	mcs := &mcfgv1.MachineConfigList{}
+	g.Expect(r.List(context.Background(), mcs)).To(Succeed())
+	g.Expect(mcs.Items).To(BeEmpty())
+}
@@ -19,68 +19,164 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"net"
 	"os"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/k8snetworkplumbingwg/sriov-network-operator/pkg/apply"
 	"github.com/openshift/cluster-network-operator/pkg/render"
 	appsv1 "k8s.io/api/apps/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	mcrender "github.com/k8snetworkplumbingwg/sriov-network-operator/pkg/render"
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
 	"github.com/openshift/dpu-network-operator/api"
 	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/images"
+	"github.com/openshift/dpu-network-operator/pkg/metrics"
 	syncer "github.com/openshift/dpu-network-operator/pkg/ovnkube-syncer"
+	"github.com/openshift/dpu-network-operator/pkg/supervisor"
+	"github.com/openshift/dpu-network-operator/pkg/tracing"
 	"github.com/openshift/dpu-network-operator/pkg/utils"
 )
 
 const (
+	// dpuMcRole is the MachineConfig role selected by the main pool on top
+	// of its own, the role of the MachineConfig of the main pool before the
+	// pools got their own roles. The MachineConfigs of the users labelled
+	// with it keep being rendered in the main pool.
 	dpuMcRole = "dpu-worker"
+	// dpuClusterConfigUIDLabel identifies the DpuClusterConfig of the
+	// cluster-scoped objects, which cannot be owned by it
+	dpuClusterConfigUIDLabel = "dpu.openshift.io/dpuclusterconfig-uid"
+	// defaultPriorityClassName is the PriorityClass of the DPU-critical pods
+	// when the DpuClusterConfig sets none
+	defaultPriorityClassName = "system-node-critical"
 )
 
 var logger = log.Log.WithName("controller_dpuclusterconfig")
 
+var sccGVK = schema.GroupVersionKind{
+	Group:   "security.openshift.io",
+	Version: "v1",
+	Kind:    "SecurityContextConstraints",
+}
+
 const (
 	OVN_NB_PORT = "9641"
 	OVN_SB_PORT = "9642"
+
+	kubeconfigFileResyncPeriod = 5 * time.Minute
+	tenantDiscoveryRetryPeriod = 30 * time.Second
 )
 
+// LocalOvnkubeConfig locates the ovnkube-node DaemonSet of the infra cluster,
+// whose image is used for the ovnkube-node pods of the DPUs by default
+type LocalOvnkubeConfig struct {
+	Namespace string `envconfig:"LOCAL_OVNKUBE_NAMESPACE" default:"openshift-ovn-kubernetes"`
+	DaemonSet string `envconfig:"LOCAL_OVNKUBE_DAEMONSET" default:"ovnkube-node"`
+}
+
 // DpuClusterConfigReconciler reconciles a DpuClusterConfig object
 type DpuClusterConfigReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme        *runtime.Scheme
+	Platform      *utils.Platform
+	TenantConfigs *utils.TenantRestConfigStore
+	Images        *images.Resolver
+	LocalOvnkube  LocalOvnkubeConfig
+	// Recorder records the writes to the tenant cluster as events when set
+	Recorder record.EventRecorder
+	// ResyncPeriod is the period of the full resync of the DpuClusterConfigs,
+	// which re-renders and re-applies their objects to revert out-of-band
+	// edits. It is disabled when 0.
+	ResyncPeriod time.Duration
+	// requeue decides when the reconciles are retried
+	requeue *requeuePolicy
+	// syncers holds the tenant syncer of each namespace with a DpuClusterConfig
+	syncers map[string]*tenantSyncer
+	// tenantEvents reconciles a DpuClusterConfig when the background tasks
+	// of its tenant syncer see a change, e.g. of the reachability of the
+	// tenant cluster
+	tenantEvents chan event.GenericEvent
+}
+
+// tenantSyncer is the ovnkube syncer of a single tenant cluster
+type tenantSyncer struct {
 	syncer *syncer.OvnkubeSyncer
 	stopCh chan struct{}
+	// task restarts the syncer when it fails to start
+	task *supervisor.Task
+	// kubeconfigVersion identifies the tenant kubeconfig the syncer was
+	// started with
+	kubeconfigVersion string
+	// tenantNamespace is the namespace the syncer was started with
+	tenantNamespace string
+	// rateLimit is the tenant rate limit the syncer was started with
+	rateLimit dpuv1alpha1.TenantRateLimitSpec
+	// tokenRenewal renews the ServiceAccount token of the tenant
+	// kubeconfig, it is nil when the token is not renewed
+	tokenRenewal *supervisor.Task
+	// heartbeat probes the API server of the tenant cluster
+	heartbeat *supervisor.Task
+	// masters caches the ovnkube-master pods of the tenant cluster, it is
+	// nil for a hosted cluster
+	masters *masterDiscovery
+	// network caches the network operator config of the tenant cluster
+	network *tenantNetwork
 }
 
 //+kubebuilder:rbac:groups=dpu.openshift.io,resources=dpuclusterconfigs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=dpu.openshift.io,resources=dpuclusterconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=dpu.openshift.io,resources=dpuclusterconfigs/finalizers,verbs=update
+//+kubebuilder:rbac:groups=dpu.openshift.io,resources=dpunodeconfigs,verbs=get;list;watch;create
 //+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigpools,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigs,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=anyuid;hostnetwork,verbs=use
+//+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=hostnetwork;hostnetwork-v2,verbs=use
+//+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=config.openshift.io,resources=imagedigestmirrorsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operator.openshift.io,resources=imagecontentsourcepolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get;list;watch
+//+kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch
+//+kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/approval,verbs=update
+//+kubebuilder:rbac:groups=certificates.k8s.io,resources=signers,resourceNames=kubernetes.io/kube-apiserver-client-kubelet;kubernetes.io/kubelet-serving,verbs=approve
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,resourceNames=system:node-bootstrapper,verbs=bind
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -92,7 +188,13 @@ type DpuClusterConfigReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.9.2/pkg/reconcile
 func (r *DpuClusterConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	var err error
+	ctx, span := tracing.Start(ctx, "reconcile", "controller", "DpuClusterConfig", "request", req.NamespacedName)
+	result, err := r.reconcile(ctx, req)
+	span.End(err)
+	return result, err
+}
+
+func (r *DpuClusterConfigReconciler) reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	logger := log.FromContext(ctx).WithValues("reconcile DpuClusterConfig", req.NamespacedName)
 	logger.Info("Reconcile")
 	dpuClusterConfig := &dpuv1alpha1.DpuClusterConfig{}
@@ -100,42 +202,139 @@ func (r *DpuClusterConfigReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	cfgList := &dpuv1alpha1.DpuClusterConfigList{}
 	err = r.List(ctx, cfgList, &client.ListOptions{Namespace: req.Namespace})
 	if err != nil {
-		return ctrl.Result{}, err
+		return r.requeue.Retry(req, err)
 	}
 	if len(cfgList.Items) > 1 {
-		logger.Error(fmt.Errorf("more than one DpuClusterConfig CR is found in"), "namespace", req.Namespace)
-		return ctrl.Result{}, err
+		return r.requeue.Terminal(req, fmt.Errorf("more than one DpuClusterConfig CR is found in namespace %s", req.Namespace))
 	} else if len(cfgList.Items) == 1 {
 		dpuClusterConfig = &cfgList.Items[0]
 
+		// The status is only written when it changed, and a status that
+		// cannot be written makes the reconcile fail so that it is retried
+		original := dpuClusterConfig.DeepCopy()
 		defer func() {
-			if err := r.Status().Update(context.TODO(), dpuClusterConfig); err != nil {
-				logger.Error(err, "unable to update DpuClusterConfig status")
+			if statusErr := r.patchStatus(context.TODO(), original, dpuClusterConfig.Status); statusErr != nil {
+				logger.Error(statusErr, "unable to update DpuClusterConfig status")
+				if err == nil {
+					err = statusErr
+				}
 			}
 		}()
 
+		if err = r.syncNetworkPolicies(ctx); err != nil {
+			logger.Error(err, "Fail to sync the network policies")
+			return r.requeue.Retry(req, err)
+		}
+
 		if dpuClusterConfig.Spec.PoolName == "" {
 			logger.Info("poolName is not provided")
-			return ctrl.Result{}, nil
+			return r.requeue.Done(req)
 		} else {
-			err = r.syncMachineConfigObjs(dpuClusterConfig.Spec)
-			if err != nil {
+			// An invalid pool name cannot be fixed by retrying, so the
+			// DpuClusterConfig is marked degraded until its spec changes
+			if err := validatePools(dpuClusterConfig); err != nil {
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().Degraded().Reason(api.ReasonInvalidPoolName).Msg(err.Error()).Build())
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotMcpReady().Reason(api.ReasonInvalidPoolName).Msg(err.Error()).Build())
+				return r.requeue.Terminal(req, err)
+			}
+			if err := validateHardwareOffload(dpuClusterConfig); err != nil {
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().Degraded().Reason(api.ReasonInvalidOvsOption).Msg(err.Error()).Build())
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotMcpReady().Reason(api.ReasonInvalidOvsOption).Msg(err.Error()).Build())
+				return r.requeue.Terminal(req, err)
+			}
+			if err := validateHostNetwork(dpuClusterConfig); err != nil {
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().Degraded().Reason(api.ReasonInvalidHostNetwork).Msg(err.Error()).Build())
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotMcpReady().Reason(api.ReasonInvalidHostNetwork).Msg(err.Error()).Build())
+				return r.requeue.Terminal(req, err)
+			}
+			if err := validateJoin(dpuClusterConfig); err != nil {
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().Degraded().Reason(api.ReasonInvalidJoin).Msg(err.Error()).Build())
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotMcpReady().Reason(api.ReasonInvalidJoin).Msg(err.Error()).Build())
+				return r.requeue.Terminal(req, err)
+			}
+			meta.RemoveStatusCondition(&dpuClusterConfig.Status.Conditions, api.Degraded)
+		}
+		// In dry run, the manifests are rendered once the tenant syncer is
+		// started, instead of being applied
+		var joinPeriod time.Duration
+		if utils.SimulateHardware {
+			logger.Info("DPU hardware is simulated, skip the NIC configuration")
+			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().McpReady().Reason(api.ReasonSimulated).Build())
+		} else if !isDryRun(dpuClusterConfig) {
+			if r.Platform.HasMachineConfig() {
+				err = r.syncMachineConfigObjs(ctx, dpuClusterConfig)
+			} else {
+				err = r.syncSwitchdevDaemonSet(ctx, dpuClusterConfig)
+			}
+			var poolConflict *poolConflictError
+			if stderrors.As(err, &poolConflict) {
+				// The pool is left to the other DpuClusterConfig until it
+				// is deleted or the pool is renamed, which is not watched
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().Degraded().Reason(api.ReasonPoolConflict).Msg(err.Error()).Build())
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotMcpReady().Reason(api.ReasonPoolConflict).Msg(err.Error()).Build())
+				return r.requeue.Wait(req, err.Error())
+			} else if err != nil {
 				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotMcpReady().Reason(api.ReasonFailedCreated).Msg(err.Error()).Build())
-				return ctrl.Result{}, err
+				return r.requeue.Retry(req, err)
 			}
 			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().McpReady().Reason(api.ReasonCreated).Build())
+			if r.Platform.HasMachineConfig() {
+				msg, err := r.migratePool(ctx, dpuClusterConfig)
+				if err != nil {
+					meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotMcpReady().Reason(api.ReasonPoolMigrating).Msg(err.Error()).Build())
+					return r.requeue.Retry(req, err)
+				}
+				// The MachineConfigPools and the nodes are not watched
+				if msg != "" {
+					meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotMcpReady().Reason(api.ReasonPoolMigrating).Msg(msg).Build())
+					return r.requeue.Poll(req, poolMigrationPollPeriod)
+				}
+			}
+			// The pool is ready to take the joining DPUs
+			if joinPeriod, err = r.syncJoin(ctx, dpuClusterConfig); err != nil {
+				return r.requeue.Retry(req, err)
+			}
 		}
 
-		if dpuClusterConfig.Spec.KubeConfigFile == "" {
+		if dpuClusterConfig.Spec.KubeConfigFile == "" && dpuClusterConfig.Spec.KubeConfigPath == "" {
 			logger.Info("kubeconfig of tenant cluster is not provided")
-			return ctrl.Result{}, nil
+			if isDryRun(dpuClusterConfig) {
+				if err = r.syncDryRun(ctx, dpuClusterConfig); err != nil {
+					return r.requeue.Retry(req, err)
+				}
+			}
+			if joinPeriod > 0 {
+				return r.requeue.Poll(req, joinPeriod)
+			}
+			return r.requeue.Done(req)
+		}
+		if ts, ok := r.syncers[req.Namespace]; ok && r.isTenantKubeconfigRotated(ctx, dpuClusterConfig, ts) {
+			logger.Info("The tenant kubeconfig changed, restart the tenant syncer")
+			r.stopTenantSyncer(req.Namespace)
+		} else if ok && ts.tenantNamespace != tenantNamespace(dpuClusterConfig) {
+			logger.Info("The tenant namespace changed, restart the tenant syncer")
+			r.stopTenantSyncer(req.Namespace)
+		} else if ok && ts.rateLimit != tenantRateLimit(dpuClusterConfig) {
+			logger.Info("The tenant rate limit changed, restart the tenant syncer")
+			r.stopTenantSyncer(req.Namespace)
 		}
-		if r.syncer == nil {
+		if _, ok := r.syncers[req.Namespace]; !ok {
+			if err = r.checkTenantKubeconfig(ctx, dpuClusterConfig); err != nil {
+				reason := api.ReasonFailedStart
+				var kerr *tenantKubeconfigError
+				if stderrors.As(err, &kerr) {
+					reason = kerr.reason
+				}
+				logger.Info("The tenant kubeconfig is not usable", "reason", reason, "error", err.Error())
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotTenantKubeconfigValid().Reason(reason).Msg(err.Error()).Build())
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotTenantObjsSynced().Reason(reason).Msg(err.Error()).Build())
+				return r.requeue.Retry(req, err)
+			}
+			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().TenantKubeconfigValid().Reason(api.ReasonValid).Build())
 			logger.Info("Create the tenant syncer")
-			r.stopCh = make(chan struct{})
 			if err = r.startTenantSyncer(ctx, dpuClusterConfig); err != nil {
 				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotTenantObjsSynced().Reason(api.ReasonFailedStart).Msg(err.Error()).Build())
-				return ctrl.Result{}, err
+				return r.requeue.Retry(req, err)
 			}
 			if err := r.isTenantObjsSynced(ctx, req.Namespace); err != nil {
 				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotTenantObjsSynced().Reason(api.ReasonNotFound).Msg(err.Error()).Build())
@@ -143,170 +342,858 @@ func (r *DpuClusterConfigReconciler) Reconcile(ctx context.Context, req ctrl.Req
 				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().TenantObjsSynced().Reason(api.ReasonCreated).Build())
 			}
 		}
-		if err = r.syncOvnkubeDaemonSet(ctx, dpuClusterConfig); err != nil {
+		ts := r.syncers[req.Namespace]
+		dpuClusterConfig.Status.SyncedObjects = ts.syncer.SyncedObjects()
+		if crashLooping, err := ts.task.CrashLooping(); crashLooping {
+			logger.Info("The tenant syncer keeps failing", "error", err.Error())
+			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().Degraded().Reason(api.ReasonCrashLooping).Msg(err.Error()).Build())
+		}
+		if unreachable := r.TenantConfigs.Unreachable(req.Namespace); unreachable != nil {
+			logger.Info("The tenant cluster is unreachable", "error", unreachable.Error())
+			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotTenantReachable().Reason(api.ReasonTenantUnreachable).Msg(unreachable.Error()).Build())
+			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().Degraded().Reason(api.ReasonTenantUnreachable).Msg(unreachable.Error()).Build())
+		} else {
+			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().TenantReachable().Reason(api.ReasonHeartbeatSucceeded).Build())
+		}
+		if crashLooping, err := ts.heartbeat.CrashLooping(); crashLooping {
+			logger.Info("The tenant heartbeat keeps failing", "error", err.Error())
+			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().Degraded().Reason(api.ReasonCrashLooping).Msg(err.Error()).Build())
+		}
+		if ts.tokenRenewal != nil {
+			if failing, err := ts.tokenRenewal.CrashLooping(); failing {
+				logger.Info("The tenant token renewal keeps failing", "error", err.Error())
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().Degraded().Reason(api.ReasonTokenRenewalFailed).Msg(err.Error()).Build())
+			}
+		}
+		if isDryRun(dpuClusterConfig) {
+			if err = r.syncDryRun(ctx, dpuClusterConfig); err != nil {
+				return r.requeue.Retry(req, err)
+			}
+			// The tenant cluster pods are not watched
+			if meta.IsStatusConditionTrue(dpuClusterConfig.Status.Conditions, api.TenantDiscoveryProgressing) {
+				return r.requeue.Poll(req, tenantDiscoveryRetryPeriod)
+			}
+			return r.requeue.Done(req)
+		}
+		var poolNotFound *poolNotFoundError
+		if err = r.syncOvnkubeDaemonSet(ctx, dpuClusterConfig); stderrors.As(err, &poolNotFound) {
+			// The MachineConfigPool is not watched, its creation is waited for
+			// with a backoff instead of being reported as a failure
+			msg := fmt.Sprintf("Waiting for MachineConfigPool %s", poolNotFound.pool)
+			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotOvnKubeReady().Reason(api.ReasonProgressing).Msg(msg).Build())
+			return r.requeue.Wait(req, msg)
+		} else if err != nil {
 			logger.Info("Sync DaemonSet ovnkube-node")
 			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotOvnKubeReady().Reason(api.ReasonFailedCreated).Msg(err.Error()).Build())
-			return ctrl.Result{}, err
+			return r.requeue.Retry(req, err)
+		}
+		if err = r.syncOpiBridge(ctx, dpuClusterConfig); err != nil {
+			logger.Error(err, "Fail to sync the OPI bridge")
+			return r.requeue.Retry(req, err)
+		}
+		if err = r.syncDocaTelemetry(ctx, dpuClusterConfig); err != nil {
+			logger.Error(err, "Fail to sync the DOCA telemetry service")
+			return r.requeue.Retry(req, err)
+		}
+		if err = r.syncOvsAgent(ctx, dpuClusterConfig); err != nil {
+			logger.Error(err, "Fail to sync the OVS agent")
+			return r.requeue.Retry(req, err)
+		}
+		if err = r.syncTenantAgent(ctx, dpuClusterConfig); err != nil {
+			logger.Error(err, "Fail to sync the tenant agent")
+			r.invalidateTenantClients(dpuClusterConfig, err)
+			return r.requeue.Retry(req, err)
+		}
+		if err = r.syncDpuHost(ctx, dpuClusterConfig); err != nil {
+			logger.Error(err, "Fail to sync the ovnkube dpu-host daemonset")
+			r.invalidateTenantClients(dpuClusterConfig, err)
+			return r.requeue.Retry(req, err)
+		}
+		if err = r.syncRepresentorNetworks(ctx, dpuClusterConfig); err != nil {
+			logger.Error(err, "Fail to check the representor networks")
+			r.invalidateTenantClients(dpuClusterConfig, err)
+			return r.requeue.Retry(req, err)
+		}
+		if err = r.syncConnectivityCheck(ctx, dpuClusterConfig); err != nil {
+			logger.Error(err, "Fail to sync the connectivity check")
+			r.invalidateTenantClients(dpuClusterConfig, err)
+			return r.requeue.Retry(req, err)
+		}
+		if err = r.checkOvnTLS(ctx, dpuClusterConfig); err != nil {
+			logger.Error(err, "Fail to check the ovn-cert")
+			return r.requeue.Retry(req, err)
 		}
-		ds := appsv1.DaemonSet{}
-		if err = r.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: "ovnkube-node"}, &ds); err != nil {
-			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotOvnKubeReady().Reason(api.ReasonNotFound).Msg(err.Error()).Build())
-			return ctrl.Result{}, err
+		rollingOut := ""
+		for _, pool := range dpuPools(dpuClusterConfig) {
+			ds := appsv1.DaemonSet{}
+			if err = r.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: pool.ovnkubeNodeName()}, &ds); err != nil {
+				meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotOvnKubeReady().Reason(api.ReasonNotFound).Msg(err.Error()).Build())
+				return r.requeue.Retry(req, err)
+			}
+			if ds.Status.DesiredNumberScheduled != ds.Status.NumberReady {
+				rollingOut = ds.Name
+				break
+			}
 		}
-		if ds.Status.DesiredNumberScheduled == ds.Status.NumberReady {
+		if rollingOut == "" {
 			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().OvnKubeReady().Reason(api.ReasonCreated).Build())
 		} else {
-			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotOvnKubeReady().Reason(api.ReasonProgressing).Msg("DaemonSet 'ovnkube-node' is rolling out").Build())
+			msg := fmt.Sprintf("DaemonSet '%s' is rolling out", rollingOut)
+			if diag := r.getOvnkubePodsDiagnostics(ctx, req.Namespace); diag != "" {
+				msg = msg + ": " + diag
+			}
+			meta.SetStatusCondition(&dpuClusterConfig.Status.Conditions, *api.Conditions().NotOvnKubeReady().Reason(api.ReasonProgressing).Msg(msg).Build())
+		}
+		// The tenant cluster pods and the syncer restarts are not watched
+		if meta.IsStatusConditionTrue(dpuClusterConfig.Status.Conditions, api.TenantDiscoveryProgressing) ||
+			meta.IsStatusConditionTrue(dpuClusterConfig.Status.Conditions, api.Degraded) {
+			return r.requeue.Poll(req, tenantDiscoveryRetryPeriod)
+		}
+		// Changes of a mounted kubeconfig file do not trigger any event
+		period := r.ResyncPeriod
+		if dpuClusterConfig.Spec.KubeConfigPath != "" && (period == 0 || period > kubeconfigFileResyncPeriod) {
+			period = kubeconfigFileResyncPeriod
+		}
+		// Nor does the certificates getting close to their expiry
+		if certPeriod := r.checkCertificateExpiry(ctx, dpuClusterConfig); certPeriod > 0 && (period == 0 || period > certPeriod) {
+			period = certPeriod
+		}
+		// Nor do the results of the connectivity checker pods
+		if dpuClusterConfig.Spec.ConnectivityCheck != nil {
+			if checkPeriod := connectivityCheckInterval(dpuClusterConfig); period == 0 || period > checkPeriod {
+				period = checkPeriod
+			}
+		}
+		// Nor do the registrations of the joining nodes and the rotations of
+		// the bootstrap token
+		if joinPeriod > 0 && (period == 0 || period > joinPeriod) {
+			period = joinPeriod
+		}
+		if period > 0 {
+			return r.requeue.Poll(req, period)
 		}
 	} else if len(cfgList.Items) == 0 {
-		if r.syncer != nil {
+		if _, ok := r.syncers[req.Namespace]; ok {
 			logger.Info("Stop the ovnkube syncer")
-			close(r.stopCh)
-			r.syncer = nil
+			r.stopTenantSyncer(req.Namespace)
+		}
+		deleteCertificateExpiryMetrics(req.Namespace)
+		deleteTenantHeartbeatMetrics(req.Namespace)
+		if err = r.deleteJoin(ctx, req.Namespace); err != nil {
+			return r.requeue.Retry(req, err)
 		}
+		if err = r.deleteOvnkubeNodeSCC(ctx, req.Namespace); err != nil {
+			return r.requeue.Retry(req, err)
+		}
+		return r.requeue.Deleted(req)
 	}
 
-	return ctrl.Result{}, nil
+	return r.requeue.Done(req)
+}
+
+// patchStatus patches the status of cfg to status when it differs. The patch
+// is rejected when cfg changed in between, in which case it is computed again
+// from the latest cfg, the status being owned by this controller.
+func (r *DpuClusterConfigReconciler) patchStatus(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, status dpuv1alpha1.DpuClusterConfigStatus) error {
+	status = *status.DeepCopy()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		keepTransitionTimes(cfg.Status.Conditions, status.Conditions)
+		if equality.Semantic.DeepEqual(cfg.Status, status) {
+			return nil
+		}
+		patched := cfg.DeepCopy()
+		patched.Status = status
+		err := r.Status().Patch(ctx, patched, client.MergeFromWithOptions(cfg, client.MergeFromWithOptimisticLock{}))
+		if errors.IsConflict(err) {
+			latest := &dpuv1alpha1.DpuClusterConfig{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(cfg), latest); err != nil {
+				return err
+			}
+			*cfg = *latest
+		}
+		return err
+	})
+}
+
+// keepTransitionTimes sets the lastTransitionTime of the conditions whose
+// status is the same as in the previous conditions back to the previous one.
+// A condition removed and set again within a reconcile, e.g. Degraded, would
+// otherwise change at each reconcile.
+func keepTransitionTimes(previous, conditions []metav1.Condition) {
+	for i := range conditions {
+		if c := meta.FindStatusCondition(previous, conditions[i].Type); c != nil && c.Status == conditions[i].Status {
+			conditions[i].LastTransitionTime = c.LastTransitionTime
+		}
+	}
+}
+
+// daemonSetChangedPredicate filters the updates of the owned DaemonSets down
+// to the edits of their spec or metadata, which are reverted, and to the
+// transitions of the numbers the OvnKubeReady condition is computed from, so
+// that the condition converges as soon as the pods are ready without
+// reconciling on every status heartbeat
+func daemonSetChangedPredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldDs, ok := e.ObjectOld.(*appsv1.DaemonSet)
+				if !ok {
+					return false
+				}
+				newDs, ok := e.ObjectNew.(*appsv1.DaemonSet)
+				if !ok {
+					return false
+				}
+				return oldDs.Status.NumberReady != newDs.Status.NumberReady ||
+					oldDs.Status.DesiredNumberScheduled != newDs.Status.DesiredNumberScheduled
+			},
+		},
+	)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DpuClusterConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&dpuv1alpha1.DpuClusterConfig{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&corev1.Secret{}).
-		Owns(&appsv1.DaemonSet{}).
-		Complete(r)
+	r.syncers = map[string]*tenantSyncer{}
+	r.tenantEvents = make(chan event.GenericEvent, 16)
+	r.requeue = newRequeuePolicy(logger, "DpuClusterConfig")
+	b := ctrl.NewControllerManagedBy(mgr).
+		// The status updates of the DpuClusterConfig are written by the
+		// reconcile itself and are not reconciled again
+		For(&dpuv1alpha1.DpuClusterConfig{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		// ConfigMaps and Secrets have no generation, only the resyncs of
+		// the informers, which change nothing, are filtered out
+		Owns(&corev1.ConfigMap{}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Owns(&corev1.Secret{}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Owns(&corev1.Service{}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Owns(&appsv1.DaemonSet{}, builder.WithPredicates(daemonSetChangedPredicate()))
+	b = b.Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.tenantKubeconfigRequests),
+		builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}))
+	b = b.Watches(&source.Kind{Type: &dpuv1alpha1.DpuNodeConfig{}}, handler.EnqueueRequestsFromMapFunc(r.dpuNodeConfigRequests),
+		builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})))
+	// The status of the DpuResources is mostly written by the OVS agents
+	b = b.Watches(&source.Kind{Type: &dpuv1alpha1.DpuResource{}}, handler.EnqueueRequestsFromMapFunc(r.dpuNodeConfigRequests),
+		builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	b = b.Watches(&source.Channel{Source: r.tenantEvents}, &handler.EnqueueRequestForObject{})
+	// The kubelets of the joining DPUs request their certificates
+	b = b.Watches(&source.Kind{Type: &certificatesv1.CertificateSigningRequest{}}, handler.EnqueueRequestsFromMapFunc(r.joinRequests),
+		builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}))
+	if r.Platform.Serves(sriovNetworkNodeStateGVK) {
+		state := &unstructured.Unstructured{}
+		state.SetGroupVersionKind(sriovNetworkNodeStateGVK)
+		b = b.Watches(&source.Kind{Type: state}, handler.EnqueueRequestsFromMapFunc(r.sriovIntegrationRequests),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}))
+	}
+	return b.Complete(r)
 }
 
 func (r *DpuClusterConfigReconciler) startTenantSyncer(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
 	logger.Info("Start the tenant syncer")
-	var err error
-	s := &corev1.Secret{}
+	bytes, version, err := r.getTenantKubeconfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
 
-	err = r.Client.Get(ctx, types.NamespacedName{Name: cfg.Spec.KubeConfigFile, Namespace: cfg.Namespace}, s)
+	tenantRestConfig, err := clientcmd.RESTConfigFromKubeConfig(bytes)
 	if err != nil {
 		return err
 	}
-	bytes, ok := s.Data["config"]
-	if !ok {
-		return fmt.Errorf("key 'config' cannot be found in secret %s", cfg.Spec.KubeConfigFile)
+	tracing.WrapTransport(tenantRestConfig, "tenant")
+	setTenantRateLimit(cfg, tenantRestConfig)
+	var token *tenantToken
+	if cfg.Spec.TokenRenewal != nil {
+		if token, err = newTenantToken(bytes); err != nil {
+			return err
+		}
+		if token == nil {
+			logger.Info("The tenant kubeconfig does not use a ServiceAccount token, it is not renewed")
+		} else {
+			tenantRestConfig.Wrap(token.wrapTransport)
+		}
+	}
+	if err = r.syncTenantKubeconfigSecret(ctx, cfg, bytes); err != nil {
+		return err
 	}
 
-	utils.TenantRestConfig, err = clientcmd.RESTConfigFromKubeConfig(bytes)
+	// The syncer shares the dynamic client of the tenant cluster with the
+	// controllers
+	r.TenantConfigs.Set(cfg.Namespace, tenantRestConfig)
+	clients, err := r.TenantConfigs.Clients(cfg.Namespace)
 	if err != nil {
+		r.TenantConfigs.Delete(cfg.Namespace)
 		return err
 	}
-
-	r.syncer, err = syncer.New(syncer.SyncerConfig{
+	ovnkubeSyncer, err := syncer.New(syncer.SyncerConfig{
 		// LocalClusterID:   cfg.Namespace,
 		LocalRestConfig:  ctrl.GetConfigOrDie(),
 		LocalNamespace:   cfg.Namespace,
-		TenantRestConfig: utils.TenantRestConfig,
-		TenantNamespace:  utils.TenantNamespace}, cfg, r.Scheme)
+		TenantRestConfig: tenantRestConfig,
+		TenantClient:     clients.Dynamic,
+		TenantNamespace:  tenantNamespace(cfg)}, cfg, r.Scheme)
 	if err != nil {
+		r.TenantConfigs.Delete(cfg.Namespace)
 		return err
 	}
-	go func() {
-		if err = r.syncer.Start(r.stopCh); err != nil {
-			logger.Error(err, "Error running the ovnkube syncer")
+	ts := &tenantSyncer{syncer: ovnkubeSyncer, stopCh: make(chan struct{}), kubeconfigVersion: version, tenantNamespace: tenantNamespace(cfg), rateLimit: tenantRateLimit(cfg)}
+	r.syncers[cfg.Namespace] = ts
+	ts.task = supervisor.Go("ovnkube-syncer/"+cfg.Namespace, ts.stopCh, func(stopCh <-chan struct{}) error {
+		if err := ts.syncer.Start(stopCh); err != nil {
+			return err
 		}
-	}()
-	if err != nil {
-		return err
+		<-stopCh
+		return nil
+	})
+	heartbeatCfg := cfg.DeepCopy()
+	ts.heartbeat = supervisor.Go("tenant-heartbeat/"+cfg.Namespace, ts.stopCh, func(stopCh <-chan struct{}) error {
+		return r.runTenantHeartbeat(heartbeatCfg, tenantRestConfig, stopCh)
+	})
+	if cfg.Spec.HostedCluster == nil {
+		ts.masters = &masterDiscovery{ttl: masterDiscoveryTTL(cfg)}
+		discoveryCfg := cfg.DeepCopy()
+		supervisor.Go("master-discovery/"+cfg.Namespace, ts.stopCh, func(stopCh <-chan struct{}) error {
+			return r.runMasterDiscovery(discoveryCfg, ts.masters, stopCh)
+		})
+	}
+	ts.network = &tenantNetwork{}
+	networkCfg := cfg.DeepCopy()
+	supervisor.Go("tenant-network/"+cfg.Namespace, ts.stopCh, func(stopCh <-chan struct{}) error {
+		return r.runTenantNetworkWatch(networkCfg, ts.network, stopCh)
+	})
+	if token != nil {
+		cfg := cfg.DeepCopy()
+		ts.tokenRenewal = supervisor.Go("token-renewal/"+cfg.Namespace, ts.stopCh, func(stopCh <-chan struct{}) error {
+			return r.renewTenantToken(cfg, tenantRestConfig, token, stopCh)
+		})
 	}
 
 	return nil
 }
 
-func (r *DpuClusterConfigReconciler) syncOvnkubeDaemonSet(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
-	logger.Info("Start to sync ovnkube daemonset")
-	var err error
-	mcp := &mcfgv1.MachineConfigPool{}
-	err = r.Get(context.TODO(), types.NamespacedName{Name: cfg.Spec.PoolName}, mcp)
+// stopTenantSyncer stops the tenant syncer of namespace and forgets the
+// tenant cluster config, so that the clients built from it are dropped.
+func (r *DpuClusterConfigReconciler) stopTenantSyncer(namespace string) {
+	if ts, ok := r.syncers[namespace]; ok {
+		close(ts.stopCh)
+		delete(r.syncers, namespace)
+	}
+	r.TenantConfigs.Delete(namespace)
+}
+
+// isTenantKubeconfigRotated returns true if the tenant kubeconfig changed
+// since the tenant syncer was started
+func (r *DpuClusterConfigReconciler) isTenantKubeconfigRotated(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, ts *tenantSyncer) bool {
+	_, version, err := r.getTenantKubeconfig(ctx, cfg)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			return fmt.Errorf("MachineConfigPool %s not found: %v", cfg.Spec.PoolName, err)
+		return false
+	}
+	return version != ts.kubeconfigVersion
+}
+
+// tenantKubeconfigRequests maps a secret event to the DpuClusterConfig
+// using the secret as tenant kubeconfig
+func (r *DpuClusterConfigReconciler) tenantKubeconfigRequests(obj client.Object) []reconcile.Request {
+	cfgList := &dpuv1alpha1.DpuClusterConfigList{}
+	if err := r.List(context.TODO(), cfgList, &client.ListOptions{Namespace: obj.GetNamespace()}); err != nil {
+		logger.Error(err, "Fail to list DpuClusterConfigs")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, cfg := range cfgList.Items {
+		if cfg.Spec.KubeConfigFile == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}})
 		}
 	}
+	return requests
+}
 
-	masterIPs, err := r.getTenantClusterMasterIPs(ctx)
+func (r *DpuClusterConfigReconciler) syncOvnkubeDaemonSet(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	logger.Info("Start to sync ovnkube daemonset")
+	nodeConfigs := &dpuv1alpha1.DpuNodeConfigList{}
+	if err := r.List(ctx, nodeConfigs, client.InNamespace(cfg.Namespace)); err != nil {
+		return err
+	}
+	if err := r.syncDpuNodeOverrides(ctx, cfg, nodeConfigs.Items); err != nil {
+		return err
+	}
+	objs, err := r.renderOvnkubeNode(ctx, cfg)
 	if err != nil {
-		logger.Error(err, "failed to get the ovnkube master IPs")
+		return err
+	}
+	ovnkubeConfig, err := r.getOvnkubeConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	hash := ovnkubeConfigHash(ovnkubeConfig)
+	excluded := excludedDpuNodes(nodeConfigs.Items)
+	for _, obj := range objs {
+		if obj.GetKind() == "DaemonSet" {
+			if err := setDaemonSetExcludedNodes(obj, excluded); err != nil {
+				return err
+			}
+			// Only the ovnkube-node DaemonSets of the pools read the ovnkube-config
+			if strings.HasPrefix(obj.GetName(), utils.OvnkubeNodeDsName) {
+				r.logOvnkubeConfigChange(ctx, obj.GetNamespace(), obj.GetName(), ovnkubeConfig, hash)
+				if err := setDaemonSetConfigHash(obj, hash); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// The DaemonSets are already pinned to the nodes of their pool
+	if err := r.applyObjects(ctx, cfg, objs, nil); err != nil {
+		return err
+	}
+	if cfg.Spec.OvsdbRelay == nil {
+		if err := r.deleteOvsdbRelay(cfg); err != nil {
+			return err
+		}
+	}
+	if !managesCni(cfg) {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: utils.CniConfigMapName, Namespace: cfg.Namespace}}
+		if err := utils.DeleteObject(r.Client, cm); err != nil {
+			return err
+		}
+	}
+	if ipsecEnabled(cfg, r.getTenantNetworkSettings(cfg)) {
 		return nil
 	}
+	for _, pool := range dpuPools(cfg) {
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: pool.ovnIPsecName(), Namespace: cfg.Namespace}}
+		if err := utils.DeleteObject(r.Client, ds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	image := os.Getenv("OVNKUBE_IMAGE")
-	if image == "" {
-		image, err = r.getLocalOvnkubeImage()
+// renderOvnkubeNode renders the ovnkube-node manifests of the pools of cfg,
+// with the OVN databases of the tenant cluster, each DaemonSet being pinned
+// to the nodes of its pool. No object is returned as long as no
+// ovnkube-master pod is found in the tenant cluster.
+func (r *DpuClusterConfigReconciler) renderOvnkubeNode(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) ([]*unstructured.Unstructured, error) {
+	var nbDbList, sbDbList string
+	if cfg.Spec.HostedCluster != nil {
+		// The OVN databases of a hosted cluster run in the management
+		// cluster, there are no ovnkube-master pods to look up.
+		nbDbList = cfg.Spec.HostedCluster.NbDbAddress
+		sbDbList = cfg.Spec.HostedCluster.SbDbAddress
+	} else {
+		masterIPs, expected, err := r.getCachedTenantClusterMasterIPs(ctx, cfg)
 		if err != nil {
-			return err
+			logger.Error(err, "failed to get the ovnkube master IPs")
+			meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().TenantDiscoveryProgressing().Reason(api.ReasonNotFound).Msg(err.Error()).Build())
+			return nil, nil
+		}
+		if len(masterIPs) < expected || len(masterIPs) == 0 {
+			msg := fmt.Sprintf("found %d of %d ovnkube-master pods in the tenant cluster", len(masterIPs), expected)
+			logger.Info("Tenant discovery in progress", "found", len(masterIPs), "expected", expected)
+			meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().TenantDiscoveryProgressing().Reason(api.ReasonProgressing).Msg(msg).Build())
+			if len(masterIPs) == 0 {
+				return nil, nil
+			}
+		} else {
+			msg := fmt.Sprintf("found %d ovnkube-master pods in the tenant cluster", len(masterIPs))
+			meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().TenantDiscoveryDone().Reason(api.ReasonDiscovered).Msg(msg).Build())
+		}
+		nbDbList = dbList(masterIPs, OVN_NB_PORT)
+		sbDbList = dbList(masterIPs, OVN_SB_PORT)
+	}
+
+	objs := []*unstructured.Unstructured{}
+	if cfg.Spec.OvsdbRelay != nil {
+		image := cfg.Spec.OvsdbRelay.Image
+		if image == "" {
+			image = cfg.Spec.OvnkubeImage
+		}
+		if image == "" {
+			var err error
+			if image, err = r.getOvnkubeImage(); err != nil {
+				return nil, err
+			}
+		}
+		relayObjs, err := renderOvsdbRelayManifests(ctx, cfg, resolveImage(ctx, r.Images, image), sbDbList)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, relayObjs...)
+		// The ovnkube-node pods connect to the relay instead, they are
+		// rendered once its Service has a ClusterIP
+		if sbDbList, err = r.getOvsdbRelayDbList(ctx, cfg); err != nil {
+			return nil, err
+		}
+		if sbDbList == "" {
+			logger.Info("Waiting for the ClusterIP of the OVSDB relay service")
+			return objs, nil
+		}
+	}
+
+	kubeconfigKey, err := r.getTenantKubeconfigKey(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, pool := range dpuPools(cfg) {
+		image := pool.cfg.Spec.OvnkubeImage
+		if image == "" {
+			var err error
+			if image, err = r.getOvnkubeImage(); err != nil {
+				return nil, err
+			}
+		}
+		image = resolveImage(ctx, r.Images, image)
+		nodeSelector, err := r.getDpuNodeSelector(pool.cfg)
+		if err != nil {
+			return nil, err
 		}
+		poolObjs, err := renderOvnkubeNodeManifests(ctx, pool, r.getTenantNetworkSettings(cfg), image, kubeconfigKey, nbDbList, sbDbList)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range poolObjs {
+			if obj.GetKind() == "DaemonSet" {
+				if err := setDaemonSetNodeSelector(obj, nodeSelector); err != nil {
+					return nil, err
+				}
+			}
+		}
+		objs = append(objs, poolObjs...)
 	}
+	return objs, nil
+}
 
+// renderOvnkubeNodeManifests renders the ovnkube-node manifests of pool
+// running image and connecting to the given OVN databases, the tenant
+// kubeconfig being read from kubeconfigKey of its secret
+func renderOvnkubeNodeManifests(ctx context.Context, pool dpuPool, tenant *tenantNetworkSettings, image, kubeconfigKey, nbDbList, sbDbList string) ([]*unstructured.Unstructured, error) {
+	cfg := pool.cfg
 	data := render.MakeRenderData()
+	data.Data["DaemonSetName"] = pool.ovnkubeNodeName()
+	data.Data["IPsecDaemonSetName"] = pool.ovnIPsecName()
+	data.Data["MainPool"] = pool.main
+	data.Data["PoolName"] = cfg.Spec.PoolName
 	data.Data["OvnKubeImage"] = image
 	data.Data["Namespace"] = cfg.Namespace
-	data.Data["TenantKubeconfig"] = cfg.Spec.KubeConfigFile
-	data.Data["OVN_NB_DB_LIST"] = dbList(masterIPs, OVN_NB_PORT)
-	data.Data["OVN_SB_DB_LIST"] = dbList(masterIPs, OVN_SB_PORT)
+	data.Data["TenantNamespace"] = tenantNamespace(cfg)
+	data.Data["PriorityClassName"] = priorityClassName(cfg)
+	data.Data["HostNetwork"], data.Data["HostPID"], data.Data["DNSPolicy"] = true, true, ""
+	if spec := cfg.Spec.OvnkubeNode; spec != nil {
+		if spec.HostNetwork != nil {
+			data.Data["HostNetwork"] = *spec.HostNetwork
+		}
+		if spec.HostPID != nil {
+			data.Data["HostPID"] = *spec.HostPID
+		}
+		data.Data["DNSPolicy"] = string(spec.DNSPolicy)
+	}
+	data.Data["OvsMetrics"] = cfg.Spec.OvsMetrics != nil
+	data.Data["OvsMetricsPort"] = ovsMetricsPort(cfg)
+	data.Data["OvsMetricsPortName"] = metrics.OvsMetricsPortName
+	data.Data["TenantKubeconfig"] = tenantKubeconfigSecretName(cfg)
+	data.Data["TenantKubeconfigKey"] = kubeconfigKey
+	data.Data["HostedCluster"] = cfg.Spec.HostedCluster != nil
+	data.Data["OvnControllerOnly"] = cfg.Spec.DeploymentMode == dpuv1alpha1.DeploymentModeOvnControllerOnly
+	setCniRenderData(cfg, &data)
+	setOvnTLSRenderData(cfg, &data)
+	setTenantNetworkRenderData(cfg, tenant, &data)
+	setNodeIdentityRenderData(cfg, &data)
+	data.Data["OVN_NB_DB_LIST"] = nbDbList
+	data.Data["OVN_SB_DB_LIST"] = sbDbList
 
-	objs, err := render.RenderDir(utils.OvnkubeNodeManifestPath, &data)
+	objs, err := renderDir(ctx, utils.OvnkubeNodeManifestPath, &data)
 	if err != nil {
 		logger.Error(err, "Fail to render ovnkube-node daemon manifests")
-		return err
+		return nil, err
+	}
+	if pool.main {
+		return objs, nil
+	}
+	// The other objects are shared by the pools, they come with the main one
+	daemonSets := []*unstructured.Unstructured{}
+	for _, obj := range objs {
+		if obj.GetKind() == "DaemonSet" {
+			daemonSets = append(daemonSets, obj)
+		}
 	}
-	// Sync DaemonSets
+	return daemonSets, nil
+}
+
+// applyObjects applies the rendered objects owned by cfg, the DaemonSets are
+// pinned to the nodes matching nodeSelector, which is nil for DaemonSets
+// already pinned. Objects whose API is not served by the cluster are skipped.
+func (r *DpuClusterConfigReconciler) applyObjects(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, objs []*unstructured.Unstructured, nodeSelector map[string]string) error {
+	var err error
 	for _, obj := range objs {
+		if !r.Platform.Serves(obj.GroupVersionKind()) {
+			logger.Info("Skip object not supported by the cluster", "kind", obj.GetKind(), "name", obj.GetName())
+			continue
+		}
 		switch obj.GetKind() {
 		case "DaemonSet":
-			scheme := scheme.Scheme
-			ds := &appsv1.DaemonSet{}
-			err = scheme.Convert(obj, ds, nil)
-			if err != nil {
-				logger.Error(err, "Fail to convert to DaemonSet")
+			if err = setDaemonSetNodeSelector(obj, nodeSelector); err != nil {
 				return err
 			}
-			for k, v := range mcp.Spec.NodeSelector.MatchLabels {
-				ds.Spec.Template.Spec.NodeSelector[k] = v
+			if err = checkDaemonSetArchitecture(ctx, r.Images, obj); err != nil {
+				return err
 			}
-			err = scheme.Convert(ds, obj, nil)
-			if err != nil {
-				logger.Error(err, "Fail to convert to Unstructured")
+			if err := r.adoptDaemonSet(ctx, cfg, obj); err != nil {
 				return err
 			}
 			if err := ctrl.SetControllerReference(cfg, obj, r.Scheme); err != nil {
 				return err
 			}
 		default:
+			// A namespaced DpuClusterConfig cannot own cluster-scoped
+			// objects, they are deleted along with the last config of the
+			// namespace instead
+			if obj.GetNamespace() == "" {
+				break
+			}
 			if err := ctrl.SetControllerReference(cfg, obj, r.Scheme); err != nil {
 				return err
 			}
 		}
-		if err := apply.ApplyObject(context.TODO(), r.Client, obj); err != nil {
+		setCommonMetadata(cfg, obj)
+		if err := applyObject(ctx, r.Client, obj); err != nil {
 			return fmt.Errorf("failed to apply object %v with err: %v", obj, err)
 		}
 	}
 	return nil
 }
 
+// setDaemonSetNodeSelector adds nodeSelector to the node selector of the
+// DaemonSet obj, and requires the architecture of the DPUs
+func setDaemonSetNodeSelector(obj *unstructured.Unstructured, nodeSelector map[string]string) error {
+	scheme := scheme.Scheme
+	ds := &appsv1.DaemonSet{}
+	err := scheme.Convert(obj, ds, nil)
+	if err != nil {
+		logger.Error(err, "Fail to convert to DaemonSet")
+		return err
+	}
+	if ds.Spec.Template.Spec.NodeSelector == nil {
+		ds.Spec.Template.Spec.NodeSelector = map[string]string{}
+	}
+	for k, v := range nodeSelector {
+		ds.Spec.Template.Spec.NodeSelector[k] = v
+	}
+	requireDpuArchitecture(&ds.Spec.Template.Spec)
+	err = scheme.Convert(ds, obj, nil)
+	if err != nil {
+		logger.Error(err, "Fail to convert to Unstructured")
+		return err
+	}
+	return nil
+}
+
+// requireDpuArchitecture adds the architecture of the DPUs to the required
+// node affinity of the pod spec, unless the hardware is simulated
+func requireDpuArchitecture(spec *corev1.PodSpec) {
+	if utils.SimulateHardware {
+		return
+	}
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      corev1.LabelArchStable,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{utils.DpuArchitecture},
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{requirement}}},
+		}
+		return
+	}
+	// The terms are ORed, each of them has to require the architecture
+	for i := range required.NodeSelectorTerms {
+		term := &required.NodeSelectorTerms[i]
+		found := false
+		for _, e := range term.MatchExpressions {
+			if e.Key == corev1.LabelArchStable {
+				found = true
+			}
+		}
+		if !found {
+			term.MatchExpressions = append(term.MatchExpressions, requirement)
+		}
+	}
+}
+
+// renderDir renders the manifests of the directory within a span
+func renderDir(ctx context.Context, dir string, data *render.RenderData) ([]*unstructured.Unstructured, error) {
+	_, span := tracing.Start(ctx, "render", "dir", dir)
+	objs, err := render.RenderDir(dir, data)
+	span.End(err)
+	return objs, err
+}
+
+// applyObject applies the object within a span
+func applyObject(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	ctx, span := tracing.Start(ctx, "apply", "kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+	err := apply.ApplyObject(ctx, c, obj)
+	span.End(err)
+	return err
+}
+
+// poolNotFoundError is returned when the MachineConfigPool of the DPU nodes
+// is not found, e.g. right after its creation, before it shows up in the
+// cache
+type poolNotFoundError struct {
+	pool string
+}
+
+func (e *poolNotFoundError) Error() string {
+	return fmt.Sprintf("MachineConfigPool %s not found", e.pool)
+}
+
+// poolConflictError is returned when the MachineConfigPool or the
+// MachineConfig of a pool is labelled with the UID of another
+// DpuClusterConfig, e.g. one of another namespace using the same pool name
+type poolConflictError struct {
+	kind string
+	name string
+	uid  string
+}
+
+func (e *poolConflictError) Error() string {
+	return fmt.Sprintf("%s %s belongs to the DpuClusterConfig %s", e.kind, e.name, e.uid)
+}
+
+// checkPoolOwner returns a poolConflictError when obj is labelled with the
+// UID of another DpuClusterConfig than cfg. The objects without the label,
+// e.g. created by hand, are adopted.
+func checkPoolOwner(cfg *dpuv1alpha1.DpuClusterConfig, kind string, obj metav1.Object) error {
+	if uid := obj.GetLabels()[dpuClusterConfigUIDLabel]; uid != "" && uid != string(cfg.UID) {
+		return &poolConflictError{kind: kind, name: obj.GetName(), uid: uid}
+	}
+	return nil
+}
+
+// getDpuNodeSelector returns the labels selecting the DPU nodes. With the
+// MachineConfig backend they are taken from the MachineConfigPool.
+func (r *DpuClusterConfigReconciler) getDpuNodeSelector(cfg *dpuv1alpha1.DpuClusterConfig) (map[string]string, error) {
+	if !r.Platform.HasMachineConfig() {
+		if cfg.Spec.NodeSelector == nil {
+			return map[string]string{}, nil
+		}
+		return cfg.Spec.NodeSelector.MatchLabels, nil
+	}
+	mcp := &mcfgv1.MachineConfigPool{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: cfg.Spec.PoolName}, mcp)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, &poolNotFoundError{pool: cfg.Spec.PoolName}
+		}
+		return nil, err
+	}
+	if mcp.Spec.NodeSelector == nil {
+		return map[string]string{}, nil
+	}
+	return mcp.Spec.NodeSelector.MatchLabels, nil
+}
+
+func (r *DpuClusterConfigReconciler) getOvnkubeImage() (string, error) {
+	image := os.Getenv("OVNKUBE_IMAGE")
+	if image != "" {
+		return image, nil
+	}
+	return r.getLocalOvnkubeImage()
+}
+
 func (r *DpuClusterConfigReconciler) getLocalOvnkubeImage() (string, error) {
 	ds := &appsv1.DaemonSet{}
-	name := types.NamespacedName{Namespace: utils.LocalOvnkbueNamespace, Name: utils.LocalOvnkbueNodeDsName}
+	name := types.NamespacedName{Namespace: r.LocalOvnkube.Namespace, Name: r.LocalOvnkube.DaemonSet}
 	err := r.Get(context.TODO(), name, ds)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", fmt.Errorf("DaemonSet %s not found to look up the ovnkube image, set spec.ovnkubeImage or the OVNKUBE_IMAGE environment variable", name)
+		}
 		return "", err
 	}
 	return ds.Spec.Template.Spec.Containers[0].Image, nil
 }
 
-func (r *DpuClusterConfigReconciler) syncMachineConfigObjs(cs dpuv1alpha1.DpuClusterConfigSpec) error {
+// renderMachineConfig renders the MachineConfig configuring the DPUs of the
+// pool of cfg
+func (r *DpuClusterConfigReconciler) renderMachineConfig(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (*mcfgv1.MachineConfig, error) {
+	data, err := r.machineConfigRenderData(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return renderMachineConfigManifest(ctx, cfg, &data)
+}
+
+// renderMachineConfigManifest renders the MachineConfig of the pool of cfg
+// with the given render data
+func renderMachineConfigManifest(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, data *mcrender.RenderData) (*mcfgv1.MachineConfig, error) {
+	_, span := tracing.Start(ctx, "render", "dir", utils.MachineConfigPath)
+	mc, err := mcrender.GenerateMachineConfig(utils.MachineConfigPath, machineConfigName(cfg), machineConfigRole(cfg), true, data)
+	if err == nil {
+		err = mergeHostConfig(mc, data)
+	}
+	span.End(err)
+	return mc, err
+}
+
+// machineConfigName returns the name of the MachineConfig of the pool of cfg
+func machineConfigName(cfg *dpuv1alpha1.DpuClusterConfig) string {
+	return "00-" + cfg.Spec.PoolName + "-" + "bluefield-switchdev"
+}
+
+// machineConfigRole returns the role of the MachineConfig of the pool of cfg,
+// each pool has its own so that its MachineConfigPool only renders its own
+// MachineConfig on top of the worker ones
+func machineConfigRole(cfg *dpuv1alpha1.DpuClusterConfig) string {
+	return cfg.Spec.PoolName
+}
+
+// machineConfigSelector returns the MachineConfigSelector of the
+// MachineConfigPool of the pool. The main pool also selects the
+// MachineConfigs of the dpu-worker role, which is the role of its
+// MachineConfig on the clusters set up before the pools got their own roles.
+func machineConfigSelector(pool dpuPool) (*metav1.LabelSelector, error) {
+	roles := []string{"worker", machineConfigRole(pool.cfg)}
+	if pool.main && machineConfigRole(pool.cfg) != dpuMcRole {
+		roles = append(roles, dpuMcRole)
+	}
+	return metav1.ParseToLabelSelector(fmt.Sprintf("%s in (%s)", mcfgv1.MachineConfigRoleLabelKey, strings.Join(roles, ",")))
+}
+
+// syncMachineConfigObjs syncs the MachineConfigPool and the MachineConfig of
+// each pool of cfg
+func (r *DpuClusterConfigReconciler) syncMachineConfigObjs(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	// While the main pool is renamed, it only selects the nodes already
+	// moved from the previous pools
+	migrating, err := r.isPoolMigrating(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	for _, pool := range dpuPools(cfg) {
+		if err := r.syncMachineConfigPool(ctx, pool, migrating && pool.main); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncMachineConfigPool syncs the MachineConfigPool and the MachineConfig of
+// the pool
+func (r *DpuClusterConfigReconciler) syncMachineConfigPool(ctx context.Context, pool dpuPool, migrating bool) error {
 	var err error
+	cfg := pool.cfg
+	cs := cfg.Spec
 	foundMc := &mcfgv1.MachineConfig{}
 	foundMcp := &mcfgv1.MachineConfigPool{}
 	mcp := &mcfgv1.MachineConfigPool{}
 	mcp.Name = cs.PoolName
-	mcSelector, err := metav1.ParseToLabelSelector(fmt.Sprintf("%s in (worker,%s)", mcfgv1.MachineConfigRoleLabelKey, dpuMcRole))
+	mcSelector, err := machineConfigSelector(pool)
 	if err != nil {
 		return err
 	}
@@ -314,10 +1201,11 @@ func (r *DpuClusterConfigReconciler) syncMachineConfigObjs(cs dpuv1alpha1.DpuClu
 		MachineConfigSelector: mcSelector,
 		NodeSelector:          cs.NodeSelector,
 	}
-	if cs.PoolName == "master" || cs.PoolName == "worker" {
-		return fmt.Errorf("%s pools is not allowed", cs.PoolName)
+	if migrating {
+		mcp.Spec.NodeSelector = poolMigrationNodeSelector(cs.NodeSelector, cs.PoolName, false)
 	}
-
+	mcp.Labels = map[string]string{dpuClusterConfigUIDLabel: string(cfg.UID)}
+	setCommonMetadata(cfg, mcp)
 	err = r.Get(context.TODO(), types.NamespacedName{Name: cs.PoolName}, foundMcp)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -327,9 +1215,15 @@ func (r *DpuClusterConfigReconciler) syncMachineConfigObjs(cs dpuv1alpha1.DpuClu
 				return fmt.Errorf("couldn't create MachineConfigPool: %v", err)
 			}
 			logger.Info("Created MachineConfigPool:", "name", cs.PoolName)
+		} else {
+			return fmt.Errorf("failed to get MachineConfigPool: %v", err)
 		}
 	} else {
-		if !(equality.Semantic.DeepEqual(foundMcp.Spec.MachineConfigSelector, mcSelector) && equality.Semantic.DeepEqual(foundMcp.Spec.NodeSelector, cs.NodeSelector)) {
+		if err := checkPoolOwner(cfg, "MachineConfigPool", foundMcp); err != nil {
+			return err
+		}
+		metadataChanged := syncMetadata(mcp, foundMcp)
+		if metadataChanged || !(equality.Semantic.DeepEqual(foundMcp.Spec.MachineConfigSelector, mcSelector) && equality.Semantic.DeepEqual(foundMcp.Spec.NodeSelector, mcp.Spec.NodeSelector)) {
 			logger.Info("MachineConfigPool already exists, updating")
 			foundMcp.Spec = mcp.Spec
 			err = r.Update(context.TODO(), foundMcp)
@@ -341,13 +1235,16 @@ func (r *DpuClusterConfigReconciler) syncMachineConfigObjs(cs dpuv1alpha1.DpuClu
 		}
 	}
 
-	mcName := "00-" + cs.PoolName + "-" + "bluefield-switchdev"
-
-	data := mcrender.MakeRenderData()
-	mc, err := mcrender.GenerateMachineConfig("bindata/machine-config", mcName, dpuMcRole, true, &data)
+	mc, err := r.renderMachineConfig(ctx, cfg)
 	if err != nil {
 		return err
 	}
+	mcName := mc.Name
+	if mc.Labels == nil {
+		mc.Labels = map[string]string{}
+	}
+	mc.Labels[dpuClusterConfigUIDLabel] = string(cfg.UID)
+	setCommonMetadata(cfg, mc)
 
 	err = r.Get(context.TODO(), types.NamespacedName{Name: mcName}, foundMc)
 	if err != nil {
@@ -361,6 +1258,9 @@ func (r *DpuClusterConfigReconciler) syncMachineConfigObjs(cs dpuv1alpha1.DpuClu
 			return fmt.Errorf("failed to get MachineConfig: %v", err)
 		}
 	} else {
+		if err := checkPoolOwner(cfg, "MachineConfig", foundMc); err != nil {
+			return err
+		}
 		var foundIgn, renderedIgn interface{}
 		// The Raw config JSON string may have the fields reordered.
 		// For example the "path" field may come before the "contents"
@@ -368,13 +1268,19 @@ func (r *DpuClusterConfigReconciler) syncMachineConfigObjs(cs dpuv1alpha1.DpuClu
 		// MachineConfig's ignition JSON would have it the other way around.
 		// Thus we need to unmarshal the JSON for both found and rendered
 		// ignition and compare.
-		json.Unmarshal(foundMc.Spec.Config.Raw, &foundIgn)
-		json.Unmarshal(mc.Spec.Config.Raw, &renderedIgn)
-		if !reflect.DeepEqual(foundIgn, renderedIgn) {
+		if err := json.Unmarshal(foundMc.Spec.Config.Raw, &foundIgn); err != nil {
+			logger.Error(err, "Failed to parse the ignition config of the MachineConfig, overwriting it", "name", mcName)
+			foundIgn = nil
+		}
+		if err := json.Unmarshal(mc.Spec.Config.Raw, &renderedIgn); err != nil {
+			return fmt.Errorf("failed to parse the rendered ignition config of MachineConfig %s: %v", mcName, err)
+		}
+		// The labels and annotations set by other tools are kept
+		metadataChanged := syncMetadata(mc, foundMc)
+		if metadataChanged || !reflect.DeepEqual(foundIgn, renderedIgn) {
 			logger.Info("MachineConfig already exists, updating")
-			foundMc.Spec.Config.Raw = mc.Spec.Config.Raw
-			mc.SetResourceVersion(foundMc.GetResourceVersion())
-			err = r.Update(context.TODO(), mc)
+			foundMc.Spec = mc.Spec
+			err = r.Update(context.TODO(), foundMc)
 			if err != nil {
 				return fmt.Errorf("couldn't update MachineConfig: %v", err)
 			}
@@ -385,11 +1291,77 @@ func (r *DpuClusterConfigReconciler) syncMachineConfigObjs(cs dpuv1alpha1.DpuClu
 	return nil
 }
 
-func (r *DpuClusterConfigReconciler) getTenantClusterMasterIPs(ctx context.Context) ([]string, error) {
-	c, err := client.New(utils.TenantRestConfig, client.Options{})
+// deleteStaleMachineConfigObjs deletes the MachineConfigPools and the
+// MachineConfigs previously created for cfg, which are left behind when its
+// pools are renamed or removed
+func (r *DpuClusterConfigReconciler) deleteStaleMachineConfigObjs(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	mcpNames := map[string]bool{}
+	mcNames := map[string]bool{}
+	for _, pool := range dpuPools(cfg) {
+		mcpNames[pool.cfg.Spec.PoolName] = true
+		mcNames[machineConfigName(pool.cfg)] = true
+	}
+	selector := client.MatchingLabels{dpuClusterConfigUIDLabel: string(cfg.UID)}
+	mcps := &mcfgv1.MachineConfigPoolList{}
+	if err := r.List(ctx, mcps, selector); err != nil {
+		return err
+	}
+	for i := range mcps.Items {
+		if mcpNames[mcps.Items[i].Name] {
+			continue
+		}
+		logger.Info("Delete stale MachineConfigPool", "name", mcps.Items[i].Name)
+		if err := utils.DeleteObject(r.Client, &mcps.Items[i]); err != nil {
+			return fmt.Errorf("couldn't delete MachineConfigPool: %v", err)
+		}
+	}
+	mcs := &mcfgv1.MachineConfigList{}
+	if err := r.List(ctx, mcs, selector); err != nil {
+		return err
+	}
+	for i := range mcs.Items {
+		if mcNames[mcs.Items[i].Name] {
+			continue
+		}
+		logger.Info("Delete stale MachineConfig", "name", mcs.Items[i].Name)
+		if err := utils.DeleteObject(r.Client, &mcs.Items[i]); err != nil {
+			return fmt.Errorf("couldn't delete MachineConfig: %v", err)
+		}
+	}
+	return nil
+}
+
+// tenantClient returns the client of the tenant cluster of cfg shared by the
+// controllers
+func (r *DpuClusterConfigReconciler) tenantClient(cfg *dpuv1alpha1.DpuClusterConfig) (client.Client, error) {
+	clients, err := r.TenantConfigs.Clients(cfg.Namespace)
 	if err != nil {
-		logger.Error(err, "Fail to create client for the tenant cluster")
-		return []string{}, err
+		return nil, err
+	}
+	if clients == nil {
+		return nil, fmt.Errorf("no tenant cluster config for namespace %s", cfg.Namespace)
+	}
+	return clients.Client, nil
+}
+
+// invalidateTenantClients drops the shared clients of the tenant cluster of
+// cfg when the tenant cluster rejected their credentials or does not serve a
+// kind they discovered, so that they are built again on next use
+func (r *DpuClusterConfigReconciler) invalidateTenantClients(cfg *dpuv1alpha1.DpuClusterConfig, err error) {
+	if errors.IsUnauthorized(err) || meta.IsNoMatchError(err) {
+		logger.Info("Drop the clients of the tenant cluster", "namespace", cfg.Namespace, "error", err.Error())
+		r.TenantConfigs.InvalidateClients(cfg.Namespace)
+	}
+}
+
+// getTenantClusterMasterIPs returns the IPs of the running ovnkube-master
+// pods of the tenant cluster, along with the number of pods expected from
+// the ovnkube-master DaemonSet
+func (r *DpuClusterConfigReconciler) getTenantClusterMasterIPs(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) ([]string, int, error) {
+	c, err := r.tenantClient(cfg)
+	if err != nil {
+		logger.Error(err, "Fail to get the client of the tenant cluster")
+		return []string{}, 0, err
 	}
 	ovnkubeMasterPods := corev1.PodList{}
 	labelSelector := labels.SelectorFromSet(map[string]string{"app": "ovnkube-master"})
@@ -397,13 +1369,25 @@ func (r *DpuClusterConfigReconciler) getTenantClusterMasterIPs(ctx context.Conte
 	err = c.List(ctx, &ovnkubeMasterPods, listOps)
 	if err != nil {
 		logger.Error(err, "Fail to get the ovnkube-master pods of the tenant cluster")
-		return []string{}, err
+		r.invalidateTenantClients(cfg, err)
+		return []string{}, 0, err
 	}
 	masterIPs := []string{}
 	for _, pod := range ovnkubeMasterPods.Items {
-		masterIPs = append(masterIPs, pod.Status.PodIP)
+		if pod.Status.PodIP != "" {
+			masterIPs = append(masterIPs, pod.Status.PodIP)
+		}
 	}
-	return masterIPs, nil
+
+	expected := len(ovnkubeMasterPods.Items)
+	ds := &appsv1.DaemonSet{}
+	err = c.Get(ctx, types.NamespacedName{Namespace: tenantNamespace(cfg), Name: "ovnkube-master"}, ds)
+	if err == nil {
+		expected = int(ds.Status.DesiredNumberScheduled)
+	} else if !errors.IsNotFound(err) {
+		logger.Error(err, "Fail to get the ovnkube-master daemonset of the tenant cluster")
+	}
+	return masterIPs, expected, nil
 }
 
 func (r *DpuClusterConfigReconciler) isTenantObjsSynced(ctx context.Context, namespace string) error {
@@ -424,6 +1408,151 @@ func (r *DpuClusterConfigReconciler) isTenantObjsSynced(ctx context.Context, nam
 	return nil
 }
 
+// getOvnkubePodsDiagnostics summarizes the failing containers of the
+// ovnkube-node pods, with the reason and exit code of their last termination
+func (r *DpuClusterConfigReconciler) getOvnkubePodsDiagnostics(ctx context.Context, namespace string) string {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{"app": utils.OvnkubeNodeDsName}); err != nil {
+		logger.Error(err, "Fail to list the ovnkube-node pods")
+		return ""
+	}
+	diags := []string{}
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Ready || cs.State.Waiting == nil {
+				continue
+			}
+			diag := fmt.Sprintf("container %s of pod %s on node %s is %s", cs.Name, pod.Name, pod.Spec.NodeName, cs.State.Waiting.Reason)
+			if t := cs.LastTerminationState.Terminated; t != nil {
+				diag += fmt.Sprintf(", last terminated with %s (exit code %d) after %d restarts", t.Reason, t.ExitCode, cs.RestartCount)
+				// The message may hold the tail of the logs, keep the last line
+				if lines := strings.Split(strings.TrimSpace(t.Message), "\n"); lines[len(lines)-1] != "" {
+					diag += ": " + lines[len(lines)-1]
+				}
+			}
+			diags = append(diags, diag)
+		}
+	}
+	return strings.Join(diags, "; ")
+}
+
+// deleteOvnkubeNodeSCC deletes the SecurityContextConstraints of the
+// ovnkube-node pods of namespace
+func (r *DpuClusterConfigReconciler) deleteOvnkubeNodeSCC(ctx context.Context, namespace string) error {
+	if !r.Platform.Serves(sccGVK) {
+		return nil
+	}
+	scc := &unstructured.Unstructured{}
+	scc.SetGroupVersionKind(sccGVK)
+	scc.SetName(fmt.Sprintf("dpu-ovnkube-node-%s", namespace))
+	return utils.DeleteObject(r.Client, scc)
+}
+
+// resolveImage pins image to the digest of its tag. The image is used as is
+// when it cannot be resolved.
+func resolveImage(ctx context.Context, resolver *images.Resolver, image string) string {
+	if resolver == nil {
+		return image
+	}
+	resolved, err := resolver.Resolve(ctx, image)
+	if err != nil {
+		logger.Info("Fail to resolve the image digest, using the tag", "image", image, "error", err.Error())
+	}
+	return resolved
+}
+
+// checkImageArchitecture returns an error if image is known not to be
+// available for the architecture of the DPUs. A failure to read the
+// architectures of image is only logged.
+func checkImageArchitecture(ctx context.Context, resolver *images.Resolver, image string) error {
+	if resolver == nil || utils.SimulateHardware {
+		return nil
+	}
+	architectures, err := resolver.Architectures(ctx, image)
+	if err != nil {
+		logger.Info("Fail to read the image architectures", "image", image, "error", err.Error())
+		return nil
+	}
+	for _, arch := range architectures {
+		if arch == utils.DpuArchitecture {
+			return nil
+		}
+	}
+	return fmt.Errorf("image %s is not available for %s, only for %s", image, utils.DpuArchitecture, strings.Join(architectures, ", "))
+}
+
+// checkDaemonSetArchitecture checks the images of the containers of the
+// DaemonSet obj with checkImageArchitecture
+func checkDaemonSetArchitecture(ctx context.Context, resolver *images.Resolver, obj *unstructured.Unstructured) error {
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", field)
+		if err != nil {
+			return err
+		}
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			image, _ := container["image"].(string)
+			if err := checkImageArchitecture(ctx, resolver, image); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validatePoolName rejects the pools managed by OpenShift. The same rule is
+// enforced by the CRD validation.
+func validatePoolName(poolName string) error {
+	if poolName == "master" || poolName == "worker" {
+		return fmt.Errorf("%s pools is not allowed", poolName)
+	}
+	return nil
+}
+
+// tenantNamespace returns the namespace of OVN-Kubernetes in the tenant
+// cluster of cfg. Without a DpuClusterConfig, e.g. for the nodes matching
+// none, the TENANT_NAMESPACE environment variable is still honoured.
+func tenantNamespace(cfg *dpuv1alpha1.DpuClusterConfig) string {
+	if cfg != nil && cfg.Spec.TenantNamespace != "" {
+		return cfg.Spec.TenantNamespace
+	}
+	if utils.TenantNamespace != "" {
+		return utils.TenantNamespace
+	}
+	return utils.DefaultTenantNamespace
+}
+
+// priorityClassName returns the PriorityClass of the ovnkube-node and drain
+// blocker pods of cfg, so that they are not evicted under node pressure
+func priorityClassName(cfg *dpuv1alpha1.DpuClusterConfig) string {
+	if cfg != nil && cfg.Spec.PriorityClassName != "" {
+		return cfg.Spec.PriorityClassName
+	}
+	return defaultPriorityClassName
+}
+
+// ovsMetricsPort returns the port of the OVS metrics endpoint of the
+// ovnkube-node pods of cfg
+func ovsMetricsPort(cfg *dpuv1alpha1.DpuClusterConfig) int32 {
+	if cfg.Spec.OvsMetrics != nil && cfg.Spec.OvsMetrics.Port != 0 {
+		return cfg.Spec.OvsMetrics.Port
+	}
+	return 9105
+}
+
+// tenantKubeconfigKey returns the preferred key of the tenant kubeconfig in
+// the secret referred by spec.kubeConfigFile. HyperShift stores the admin
+// kubeconfig of a HostedCluster under the "kubeconfig" key.
+func tenantKubeconfigKey(cfg *dpuv1alpha1.DpuClusterConfig) string {
+	if cfg.Spec.HostedCluster != nil && cfg.Spec.KubeConfigPath == "" {
+		return utils.HostedClusterKubeconfigKey
+	}
+	return utils.KubeconfigKey
+}
+
 func dbList(masterIPs []string, port string) string {
 	addrs := make([]string, len(masterIPs))
 	for i, ip := range masterIPs {
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+//+kubebuilder:rbac:groups=dpu.openshift.io,resources=dpuconnectivitychecks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dpu.openshift.io,resources=dpuconnectivitychecks/status,verbs=get;update;patch
+
+// defaultConnectivityCheckInterval is the period of the checks when the
+// spec does not set it
+const defaultConnectivityCheckInterval = 30 * time.Second
+
+// connectivityCheckConditions maps the conditions of the checker pods to the
+// ones of the DpuConnectivityCheck
+var connectivityCheckConditions = map[string]string{
+	utils.PodToPodCondition:     dpuv1alpha1.ConnectivityCheckPodToPod,
+	utils.PodToServiceCondition: dpuv1alpha1.ConnectivityCheckPodToService,
+}
+
+func connectivityCheckInterval(cfg *dpuv1alpha1.DpuClusterConfig) time.Duration {
+	if cfg.Spec.ConnectivityCheck == nil || cfg.Spec.ConnectivityCheck.Interval == nil {
+		return defaultConnectivityCheckInterval
+	}
+	return cfg.Spec.ConnectivityCheck.Interval.Duration
+}
+
+// syncConnectivityCheck deploys the connectivity checker pods on the tenant
+// workers backed by a DPU when it is enabled in the spec, and collects their
+// results in the DpuConnectivityCheck named after the DpuClusterConfig. The
+// checker pods and the results are removed otherwise.
+func (r *DpuClusterConfigReconciler) syncConnectivityCheck(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	tenantClient, err := r.auditedTenantClient(cfg, "sync the connectivity check")
+	if err != nil {
+		return err
+	}
+
+	if cfg.Spec.ConnectivityCheck == nil {
+		objMeta := metav1.ObjectMeta{Name: utils.ConnectivityCheckName, Namespace: tenantNamespace(cfg)}
+		if err := deleteTenantObjects(tenantClient,
+			&appsv1.DaemonSet{ObjectMeta: objMeta},
+			&corev1.Service{ObjectMeta: objMeta},
+			&corev1.ServiceAccount{ObjectMeta: objMeta},
+			&rbacv1.Role{ObjectMeta: objMeta},
+			&rbacv1.RoleBinding{ObjectMeta: objMeta},
+		); err != nil {
+			return err
+		}
+		check := &dpuv1alpha1.DpuConnectivityCheck{ObjectMeta: metav1.ObjectMeta{Name: cfg.Name, Namespace: cfg.Namespace}}
+		return utils.DeleteObject(r.Client, check)
+	}
+
+	logger.Info("Start to sync the connectivity check daemonset")
+	nodeSelector := cfg.Spec.ConnectivityCheck.NodeSelector
+	if len(nodeSelector) == 0 {
+		nodeSelector = map[string]string{utils.TenantNodeDpuLabel: "true"}
+	}
+	data := render.MakeRenderData()
+	data.Data["Namespace"] = tenantNamespace(cfg)
+	data.Data["Image"] = cfg.Spec.ConnectivityCheck.Image
+	data.Data["Interval"] = connectivityCheckInterval(cfg).String()
+	data.Data["NodeSelector"] = nodeSelector
+	if err := applyTenantManifests(ctx, tenantClient, cfg, utils.ConnectivityCheckPath, &data, nil); err != nil {
+		return err
+	}
+	return r.collectConnectivityCheck(ctx, tenantClient, cfg)
+}
+
+// collectConnectivityCheck records the conditions of the checker pods in the
+// status of the DpuConnectivityCheck, by tenant node. The status is only
+// written when a result changed.
+func (r *DpuClusterConfigReconciler) collectConnectivityCheck(ctx context.Context, tenantClient client.Client, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	pods := &corev1.PodList{}
+	if err := tenantClient.List(ctx, pods, client.InNamespace(tenantNamespace(cfg)), client.MatchingLabels{"app": utils.ConnectivityCheckName}); err != nil {
+		return err
+	}
+
+	check := &dpuv1alpha1.DpuConnectivityCheck{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}, check)
+	if apierrors.IsNotFound(err) {
+		check = &dpuv1alpha1.DpuConnectivityCheck{ObjectMeta: metav1.ObjectMeta{Name: cfg.Name, Namespace: cfg.Namespace}}
+		if err := ctrl.SetControllerReference(cfg, check, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, check); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	previous := map[string][]metav1.Condition{}
+	for _, n := range check.Status.Nodes {
+		previous[n.Node] = n.Conditions
+	}
+	var nodes []dpuv1alpha1.ConnectivityCheckNodeStatus
+	failingNodes := int32(0)
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		status := dpuv1alpha1.ConnectivityCheckNodeStatus{Node: pod.Spec.NodeName, Pod: pod.Name}
+		for _, c := range previous[pod.Spec.NodeName] {
+			status.Conditions = append(status.Conditions, *c.DeepCopy())
+		}
+		for podCondition, conditionType := range connectivityCheckConditions {
+			condition := metav1.Condition{
+				Type:    conditionType,
+				Status:  metav1.ConditionUnknown,
+				Reason:  "NotReported",
+				Message: "the checker pod did not report the check yet",
+			}
+			for _, c := range pod.Status.Conditions {
+				if string(c.Type) == podCondition {
+					condition.Status = metav1.ConditionStatus(c.Status)
+					condition.Reason = c.Reason
+					condition.Message = c.Message
+					condition.LastTransitionTime = c.LastTransitionTime
+				}
+			}
+			meta.SetStatusCondition(&status.Conditions, condition)
+		}
+		sort.Slice(status.Conditions, func(i, j int) bool { return status.Conditions[i].Type < status.Conditions[j].Type })
+		for _, c := range status.Conditions {
+			if c.Status == metav1.ConditionFalse {
+				failingNodes++
+				break
+			}
+		}
+		nodes = append(nodes, status)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Node < nodes[j].Node })
+	if equality.Semantic.DeepEqual(nodes, check.Status.Nodes) && failingNodes == check.Status.FailingNodes && check.Status.LastUpdateTime != nil {
+		return nil
+	}
+	now := metav1.Now()
+	check.Status.Nodes = nodes
+	check.Status.FailingNodes = failingNodes
+	check.Status.LastUpdateTime = &now
+	return r.Status().Update(ctx, check)
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// syncDocaTelemetry deploys the DOCA telemetry service on the DPU nodes when
+// it is enabled in the spec, and removes it otherwise.
+func (r *DpuClusterConfigReconciler) syncDocaTelemetry(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	if cfg.Spec.DocaTelemetry == nil {
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: utils.DocaTelemetryDsName, Namespace: cfg.Namespace}}
+		return utils.DeleteObject(r.Client, ds)
+	}
+
+	logger.Info("Start to sync the DOCA telemetry daemonset")
+	nodeSelector, err := r.getDpuNodeSelector(cfg)
+	if err != nil {
+		return err
+	}
+	port := cfg.Spec.DocaTelemetry.PrometheusPort
+	if port == 0 {
+		port = 9100
+	}
+
+	data := render.MakeRenderData()
+	data.Data["Namespace"] = cfg.Namespace
+	data.Data["DocaTelemetryImage"] = cfg.Spec.DocaTelemetry.Image
+	data.Data["PrometheusPort"] = port
+
+	objs, err := renderDir(ctx, utils.DocaTelemetryPath, &data)
+	if err != nil {
+		logger.Error(err, "Fail to render the DOCA telemetry manifests")
+		return err
+	}
+	return r.applyObjects(ctx, cfg, objs, nodeSelector)
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// syncDpuHost deploys ovnkube in dpu-host mode on the tenant workers backed
+// by a DPU when it is enabled in the spec, and removes it otherwise. It runs
+// the ovnkube image of the DPU nodes of the main pool, so that both halves of
+// ovn-kubernetes are upgraded together.
+func (r *DpuClusterConfigReconciler) syncDpuHost(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	tenantClient, err := r.auditedTenantClient(cfg, "sync the ovnkube dpu-host daemonset")
+	if err != nil {
+		return err
+	}
+
+	if cfg.Spec.DpuHost == nil {
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: utils.OvnkubeDpuHostDsName, Namespace: tenantNamespace(cfg)}}
+		return deleteTenantObjects(tenantClient, ds)
+	}
+
+	logger.Info("Start to sync the ovnkube dpu-host daemonset")
+	image := cfg.Spec.OvnkubeImage
+	if image == "" {
+		if image, err = r.getOvnkubeImage(); err != nil {
+			return err
+		}
+	}
+	nodeSelector := cfg.Spec.DpuHost.NodeSelector
+	if len(nodeSelector) == 0 {
+		nodeSelector = map[string]string{utils.TenantNodeDpuLabel: "true"}
+	}
+	data := render.MakeRenderData()
+	data.Data["DaemonSetName"] = utils.OvnkubeDpuHostDsName
+	data.Data["Namespace"] = tenantNamespace(cfg)
+	data.Data["OvnKubeImage"] = resolveImage(ctx, r.Images, image)
+	data.Data["ManagementPortNetdev"] = cfg.Spec.DpuHost.ManagementPortNetdev
+	data.Data["NodeSelector"] = nodeSelector
+	setTenantNetworkRenderData(cfg, r.getTenantNetworkSettings(cfg), &data)
+
+	// The DaemonSet mounts the ovnkube-config of the tenant cluster, which
+	// is the one mirrored into the namespace of the DpuClusterConfig
+	ovnkubeConfig, err := r.getOvnkubeConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return applyTenantManifests(ctx, tenantClient, cfg, utils.OvnkubeDpuHostPath, &data, func(obj *unstructured.Unstructured) error {
+		if obj.GetKind() != "DaemonSet" {
+			return nil
+		}
+		return setDaemonSetConfigHash(obj, ovnkubeConfigHash(ovnkubeConfig))
+	})
+}
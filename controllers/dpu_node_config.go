@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// syncDpuNodeOverrides writes the environment of the ovnkube-node pods of the
+// DPU nodes with a DpuNodeConfig in a ConfigMap, one key per node, and
+// restarts the ovnkube-node pods of the nodes whose environment changed.
+func (r *DpuClusterConfigReconciler) syncDpuNodeOverrides(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, nodeConfigs []dpuv1alpha1.DpuNodeConfig) error {
+	data := dpuNodeOverrides(nodeConfigs)
+	current := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: utils.CmNameDpuNodeOverrides}, current)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	// The pods created along with the ConfigMap read it already
+	changed := []string{}
+	if err == nil {
+		for node, env := range current.Data {
+			if data[node] != env {
+				changed = append(changed, node)
+			}
+		}
+		for node := range data {
+			if _, found := current.Data[node]; !found {
+				changed = append(changed, node)
+			}
+		}
+	}
+
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.CmNameDpuNodeOverrides,
+			Namespace: cfg.Namespace,
+		},
+		Data: data,
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cm)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	if err := ctrl.SetControllerReference(cfg, obj, r.Scheme); err != nil {
+		return err
+	}
+	setCommonMetadata(cfg, obj)
+	if err := applyObject(ctx, r.Client, obj); err != nil {
+		return err
+	}
+
+	sort.Strings(changed)
+	for _, node := range changed {
+		if err := r.restartOvnkubeNodePods(ctx, cfg.Namespace, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restartOvnkubeNodePods deletes the ovnkube-node pods of node, so that they
+// are recreated with the current environment of the node
+func (r *DpuClusterConfigReconciler) restartOvnkubeNodePods(ctx context.Context, namespace, node string) error {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{"app": "ovnkube-node"}); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != node {
+			continue
+		}
+		logger.Info("Restart the ovnkube-node pod to apply the DpuNodeConfig", "pod", pod.Name, "node", node)
+		if err := r.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// dpuNodeOverrides renders the environment file sourced by the ovnkube-node
+// container of each DPU node with a DpuNodeConfig, keyed by node name
+func dpuNodeOverrides(nodeConfigs []dpuv1alpha1.DpuNodeConfig) map[string]string {
+	data := map[string]string{}
+	for _, nc := range nodeConfigs {
+		lines := []string{}
+		for _, env := range nc.Spec.Env {
+			lines = append(lines, env.Name+"="+shellQuote(env.Value))
+		}
+		// The variables of the operator are set last, so that they win
+		if nc.Spec.HostPFRepresentor != "" {
+			lines = append(lines, "HOST_PF_REPRESENTOR="+shellQuote(nc.Spec.HostPFRepresentor))
+		}
+		if nc.Spec.Zone != "" {
+			lines = append(lines, "OVN_ZONE="+shellQuote(nc.Spec.Zone))
+		}
+		if len(lines) > 0 {
+			data[nc.Name] = strings.Join(lines, "\n") + "\n"
+		}
+	}
+	return data
+}
+
+// shellQuote quotes s for bash
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// excludedDpuNodes returns the names of the DPU nodes whose DpuNodeConfig
+// keeps the ovnkube-node pods off the node
+func excludedDpuNodes(nodeConfigs []dpuv1alpha1.DpuNodeConfig) []string {
+	names := []string{}
+	for _, nc := range nodeConfigs {
+		if nc.Spec.Excluded {
+			names = append(names, nc.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setDaemonSetExcludedNodes keeps the pods of the DaemonSet obj off the
+// given nodes
+func setDaemonSetExcludedNodes(obj *unstructured.Unstructured, nodes []string) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	ds := &appsv1.DaemonSet{}
+	if err := scheme.Scheme.Convert(obj, ds, nil); err != nil {
+		return err
+	}
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      corev1.LabelHostname,
+		Operator: corev1.NodeSelectorOpNotIn,
+		Values:   nodes,
+	}
+	spec := &ds.Spec.Template.Spec
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{requirement}}},
+		}
+	} else {
+		// The terms are ORed, each of them has to exclude the nodes
+		for i := range required.NodeSelectorTerms {
+			term := &required.NodeSelectorTerms[i]
+			term.MatchExpressions = append(term.MatchExpressions, requirement)
+		}
+	}
+	return scheme.Scheme.Convert(ds, obj, nil)
+}
+
+// dpuNodeConfigRequests enqueues the DpuClusterConfigs of the namespace of a
+// DpuNodeConfig or a DpuResource
+func (r *DpuClusterConfigReconciler) dpuNodeConfigRequests(obj client.Object) []reconcile.Request {
+	cfgList := &dpuv1alpha1.DpuClusterConfigList{}
+	if err := r.List(context.TODO(), cfgList, client.InNamespace(obj.GetNamespace())); err != nil {
+		logger.Error(err, "Fail to list the DpuClusterConfigs of the DpuNodeConfig", "namespace", obj.GetNamespace())
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, cfg := range cfgList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}})
+	}
+	return requests
+}
@@ -18,40 +18,78 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
-	nmoapiv1beta1 "github.com/medik8s/node-maintenance-operator/api/v1beta1"
+	"github.com/go-logr/logr"
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/audit"
+	"github.com/openshift/dpu-network-operator/pkg/images"
+	"github.com/openshift/dpu-network-operator/pkg/tracing"
 	"github.com/openshift/dpu-network-operator/pkg/utils"
-	"github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 type Config struct {
-	Image               string `envconfig:"IMAGE" default:"quay.io/centos/centos:stream8"`
+	// Image is the image of the drain blocker pods, which run sleep. The
+	// drain blocker pods run the pause command of the operator image when
+	// unset.
+	Image string `envconfig:"IMAGE"`
+	// OperatorImage is the image of the operator, it is read from the
+	// operator pod when unset
+	OperatorImage       string `envconfig:"OPERATOR_IMAGE"`
 	ServiceAccount      string `envconfig:"SERVICE_ACCOUNT" default:"dpu-network-operator-controller-manager"`
 	SingleClusterDesign bool   `envconfig:"SINGLE_CLUSTER_DESIGN" default:"false"`
 }
 
 type DpuNodeLifecycleController struct {
 	client.Client
-	Config       *Config
-	Scheme       *runtime.Scheme
-	Log          logrus.FieldLogger
-	tenantClient client.Client
-	Namespace    string
+	Config        *Config
+	Scheme        *runtime.Scheme
+	Log           logr.Logger
+	TenantConfigs *utils.TenantRestConfigStore
+	Images        *images.Resolver
+	// Recorder records the writes to the tenant clusters as events when set
+	Recorder record.EventRecorder
+	// Events records the reboots of the DPU nodes as events when set
+	Events record.EventRecorder
+	// tenantClients holds a client per tenant cluster not served by a
+	// tenant syncer, keyed by the namespace of the DpuClusterConfig serving
+	// it
+	tenantClients map[string]*tenantClient
+	// requeue decides when the reconciles are retried
+	requeue   *requeuePolicy
+	Namespace string
+}
+
+// tenantClient is a client of a tenant cluster built from the tenant
+// kubeconfig secret of the operator namespace, when no tenant syncer serves
+// the tenant cluster
+type tenantClient struct {
+	client.Client
 }
 
 const (
@@ -60,106 +98,313 @@ const (
 	maintenancePrefix       = "dpu-tenant-"
 	deploymentReplicaNumber = int32(1)
 	maxUnAvailableDefault   = int32(0)
+
+	// tenantClientRetryPeriod is how often a DPU node without tenant
+	// kubeconfig is checked again
+	tenantClientRetryPeriod = 1 * time.Minute
+	// tenantDrainPollPeriod is how often the drain of the tenant node is
+	// checked, as the tenant nodes are not watched
+	tenantDrainPollPeriod = 1 * time.Minute
+
+	// drainStateAnnotation shows on the DPU node where the coordination of
+	// its drain with the tenant node stands
+	drainStateAnnotation = "dpu.openshift.io/drain-state"
+	// drainStateBlocked means that the drain of the DPU node is blocked as
+	// long as it is schedulable
+	drainStateBlocked = "Blocked"
+	// drainStateWaitingForTenantDrain means that the DPU node is cordoned and
+	// waits for the tenant node to be drained
+	drainStateWaitingForTenantDrain = "WaitingForTenantDrain"
+	// drainStateTenantDrained means that the tenant node is drained and the
+	// DPU node can be drained
+	drainStateTenantDrained = "TenantDrained"
+)
+
+var (
+	// blockerPauseCommand is the command of the drain blocker pods run from
+	// the operator image, which only holds the operator binary
+	blockerPauseCommand = []string{"/manager", "pause"}
+	// blockerSleepCommand is the command of the drain blocker pods run from
+	// another image
+	blockerSleepCommand = []string{"/bin/sh", "-ec", "sleep infinity"}
 )
 
 //+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups="",resources=nodes/status,verbs=patch
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=nodemaintenance.medik8s.io,resources=nodemaintenances,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=nodemaintenance.kubevirt.io,resources=nodemaintenances,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *DpuNodeLifecycleController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := r.Log.WithFields(
-		logrus.Fields{
-			"node_name":          req.Name,
-			"operator namespace": r.Namespace,
-		})
-	defer log.Info("node controller lifecycle reconcile ended")
+	ctx, span := tracing.Start(ctx, "reconcile", "controller", "DpuNodeLifecycle", "request", req.NamespacedName)
+	result, err := r.reconcile(ctx, req)
+	span.End(err)
+	return result, err
+}
 
-	var err error
-	r.tenantClient, err = r.ensureTenantClient(log)
-	// if no tenant client, nothing to do, on error it will retry reconcile
-	if err != nil || r.tenantClient == nil {
-		return ctrl.Result{}, err
-	}
+func (r *DpuNodeLifecycleController) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("node", req.Name, "namespace", r.Namespace)
+	defer log.V(1).Info("node controller lifecycle reconcile ended")
 
-	log.Info("node controller lifecycle reconcile started")
+	log.V(1).Info("node controller lifecycle reconcile started")
 	namespace := r.Namespace
 	node := &corev1.Node{}
 	if err := r.Get(ctx, req.NamespacedName, node); err != nil {
-		log.WithError(err).Errorf("Failed to get node %s", req.Name)
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		if errors.IsNotFound(err) {
+			return r.requeue.Deleted(req)
+		}
+		return r.requeue.Retry(req, err)
+	}
+
+	// The nodes of the additional pools may not have the DPU worker label,
+	// a node matching any pool of a DpuClusterConfig is a DPU node
+	cfg, err := r.getDpuClusterConfig(ctx, node)
+	if err != nil {
+		return r.requeue.Retry(req, err)
+	}
+	if _, hasDpuLabel := node.Labels[dpuNodeLabel]; !hasDpuLabel && cfg == nil {
+		log.V(1).Info("Node is not dpu, skip")
+		return r.requeue.Done(req)
 	}
 
-	if _, hasDpuLabel := node.Labels[dpuNodeLabel]; !hasDpuLabel {
-		log.Debugf("Node %s is not dpu, skip", node.Name)
-		return ctrl.Result{}, nil
+	// After a reboot, the drain of the tenant node is evaluated again below
+	// from the current state of the DPU node, deleting the NodeMaintenance
+	// left from before the reboot once the DPU node is schedulable
+	rebooted, err := r.syncBootID(ctx, log, node)
+	if err != nil {
+		return r.requeue.Retry(req, err)
+	}
+	if rebooted {
+		if err := r.setDrainState(ctx, node, ""); err != nil {
+			return r.requeue.Retry(req, err)
+		}
+	}
+
+	// The dumps are mostly useful while the tenant cluster is unreachable,
+	// they are requested before the tenant node is looked up
+	if err := r.syncOvsDump(ctx, log, cfg, node); err != nil {
+		return r.requeue.Retry(req, err)
+	}
+	if err := r.syncOvnSouthboundCondition(ctx, log, cfg, node); err != nil {
+		return r.requeue.Retry(req, err)
+	}
+	tenantClient, err := r.ensureTenantClient(ctx, log, cfg)
+	if err != nil {
+		return r.requeue.Retry(req, err)
+	}
+	// the tenant kubeconfig secret is not watched, poll until it shows up
+	if tenantClient == nil {
+		return r.requeue.Poll(req, tenantClientRetryPeriod)
+	}
+	// the tenant cluster is left alone while its heartbeat fails, instead of
+	// waiting for each call to time out
+	if cfg != nil {
+		if unreachable := r.TenantConfigs.Unreachable(cfg.Namespace); unreachable != nil {
+			log.Info("Tenant cluster is unreachable, skip the tenant node", "error", unreachable.Error())
+			return r.requeue.Poll(req, tenantClientRetryPeriod)
+		}
 	}
 
 	tenantNode, err := utils.GetMatchedTenantNode(node.Name)
 	if err != nil {
-		r.Log.WithError(err).Errorf("failed to get tenant node that matches %s", node.Name)
 		// in order not to retry forever in case of bad configuration, lets just return
 		// changing configmap will in any case require pod restart
-		return ctrl.Result{}, nil
+		return r.requeue.Terminal(req, fmt.Errorf("failed to get the tenant node matching %s: %v", node.Name, err))
 	}
 
-	exists, err := r.doesTenantNodeExist(tenantNode)
+	exists, err := r.doesTenantNodeExist(tenantClient, tenantNode)
 	if err != nil {
-		return ctrl.Result{}, err
+		r.invalidateTenantClient(tenantClient, err)
+		return r.requeue.Retry(req, err)
 	}
 	if !exists {
-		r.Log.Infof("Tenant node %s doesn't exists", tenantNode)
-		return ctrl.Result{}, r.cleanup(r.Log, node, namespace)
+		log.Info("Tenant node doesn't exist", "tenantNode", tenantNode)
+		if err := r.setDrainState(ctx, node, ""); err != nil {
+			return r.requeue.Retry(req, err)
+		}
+		if err := r.cleanup(log, node, namespace); err != nil {
+			return r.requeue.Retry(req, err)
+		}
+		return r.requeue.Done(req)
 	}
-	r.Log.Infof("Found tenant node %s", tenantNode)
+	log.Info("Found tenant node", "tenantNode", tenantNode)
 
-	if err := r.ensureBlockingDeploymentExists(log, node, namespace); err != nil {
-		return ctrl.Result{}, err
+	if err := r.ensureBlockingDeploymentExists(log, cfg, node, namespace); err != nil {
+		return r.requeue.Retry(req, err)
 	}
 
 	// create pbd before tenant host in order to block drain
 	// even if something is wrongly configured
 	expectedPDB := r.buildPDB(node, namespace)
+	setCommonMetadata(cfg, expectedPDB)
 	pdb, err := r.getOrCreatePDB(log, node, expectedPDB)
 	if err != nil {
-		log.WithError(err).Errorf("Failed to get pdb %s", expectedPDB.Name)
-		return ctrl.Result{}, err
+		log.Error(err, "Failed to get pdb", "pdb", expectedPDB.Name)
+		return r.requeue.Retry(req, err)
 	}
 
-	tenantShouldBeDrained := r.shouldTenantHostBeDrained(node)
-	tenantInRequiredState, err := r.ensureNodeDrainState(tenantNode, tenantShouldBeDrained)
+	// The NodeMaintenance API is discovered at each reconcile, so that an
+	// upgrade of the node-maintenance-operator switching to another version
+	// is followed
+	nmGVK, err := nodeMaintenanceGVK(tenantClient)
 	if err != nil {
-		return ctrl.Result{}, err
+		r.invalidateTenantClient(tenantClient, err)
+		return r.requeue.Retry(req, err)
+	}
+	if err := r.syncNodeMaintenanceCondition(ctx, node, nmGVK); err != nil {
+		return r.requeue.Retry(req, err)
+	}
+
+	// The drain is coordinated before the other writes to the tenant node,
+	// so that their failures cannot hold it back
+	tenantDrainPending := false
+	if nmGVK != nil {
+		tenantShouldBeDrained := r.shouldTenantHostBeDrained(node)
+		auditedClient := audit.NewTenantClient(tenantClient, fmt.Sprintf("coordinate the drain of DPU node %s", node.Name), node, r.Recorder)
+		tenantInRequiredState, err := r.ensureNodeDrainState(auditedClient, *nmGVK, tenantNamespace(cfg), tenantNode, tenantShouldBeDrained)
+		if err != nil {
+			r.invalidateTenantClient(tenantClient, err)
+			return r.requeue.Retry(req, err)
+		}
+
+		expectedPDB.Spec.MaxUnavailable.IntVal = r.getExpectedMaxUnavailable(tenantInRequiredState && tenantShouldBeDrained)
+		if err := r.ensurePDBSpecIsAsExpected(log, pdb, expectedPDB); err != nil {
+			return r.requeue.Retry(req, err)
+		}
+
+		drainState := drainStateBlocked
+		if tenantShouldBeDrained {
+			drainState = drainStateWaitingForTenantDrain
+			if tenantInRequiredState {
+				drainState = drainStateTenantDrained
+			}
+		}
+		if err := r.setDrainState(ctx, node, drainState); err != nil {
+			return r.requeue.Retry(req, err)
+		}
+		tenantDrainPending = !tenantInRequiredState && tenantShouldBeDrained
+	}
+
+	// The failures of the other writes are retried once they were all
+	// attempted
+	tenantErrs := []error{}
+	labelClient := audit.NewTenantClient(tenantClient, fmt.Sprintf("label the tenant node of DPU node %s", node.Name), node, r.Recorder)
+	if err := r.syncTenantNodeLabels(ctx, log, cfg, labelClient, tenantNode); err != nil {
+		tenantErrs = append(tenantErrs, err)
 	}
 
-	expectedPDB.Spec.MaxUnavailable.IntVal = r.getExpectedMaxUnavailable(tenantInRequiredState && tenantShouldBeDrained)
-	if err := r.ensurePDBSpecIsAsExpected(log, pdb, expectedPDB); err != nil {
-		return ctrl.Result{}, err
+	readyClient := audit.NewTenantClient(tenantClient, fmt.Sprintf("report the DPU network readiness of the tenant node of DPU node %s", node.Name), node, r.Recorder)
+	if err := r.syncTenantNetworkReady(ctx, log, cfg, node, readyClient, tenantNode); err != nil {
+		tenantErrs = append(tenantErrs, err)
 	}
 
+	identityClient := audit.NewTenantClient(tenantClient, fmt.Sprintf("approve the ovnkube-node certificate of DPU node %s", node.Name), node, r.Recorder)
+	identityPending, err := r.syncNodeIdentity(ctx, log, cfg, node, identityClient, tenantNode)
+	if err != nil {
+		tenantErrs = append(tenantErrs, err)
+	}
+
+	annotationClient := audit.NewTenantClient(tenantClient, fmt.Sprintf("heal the ovn-kubernetes annotations of the tenant node of DPU node %s", node.Name), node, r.Recorder)
+	annotationsManaged, err := r.syncTenantNodeAnnotations(ctx, log, cfg, annotationClient, tenantNode)
+	if err != nil {
+		tenantErrs = append(tenantErrs, err)
+	}
+
+	remediationWait, err := r.syncRemediation(ctx, log, cfg, node, tenantClient, tenantNode)
+	if err != nil {
+		tenantErrs = append(tenantErrs, err)
+	}
+
+	for _, err := range tenantErrs {
+		r.invalidateTenantClient(tenantClient, err)
+	}
+	if len(tenantErrs) > 0 {
+		return r.requeue.Retry(req, utilerrors.NewAggregate(tenantErrs))
+	}
+
+	if nmGVK == nil {
+		return r.requeue.Poll(req, tenantClientRetryPeriod)
+	}
 	// if tenant should be drained but it was not yet, we should retry reconcile as we don't listen on tenant nodes events
-	if !tenantInRequiredState && tenantShouldBeDrained {
-		return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+	if tenantDrainPending {
+		return r.requeue.Poll(req, tenantDrainPollPeriod)
 	}
 
-	return ctrl.Result{}, nil
+	// the blocker pods are not watched, poll until the pod is scheduled
+	scheduled, err := r.syncDrainBlockerCondition(ctx, log, node, namespace)
+	if err != nil {
+		return r.requeue.Retry(req, err)
+	}
+	if !scheduled {
+		return r.requeue.Poll(req, tenantDrainPollPeriod)
+	}
+
+	// the CSRs of the tenant cluster are not watched, poll them while
+	// ovnkube-node waits for its certificate
+	if identityPending {
+		return r.requeue.Poll(req, nodeIdentityPollPeriod)
+	}
+	// the DPU node is checked again once it may be deemed dead
+	if remediationWait > 0 && (!annotationsManaged || remediationWait < tenantNodeAnnotationsResyncPeriod) {
+		return r.requeue.Poll(req, remediationWait)
+	}
+	// the tenant node is not watched, its annotations are healed periodically
+	if annotationsManaged {
+		return r.requeue.Poll(req, tenantNodeAnnotationsResyncPeriod)
+	}
+	// the renewals of the per-node certificates are approved periodically
+	if cfg != nil && cfg.Spec.NodeIdentity != nil {
+		return r.requeue.Poll(req, nodeIdentityResyncPeriod)
+	}
+	return r.requeue.Done(req)
 }
 
 // Create deployment with that will run sleep infinity
 // This deployment with help of pdb will block drain of dpu node
-func (r *DpuNodeLifecycleController) ensureBlockingDeploymentExists(log logrus.FieldLogger, node *corev1.Node, namespace string) error {
-	log.Infof("Create blocking deployment if not exists")
-	expectedDeployment := r.buildDeployment(node, namespace)
-	_, err := utils.GetOrCreateObject(r.Client, expectedDeployment, r.Log)
-	return err
+func (r *DpuNodeLifecycleController) ensureBlockingDeploymentExists(log logr.Logger, cfg *dpuv1alpha1.DpuClusterConfig, node *corev1.Node, namespace string) error {
+	log.Info("Create blocking deployment if not exists")
+	expectedDeployment := r.buildDeployment(cfg, node, namespace)
+	setCommonMetadata(cfg, expectedDeployment)
+	container := &expectedDeployment.Spec.Template.Spec.Containers[0]
+	container.Image = resolveImage(context.TODO(), r.Images, container.Image)
+	if err := checkImageArchitecture(context.TODO(), r.Images, container.Image); err != nil {
+		return err
+	}
+	obj, err := utils.GetOrCreateObject(r.Client, expectedDeployment, log)
+	if err != nil {
+		return err
+	}
+	deployment := obj.(*appsv1.Deployment)
+	updated := syncMetadata(expectedDeployment, deployment)
+	podSpec, expectedPodSpec := &deployment.Spec.Template.Spec, &expectedDeployment.Spec.Template.Spec
+	if !equality.Semantic.DeepEqual(podSpec.Tolerations, expectedPodSpec.Tolerations) ||
+		!equality.Semantic.DeepEqual(podSpec.Affinity, expectedPodSpec.Affinity) ||
+		!equality.Semantic.DeepEqual(podSpec.ImagePullSecrets, expectedPodSpec.ImagePullSecrets) ||
+		podSpec.Containers[0].Image != expectedPodSpec.Containers[0].Image ||
+		!equality.Semantic.DeepEqual(podSpec.Containers[0].Command, expectedPodSpec.Containers[0].Command) ||
+		podSpec.PriorityClassName != expectedPodSpec.PriorityClassName {
+		podSpec.PriorityClassName = expectedPodSpec.PriorityClassName
+		podSpec.Tolerations = expectedPodSpec.Tolerations
+		podSpec.Affinity = expectedPodSpec.Affinity
+		podSpec.ImagePullSecrets = expectedPodSpec.ImagePullSecrets
+		podSpec.Containers[0].Image = expectedPodSpec.Containers[0].Image
+		podSpec.Containers[0].Command = expectedPodSpec.Containers[0].Command
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+	log.Info("Update the blocking deployment")
+	return r.Update(context.TODO(), deployment)
 }
 
 // return dpu or create one in case it didn't exist
-func (r *DpuNodeLifecycleController) getOrCreatePDB(log logrus.FieldLogger, node *corev1.Node, expectedPDB *policyv1.PodDisruptionBudget) (*policyv1.PodDisruptionBudget, error) {
-	log.Infof("Get or create blocking pdb for node %s", node.Name)
-	pdb, err := utils.GetOrCreateObject(r.Client, expectedPDB, r.Log)
+func (r *DpuNodeLifecycleController) getOrCreatePDB(log logr.Logger, node *corev1.Node, expectedPDB *policyv1.PodDisruptionBudget) (*policyv1.PodDisruptionBudget, error) {
+	log.Info("Get or create blocking pdb")
+	pdb, err := utils.GetOrCreateObject(r.Client, expectedPDB, log)
 	if err != nil {
 		return nil, err
 	}
@@ -175,16 +420,17 @@ func (r *DpuNodeLifecycleController) getExpectedMaxUnavailable(allowedToDrain bo
 	return expectedMaxUnavailable
 }
 
-// Ensure pdb spec was not changed and is same as expected one
+// Ensure pdb spec and metadata were not changed and are same as expected ones
 // Set MaxUnavailable field in PDB to the expected value
 // More than 0 value will allow deployment eviction that will allow dpu to fulfill drain
-func (r *DpuNodeLifecycleController) ensurePDBSpecIsAsExpected(log logrus.FieldLogger, pdb, expectedPDB *policyv1.PodDisruptionBudget) error {
-	if equality.Semantic.DeepEqual(pdb.Spec, expectedPDB.Spec) {
-		log.Infof("No changes in pdb spec, MaxUnavailable is %d", expectedPDB.Spec.MaxUnavailable.IntVal)
+func (r *DpuNodeLifecycleController) ensurePDBSpecIsAsExpected(log logr.Logger, pdb, expectedPDB *policyv1.PodDisruptionBudget) error {
+	metadataChanged := syncMetadata(expectedPDB, pdb)
+	if !metadataChanged && equality.Semantic.DeepEqual(pdb.Spec, expectedPDB.Spec) {
+		log.V(1).Info("No changes in pdb spec", "maxUnavailable", expectedPDB.Spec.MaxUnavailable.IntVal)
 		return nil
 	}
 	pdb.Spec = expectedPDB.Spec
-	log.Infof("Setting pdb's spec to %+v", expectedPDB.Spec)
+	log.Info("Setting pdb's spec", "spec", expectedPDB.Spec)
 	err := r.Update(context.TODO(), pdb)
 	return err
 }
@@ -217,10 +463,14 @@ func (r *DpuNodeLifecycleController) buildPDB(node *corev1.Node, namespace strin
 	return &pdb
 }
 
-func (r *DpuNodeLifecycleController) buildDeployment(node *corev1.Node, namespace string) *appsv1.Deployment {
+func (r *DpuNodeLifecycleController) buildDeployment(cfg *dpuv1alpha1.DpuClusterConfig, node *corev1.Node, namespace string) *appsv1.Deployment {
 	labels := map[string]string{
 		"app": deploymentPrefix + node.Name,
 	}
+	image, command := r.Config.Image, blockerSleepCommand
+	if image == "" {
+		image, command = r.Config.OperatorImage, blockerPauseCommand
+	}
 	deployment := appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      deploymentPrefix + node.Name,
@@ -246,22 +496,37 @@ func (r *DpuNodeLifecycleController) buildDeployment(node *corev1.Node, namespac
 				},
 				Spec: corev1.PodSpec{
 					HostNetwork: true,
+					SecurityContext: &corev1.PodSecurityContext{
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
 					Containers: []corev1.Container{
 						{
 							Name:    "sleep-forever",
-							Image:   r.Config.Image,
-							Command: []string{"/bin/sh", "-ec", "sleep infinity"},
+							Image:   image,
+							Command: command,
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: pointer.Bool(false),
+								Capabilities: &corev1.Capabilities{
+									Drop: []corev1.Capability{"ALL"},
+								},
+							},
 						},
 					},
 					NodeSelector: map[string]string{
 						"kubernetes.io/hostname": node.Name,
 					},
 					ServiceAccountName: r.Config.ServiceAccount,
+					PriorityClassName:  priorityClassName(cfg),
 				},
 			},
 		},
 	}
 
+	setDrainBlockerImage(cfg, &deployment.Spec.Template.Spec)
+	setDrainBlockerScheduling(cfg, &deployment.Spec.Template.Spec)
+	requireDpuArchitecture(&deployment.Spec.Template.Spec)
 	return &deployment
 }
 
@@ -273,21 +538,21 @@ func (r *DpuNodeLifecycleController) shouldTenantHostBeDrained(node *corev1.Node
 // build the nmName
 // if it should be drained, drain it, if it should be undrained, undrain it.
 // return if node is in required state
-func (r *DpuNodeLifecycleController) ensureNodeDrainState(tenantNode string, shouldBeDrained bool) (bool, error) {
+func (r *DpuNodeLifecycleController) ensureNodeDrainState(tenantClient client.Client, nmGVK schema.GroupVersionKind, namespace, tenantNode string, shouldBeDrained bool) (bool, error) {
 	nmName := maintenancePrefix + tenantNode
 	if shouldBeDrained {
-		return r.drainTenantNode(nmName, tenantNode)
+		return r.drainTenantNode(tenantClient, nmGVK, namespace, nmName, tenantNode)
 	}
 
-	return r.unDrainTenantNode(nmName, tenantNode)
+	return r.unDrainTenantNode(tenantClient, nmGVK, namespace, nmName, tenantNode)
 }
 
-func (r *DpuNodeLifecycleController) doesTenantNodeExist(tenantNode string) (bool, error) {
+func (r *DpuNodeLifecycleController) doesTenantNodeExist(tenantClient client.Client, tenantNode string) (bool, error) {
 	namespacedName := types.NamespacedName{
 		Name: tenantNode,
 	}
 	obj := &corev1.Node{}
-	err := r.tenantClient.Get(context.TODO(), namespacedName, obj)
+	err := tenantClient.Get(context.TODO(), namespacedName, obj)
 	if err != nil {
 		return false, client.IgnoreNotFound(err)
 	}
@@ -296,18 +561,18 @@ func (r *DpuNodeLifecycleController) doesTenantNodeExist(tenantNode string) (boo
 
 // Create nodeMaintenance cr if not created yet
 // creating CR will say to NM operator to put node to maintenance/drain
-func (r *DpuNodeLifecycleController) drainTenantNode(nmName, tenantHostName string) (bool, error) {
-	r.Log.Infof("Start node %s draining", tenantHostName)
+func (r *DpuNodeLifecycleController) drainTenantNode(tenantClient client.Client, nmGVK schema.GroupVersionKind, namespace, nmName, tenantHostName string) (bool, error) {
+	r.Log.Info("Start node draining", "tenantNode", tenantHostName)
 	// Create CR if node should be drained and remove if not
-	nmAsObj, err := utils.GetOrCreateObject(r.tenantClient, r.buildNodeMaintenanceCR(nmName, tenantHostName), r.Log)
+	nmAsObj, err := utils.GetOrCreateObject(tenantClient, r.buildNodeMaintenanceCR(nmGVK, namespace, nmName, tenantHostName), r.Log)
 	if err != nil {
 		return false, err
 	}
 
-	nm := nmAsObj.(*nmoapiv1beta1.NodeMaintenance)
-	wasDrained := nm.Status.Phase == nmoapiv1beta1.MaintenanceSucceeded
+	phase, _, _ := unstructured.NestedString(nmAsObj.(*unstructured.Unstructured).Object, "status", "phase")
+	wasDrained := phase == nodeMaintenanceSucceeded
 	if wasDrained {
-		r.Log.Infof("Tenant node %s was drained", tenantHostName)
+		r.Log.Info("Tenant node was drained", "tenantNode", tenantHostName)
 	}
 
 	return wasDrained, nil
@@ -316,76 +581,192 @@ func (r *DpuNodeLifecycleController) drainTenantNode(nmName, tenantHostName stri
 // Deleting CR will move node from maintenance
 // Currently nodemaintenance operator doesn't save previous status of the node, in that case if node previously
 // was drained or cordoned it will become uncordon
-func (r *DpuNodeLifecycleController) unDrainTenantNode(nmName, tenantHostName string) (bool, error) {
-	r.Log.Infof("Start node %s unDraining", tenantHostName)
-	nm := &nmoapiv1beta1.NodeMaintenance{}
-	typedNM := types.NamespacedName{Name: nmName, Namespace: utils.TenantNamespace}
-	err := r.tenantClient.Get(context.TODO(), typedNM, nm)
+func (r *DpuNodeLifecycleController) unDrainTenantNode(tenantClient client.Client, nmGVK schema.GroupVersionKind, namespace, nmName, tenantHostName string) (bool, error) {
+	r.Log.Info("Start node unDraining", "tenantNode", tenantHostName)
+	nm := newNodeMaintenance(nmGVK, namespace, nmName)
+	typedNM := types.NamespacedName{Name: nmName, Namespace: namespace}
+	err := tenantClient.Get(context.TODO(), typedNM, nm)
 	if err != nil && !errors.IsNotFound(err) {
 		return false, err
 	}
 	// if nm cr exists we need to delete it
 	if err == nil {
-		r.Log.Infof("Tenant node %s should be uncordon, deleting NM cr", tenantHostName)
-		if err := r.tenantClient.Delete(context.TODO(), nm); err != nil {
-			r.Log.WithError(err).Errorf("Failed to delete node maintenance cr %s", nmName)
+		r.Log.Info("Tenant node should be uncordon, deleting NM cr", "tenantNode", tenantHostName)
+		if err := tenantClient.Delete(context.TODO(), nm); err != nil {
+			r.Log.Error(err, "Failed to delete node maintenance cr", "name", nmName)
 			return false, err
 		}
-		r.Log.Infof("Tenant node %s was unDrained", tenantHostName)
+		r.Log.Info("Tenant node was unDrained", "tenantNode", tenantHostName)
 	}
 
 	return true, nil
 }
 
-func (r *DpuNodeLifecycleController) buildNodeMaintenanceCR(name, tenantNodeHostname string) *nmoapiv1beta1.NodeMaintenance {
-	return &nmoapiv1beta1.NodeMaintenance{
-		TypeMeta: metav1.TypeMeta{},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: utils.TenantNamespace,
-		},
-		Spec: nmoapiv1beta1.NodeMaintenanceSpec{
-			NodeName: tenantNodeHostname,
-			Reason:   "Infra dpu is going to reboot",
-		}}
+func (r *DpuNodeLifecycleController) buildNodeMaintenanceCR(nmGVK schema.GroupVersionKind, namespace, name, tenantNodeHostname string) *unstructured.Unstructured {
+	nm := newNodeMaintenance(nmGVK, namespace, name)
+	nm.Object["spec"] = map[string]interface{}{
+		"nodeName": tenantNodeHostname,
+		"reason":   "Infra dpu is going to reboot",
+	}
+	return nm
 }
 
 // Return client that will handle hosts with dpu status
-func (r *DpuNodeLifecycleController) ensureTenantClient(log logrus.FieldLogger) (client.Client, error) {
-	if r.tenantClient != nil {
-		return r.tenantClient, nil
-	}
+func (r *DpuNodeLifecycleController) ensureTenantClient(ctx context.Context, log logr.Logger, cfg *dpuv1alpha1.DpuClusterConfig) (client.Client, error) {
 	if r.Config.SingleClusterDesign {
-		log.Infof("Single cluster design is on, tenant client is the same as local")
+		log.V(1).Info("Single cluster design is on, tenant client is the same as local")
 		return r.Client, nil
 	}
 
-	tenantKubeconfig, err := r.getTenantRestClientConfig()
+	// Without a matching DpuClusterConfig the tenant kubeconfig is looked up
+	// in the operator namespace, otherwise it comes from the tenant syncer
+	// of the DpuClusterConfig only
+	cfgNamespace := ""
+	if cfg != nil {
+		cfgNamespace = cfg.Namespace
+	}
+	// The clients of the tenant clusters served by a tenant syncer are
+	// shared with the other controllers, and rebuilt when the tenant
+	// kubeconfig is rotated
+	clients, err := r.TenantConfigs.Clients(cfgNamespace)
 	if err != nil {
-		log.WithError(err).Errorf("failed to get tenant kubeconfig")
+		log.Error(err, "Fail to create client for the tenant cluster")
+		return nil, err
+	}
+	if clients != nil {
+		delete(r.tenantClients, cfgNamespace)
+		return clients.Client, nil
+	}
+	if tc, ok := r.tenantClients[cfgNamespace]; ok {
+		return tc, nil
+	}
+
+	tenantKubeconfig, err := r.getTenantRestClientConfig(cfgNamespace)
+	if err != nil {
+		log.Error(err, "failed to get tenant kubeconfig")
 		return nil, err
 	}
 
 	if tenantKubeconfig == nil {
 		return nil, nil
 	}
-	tenantClient, err := client.New(tenantKubeconfig, client.Options{})
+	c, err := client.New(tenantKubeconfig, client.Options{})
 	if err != nil {
-		r.Log.WithError(err).Errorf("Fail to create client for the tenant cluster")
+		log.Error(err, "Fail to create client for the tenant cluster")
+		return nil, err
+	}
+	tc := &tenantClient{Client: c}
+	r.tenantClients[cfgNamespace] = tc
+	return tc, err
+}
+
+// invalidateTenantClient drops the cached tenant client when the tenant
+// cluster rejected its credentials, so that the next reconcile reads the
+// tenant kubeconfig again
+func (r *DpuNodeLifecycleController) invalidateTenantClient(c client.Client, err error) {
+	if !errors.IsUnauthorized(err) {
+		return
+	}
+	for ns, tc := range r.tenantClients {
+		if client.Client(tc) == c {
+			r.Log.Error(err, "Tenant cluster rejected the credentials, drop the tenant client", "namespace", ns)
+			delete(r.tenantClients, ns)
+		}
+	}
+	if ns, ok := r.TenantConfigs.InvalidateClientsOf(c); ok {
+		r.Log.Error(err, "Tenant cluster rejected the credentials, drop the shared tenant clients", "namespace", ns)
+	}
+}
+
+// getDpuClusterConfig returns the DpuClusterConfig with a pool whose
+// nodeSelector matches the node, with the overrides of the pool applied. Its
+// namespace identifies the tenant cluster served by the DPU. nil is returned
+// if none matches.
+func (r *DpuNodeLifecycleController) getDpuClusterConfig(ctx context.Context, node *corev1.Node) (*dpuv1alpha1.DpuClusterConfig, error) {
+	cfgList := &dpuv1alpha1.DpuClusterConfigList{}
+	if err := r.List(ctx, cfgList); err != nil {
 		return nil, err
 	}
-	nmoapiv1beta1.AddToScheme(tenantClient.Scheme())
-	return tenantClient, err
+	for i := range cfgList.Items {
+		cfg := &cfgList.Items[i]
+		for _, pool := range dpuPools(cfg) {
+			if pool.cfg.Spec.NodeSelector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pool.cfg.Spec.NodeSelector)
+			if err != nil {
+				r.Log.Error(err, "Invalid nodeSelector in DpuClusterConfig", "namespace", cfg.Namespace, "name", cfg.Name, "pool", pool.cfg.Spec.PoolName)
+				continue
+			}
+			if selector.Matches(labels.Set(node.Labels)) {
+				return pool.cfg, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// isDpuNode tells whether the node has the DPU worker label or matches a pool
+// of a DpuClusterConfig
+func (r *DpuNodeLifecycleController) isDpuNode(obj client.Object) bool {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return false
+	}
+	if _, ok := node.Labels[dpuNodeLabel]; ok {
+		return true
+	}
+	cfg, err := r.getDpuClusterConfig(context.Background(), node)
+	if err != nil {
+		r.Log.Error(err, "Failed to get the DpuClusterConfig of node", "node", node.Name)
+		return false
+	}
+	return cfg != nil
+}
+
+// dpuClusterConfigNodeRequests enqueues the nodes matching a pool of a
+// DpuClusterConfig, so that the nodes selected by a new or edited pool are
+// reconciled without waiting for an edit of the nodes
+func (r *DpuNodeLifecycleController) dpuClusterConfigNodeRequests(obj client.Object) []reconcile.Request {
+	cfg, ok := obj.(*dpuv1alpha1.DpuClusterConfig)
+	if !ok {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, pool := range dpuPools(cfg) {
+		if pool.cfg.Spec.NodeSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pool.cfg.Spec.NodeSelector)
+		if err != nil {
+			continue
+		}
+		nodes := &corev1.NodeList{}
+		if err := r.List(context.Background(), nodes, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			r.Log.Error(err, "Failed to list the nodes of pool", "namespace", cfg.Namespace, "pool", pool.cfg.Spec.PoolName)
+			continue
+		}
+		for _, node := range nodes.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}})
+		}
+	}
+	return requests
 }
 
 // Since, at this point, it's difficult to set up a fully functioning two-cluster design.
 // For that reason, allow to provide tenant-kubeconfig through
 // a secret. In this development/debugging mode, the DpuClusterConfigReconciler is disabled
 // while the dpu node controller just runs.
-func (r *DpuNodeLifecycleController) getTenantRestClientConfig() (*restclient.Config, error) {
-	// If TenantRestConfig was set by ovn controller we should use it
-	if utils.TenantRestConfig != nil {
-		return utils.TenantRestConfig, nil
+func (r *DpuNodeLifecycleController) getTenantRestClientConfig(cfgNamespace string) (*restclient.Config, error) {
+	// If the tenant rest config was set by ovn controller we should use it
+	if tenantRestConfig := r.TenantConfigs.Get(cfgNamespace); tenantRestConfig != nil {
+		return tenantRestConfig, nil
+	}
+	// The legacy secret may belong to another tenant cluster, a DPU node
+	// matching a DpuClusterConfig waits for its tenant syncer instead
+	if cfgNamespace != "" {
+		r.Log.Info("The tenant syncer has not registered the tenant kubeconfig yet, skipping", "namespace", cfgNamespace)
+		return nil, nil
 	}
 
 	tenantKubeconfigName := "tenant-kubeconfig"
@@ -395,39 +776,71 @@ func (r *DpuNodeLifecycleController) getTenantRestClientConfig() (*restclient.Co
 	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: tenantKubeconfigName, Namespace: utils.Namespace}, s)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			r.Log.Infof("No secret %s in %s, skipping", tenantKubeconfigName, utils.Namespace)
+			r.Log.Info("No tenant kubeconfig secret, skipping", "secret", tenantKubeconfigName, "namespace", utils.Namespace)
 			return nil, nil
 		}
-		r.Log.WithError(err).Warnf("Failed to get %s though it exists", tenantKubeconfigName)
+		r.Log.Error(err, "Failed to get the tenant kubeconfig secret though it exists", "secret", tenantKubeconfigName)
 		return nil, err
 	}
 
-	bytes, ok := s.Data["config"]
-	if !ok {
-		r.Log.WithError(err).Warnf("Failed to get %s, key \"config\" doesn't exist", tenantKubeconfigName)
+	_, bytes, err := utils.GetSecretKubeconfig(s, utils.KubeconfigKey)
+	if err != nil {
+		r.Log.Error(err, "Failed to get the tenant kubeconfig", "secret", tenantKubeconfigName)
+		return nil, err
+	}
+
+	tenantRestConfig, err := clientcmd.RESTConfigFromKubeConfig(bytes)
+	if err != nil {
 		return nil, err
 	}
+	return tracing.WrapTransport(tenantRestConfig, "tenant"), nil
+}
 
-	return clientcmd.RESTConfigFromKubeConfig(bytes)
+// setDrainState sets the drain state annotation of the DPU node, so that it
+// shows in `oc describe node`. The annotation is removed if state is empty.
+func (r *DpuNodeLifecycleController) setDrainState(ctx context.Context, node *corev1.Node, state string) error {
+	if node.Annotations[drainStateAnnotation] == state {
+		return nil
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	if state == "" {
+		delete(node.Annotations, drainStateAnnotation)
+	} else {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[drainStateAnnotation] = state
+	}
+	r.Log.Info("Setting drain state of node", "node", node.Name, "state", state)
+	return r.Patch(ctx, node, patch)
 }
 
-func (r *DpuNodeLifecycleController) cleanup(log logrus.FieldLogger, node *corev1.Node, namespace string) error {
-	log.Info("Cleaning blocking deployment for node %s", node.Name)
-	expectedDeployment := r.buildDeployment(node, namespace)
+func (r *DpuNodeLifecycleController) cleanup(log logr.Logger, node *corev1.Node, namespace string) error {
+	log.Info("Cleaning blocking deployment")
+	expectedDeployment := r.buildDeployment(nil, node, namespace)
 	err := utils.DeleteObject(r.Client, expectedDeployment)
 	if err != nil {
 		return err
 	}
-	log.Infof("Cleaning PDB for node %s", node.Name)
+	log.Info("Cleaning PDB")
 	expectedPDB := r.buildPDB(node, namespace)
 	return utils.DeleteObject(r.Client, expectedPDB)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DpuNodeLifecycleController) SetupWithManager(mgr ctrl.Manager) error {
+	r.tenantClients = map[string]*tenantClient{}
+	r.requeue = newRequeuePolicy(r.Log, "DpuNodeLifecycle")
+	// Only the DPU nodes are reconciled, the other nodes being skipped
+	// anyway, and the owned objects only on the edits of their spec
+	isDpuNode := predicate.NewPredicateFuncs(r.isDpuNode)
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Node{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&policyv1.PodDisruptionBudget{}).
+		For(&corev1.Node{}, builder.WithPredicates(isDpuNode, predicate.ResourceVersionChangedPredicate{})).
+		Owns(&appsv1.Deployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&policyv1.PodDisruptionBudget{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(dpuNodePodRequests),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&source.Kind{Type: &dpuv1alpha1.DpuClusterConfig{}}, handler.EnqueueRequestsFromMapFunc(r.dpuClusterConfigNodeRequests),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
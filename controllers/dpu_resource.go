@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/agent"
+)
+
+//+kubebuilder:rbac:groups=dpu.openshift.io,resources=dpuresources,verbs=get;list;watch
+//+kubebuilder:rbac:groups=dpu.openshift.io,resources=dpuresources/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+
+// dpuResourceFunctions returns the functions requested by the DpuResources
+// of the namespace of cfg, by DPU node. A DpuResource requesting the same
+// type of functions of the same PF on a node as another one, first by name,
+// is not applied on that node and reported as conflicting in its status.
+func (r *DpuClusterConfigReconciler) dpuResourceFunctions(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, nodeSelector map[string]string) (map[string][]agent.FunctionSettings, error) {
+	resources := &dpuv1alpha1.DpuResourceList{}
+	if err := r.List(ctx, resources, client.InNamespace(cfg.Namespace)); err != nil {
+		return nil, err
+	}
+	if len(resources.Items) == 0 {
+		return nil, nil
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabels(nodeSelector)); err != nil {
+		return nil, err
+	}
+	sort.Slice(resources.Items, func(i, j int) bool { return resources.Items[i].Name < resources.Items[j].Name })
+
+	functions := map[string][]agent.FunctionSettings{}
+	owners := map[string]string{}
+	for i := range resources.Items {
+		res := &resources.Items[i]
+		selector := labels.SelectorFromSet(res.Spec.NodeSelector)
+		// The selected nodes, along with the conflict preventing the
+		// functions to be applied, if any
+		selected := map[string]string{}
+		for _, node := range nodes.Items {
+			if !selector.Matches(labels.Set(node.Labels)) {
+				continue
+			}
+			key := fmt.Sprintf("%s/%d/%s", node.Name, res.Spec.PfID, res.Spec.Type)
+			if owner, found := owners[key]; found {
+				selected[node.Name] = fmt.Sprintf("conflicts with the DpuResource %s", owner)
+				continue
+			}
+			owners[key] = res.Name
+			selected[node.Name] = ""
+			functions[node.Name] = append(functions[node.Name], agent.FunctionSettings{
+				Resource: res.Name,
+				PfID:     res.Spec.PfID,
+				Type:     res.Spec.Type,
+				Count:    res.Spec.Count,
+				Bridge:   res.Spec.Bridge,
+			})
+		}
+		if err := r.updateDpuResourceStatus(ctx, res, selected); err != nil {
+			return nil, err
+		}
+	}
+	return functions, nil
+}
+
+// updateDpuResourceStatus removes the nodes no longer selected from the
+// status of the DpuResource, and reports the conflicts of the selected ones.
+// The other nodes are reported by their OVS agent.
+func (r *DpuClusterConfigReconciler) updateDpuResourceStatus(ctx context.Context, res *dpuv1alpha1.DpuResource, selected map[string]string) error {
+	nodes := []dpuv1alpha1.DpuResourceNodeStatus{}
+	reported := map[string]bool{}
+	for _, n := range res.Status.Nodes {
+		conflict, found := selected[n.Node]
+		if !found || (conflict != "" && n.Message != conflict) {
+			continue
+		}
+		nodes = append(nodes, n)
+		reported[n.Node] = true
+	}
+	for node, conflict := range selected {
+		if conflict != "" && !reported[node] {
+			nodes = append(nodes, dpuv1alpha1.DpuResourceNodeStatus{
+				Node:           node,
+				Message:        conflict,
+				LastUpdateTime: metav1.Now(),
+			})
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Node < nodes[j].Node })
+	if equality.Semantic.DeepEqual(nodes, res.Status.Nodes) {
+		return nil
+	}
+	res.Status.Nodes = nodes
+	return r.Status().Update(ctx, res)
+}
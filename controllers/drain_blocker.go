@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// drainBlockerScheduledCondition is the condition of the DPU nodes
+	// telling whether their drain blocker pod is scheduled. The drain of
+	// the DPU node is not blocked as long as it is not.
+	drainBlockerScheduledCondition corev1.NodeConditionType = "DpuDrainBlockerScheduled"
+)
+
+// setDrainBlockerImage sets the image of the drain blocker pods and its pull
+// secrets from cfg. The image defaults to the IMAGE environment variable, or
+// to the operator image.
+func setDrainBlockerImage(cfg *dpuv1alpha1.DpuClusterConfig, spec *corev1.PodSpec) {
+	if cfg == nil || cfg.Spec.DrainBlocker == nil {
+		return
+	}
+	if cfg.Spec.DrainBlocker.Image != "" {
+		spec.Containers[0].Image = cfg.Spec.DrainBlocker.Image
+		spec.Containers[0].Command = blockerSleepCommand
+	}
+	spec.ImagePullSecrets = cfg.Spec.DrainBlocker.DeepCopy().ImagePullSecrets
+}
+
+// setDrainBlockerScheduling sets the tolerations and the affinity of the
+// drain blocker pods from cfg. The pods tolerate all the taints by default.
+func setDrainBlockerScheduling(cfg *dpuv1alpha1.DpuClusterConfig, spec *corev1.PodSpec) {
+	spec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+	if cfg == nil || cfg.Spec.DrainBlocker == nil {
+		return
+	}
+	if len(cfg.Spec.DrainBlocker.Tolerations) > 0 {
+		spec.Tolerations = cfg.Spec.DrainBlocker.DeepCopy().Tolerations
+	}
+	if cfg.Spec.DrainBlocker.Affinity != nil {
+		spec.Affinity = cfg.Spec.DrainBlocker.Affinity.DeepCopy()
+	}
+}
+
+// syncDrainBlockerCondition sets the DpuDrainBlockerScheduled condition of
+// the DPU node from the PodScheduled condition of its drain blocker pod. It
+// returns false if the pod is not scheduled yet.
+func (r *DpuNodeLifecycleController) syncDrainBlockerCondition(ctx context.Context, log logr.Logger, node *corev1.Node, namespace string) (bool, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{"app": deploymentPrefix + node.Name}); err != nil {
+		return false, err
+	}
+	if len(pods.Items) == 0 {
+		return false, nil
+	}
+	condition := corev1.NodeCondition{
+		Type:   drainBlockerScheduledCondition,
+		Status: corev1.ConditionTrue,
+		Reason: "Scheduled",
+	}
+	for _, pod := range pods.Items {
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+				condition.Status = corev1.ConditionFalse
+				condition.Reason = c.Reason
+				condition.Message = c.Message
+			}
+		}
+	}
+
+	changed, err := r.setNodeCondition(ctx, node, condition)
+	if changed && condition.Status == corev1.ConditionFalse {
+		log.Info("The drain blocker pod cannot be scheduled, the drain of the DPU node is not blocked", "reason", condition.Reason, "message", condition.Message)
+	}
+	return condition.Status == corev1.ConditionTrue, err
+}
+
+// setNodeCondition sets the condition of the DPU node, unless it is already
+// set. It returns whether the condition changed.
+func (r *DpuNodeLifecycleController) setNodeCondition(ctx context.Context, node *corev1.Node, condition corev1.NodeCondition) (bool, error) {
+	return patchNodeCondition(ctx, r.Client, node, condition)
+}
+
+// patchNodeCondition sets the condition of a node of the cluster of c, unless
+// it is already set. It returns whether the condition changed.
+func patchNodeCondition(ctx context.Context, c client.Client, node *corev1.Node, condition corev1.NodeCondition) (bool, error) {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == condition.Type && cond.Status == condition.Status && cond.Reason == condition.Reason && cond.Message == condition.Message {
+			return false, nil
+		}
+	}
+	now := metav1.Now()
+	condition.LastHeartbeatTime = now
+	condition.LastTransitionTime = now
+	patch := client.StrategicMergeFrom(node.DeepCopy())
+	found := false
+	for i, cond := range node.Status.Conditions {
+		if cond.Type == condition.Type {
+			if cond.Status == condition.Status {
+				condition.LastTransitionTime = cond.LastTransitionTime
+			}
+			node.Status.Conditions[i] = condition
+			found = true
+		}
+	}
+	if !found {
+		node.Status.Conditions = append(node.Status.Conditions, condition)
+	}
+	return true, c.Status().Patch(ctx, node, patch)
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+const (
+	// dryRunAnnotation set to "true" on a DpuClusterConfig makes the operator
+	// render its manifests into a ConfigMap instead of applying them
+	dryRunAnnotation = "dpu.openshift.io/dry-run"
+
+	dryRunMachineConfigKey = "machineconfig.yaml"
+	dryRunOvnkubeNodeKey   = "ovnkube-node.yaml"
+)
+
+func isDryRun(cfg *dpuv1alpha1.DpuClusterConfig) bool {
+	return cfg.Annotations[dryRunAnnotation] == "true"
+}
+
+func dryRunConfigMapName(cfg *dpuv1alpha1.DpuClusterConfig) string {
+	return cfg.Name + "-dry-run"
+}
+
+// syncDryRun renders the MachineConfig and the ovnkube-node manifests of cfg
+// into the <name>-dry-run ConfigMap, so that they can be reviewed before
+// the rollout. The ovnkube-node manifests are only rendered once the tenant
+// cluster is known.
+func (r *DpuClusterConfigReconciler) syncDryRun(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	logger.Info("Dry run, render the manifests without applying them")
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dryRunConfigMapName(cfg),
+			Namespace: cfg.Namespace,
+		},
+		Data: map[string]string{},
+	}
+
+	if r.Platform.HasMachineConfig() {
+		manifests := []string{}
+		for _, pool := range dpuPools(cfg) {
+			mc, err := r.renderMachineConfig(ctx, pool.cfg)
+			if err != nil {
+				return err
+			}
+			manifest, err := toYAML(mc)
+			if err != nil {
+				return err
+			}
+			manifests = append(manifests, manifest)
+		}
+		cm.Data[dryRunMachineConfigKey] = strings.Join(manifests, "---\n")
+	}
+
+	if cfg.Spec.HostedCluster != nil || r.TenantConfigs.Get(cfg.Namespace) != nil {
+		objs, err := r.renderOvnkubeNode(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		manifests := []string{}
+		for _, obj := range objs {
+			manifest, err := toYAML(obj)
+			if err != nil {
+				return err
+			}
+			manifests = append(manifests, manifest)
+		}
+		if len(manifests) > 0 {
+			cm.Data[dryRunOvnkubeNodeKey] = strings.Join(manifests, "---\n")
+		}
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cm)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	if err := ctrl.SetControllerReference(cfg, obj, r.Scheme); err != nil {
+		return err
+	}
+	setCommonMetadata(cfg, obj)
+	return applyObject(ctx, r.Client, obj)
+}
+
+func toYAML(obj interface{}) (string, error) {
+	manifest, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(manifest), nil
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+// ovsOptionRegex matches the keys and values of the OVS other_config options
+// that can be set. They are passed unquoted to ovs-vsctl in the machine config
+// and the DPU host config daemonset.
+var ovsOptionRegex = regexp.MustCompile(`^[a-zA-Z0-9_.:-]+$`)
+
+// validateHardwareOffload checks the OVS other_config options of cfg
+func validateHardwareOffload(cfg *dpuv1alpha1.DpuClusterConfig) error {
+	for _, option := range ovsOtherConfig(cfg) {
+		if !ovsOptionRegex.MatchString(option.key) || !ovsOptionRegex.MatchString(option.value) {
+			return fmt.Errorf("invalid OVS other_config option %q=%q", option.key, option.value)
+		}
+	}
+	return nil
+}
+
+type ovsOption struct {
+	key   string
+	value string
+}
+
+func (o ovsOption) String() string {
+	return o.key + "=" + o.value
+}
+
+// ovsOtherConfig returns the OVS other_config options of the DPU nodes of
+// cfg, sorted by key. hw-offload is enabled unless it is explicitly disabled.
+func ovsOtherConfig(cfg *dpuv1alpha1.DpuClusterConfig) []ovsOption {
+	options := map[string]string{}
+	spec := cfg.Spec.HardwareOffload
+	if spec == nil {
+		spec = &dpuv1alpha1.HardwareOffloadSpec{}
+	}
+	for k, v := range spec.OtherConfig {
+		options[k] = v
+	}
+	options["hw-offload"] = strconv.FormatBool(spec.Enabled == nil || *spec.Enabled)
+	if spec.TcPolicy != "" {
+		options["tc-policy"] = spec.TcPolicy
+	}
+	if spec.MaxIdle != nil {
+		options["max-idle"] = strconv.Itoa(int(*spec.MaxIdle))
+	}
+
+	keys := make([]string, 0, len(options))
+	for k := range options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]ovsOption, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, ovsOption{key: k, value: options[k]})
+	}
+	return result
+}
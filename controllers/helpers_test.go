@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"os"
+	"testing"
+
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+// newFakeClientBuilder returns a builder of fake clients with the APIs used
+// by the controllers
+func newFakeClientBuilder() *fake.ClientBuilder {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(dpuv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(mcfgv1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme)
+}
+
+// newFakeClient returns a fake client holding objs
+func newFakeClient(objs ...client.Object) client.Client {
+	return newFakeClientBuilder().WithObjects(objs...).Build()
+}
+
+// newTestConfig returns a DpuClusterConfig of the dpu-bf2 pool
+func newTestConfig() *dpuv1alpha1.DpuClusterConfig {
+	return &dpuv1alpha1.DpuClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "tenant-a", UID: "uid-a"},
+		Spec: dpuv1alpha1.DpuClusterConfigSpec{
+			PoolName:     "dpu-bf2",
+			NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"node-role.kubernetes.io/dpu-bf2": ""}},
+		},
+	}
+}
+
+// chdirRepoRoot runs the test from the root of the repository, which the
+// bindata paths are relative to
+func chdirRepoRoot(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(".."); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// newTestCSR returns a pending CertificateSigningRequest of username for the
+// subject, signer and usages, with the given IP subject alternative names
+func newTestCSR(t *testing.T, name, username, signer string, subject pkix.Name, ips []net.IP, usages ...certificatesv1.KeyUsage) *certificatesv1.CertificateSigningRequest {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: subject, IPAddresses: ips}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+			SignerName: signer,
+			Username:   username,
+			Usages:     usages,
+		},
+	}
+}
+
+// isCSRApproved tells whether the CertificateSigningRequest was approved
+func isCSRApproved(csr *certificatesv1.CertificateSigningRequest) bool {
+	for _, c := range csr.Status.Conditions {
+		if c.Type == certificatesv1.CertificateApproved {
+			return true
+		}
+	}
+	return false
+}
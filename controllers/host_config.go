@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	ign3 "github.com/coreos/ignition/v2/config/v3_2"
+	ign3types "github.com/coreos/ignition/v2/config/v3_2/types"
+	mcrender "github.com/k8snetworkplumbingwg/sriov-network-operator/pkg/render"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"github.com/openshift/machine-config-operator/pkg/controller/common"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// hostConfigDir and hostConfigUnitsDir are the directories of the file and
+// unit templates of the hostConfig and timeSync, under the machine config
+// templates. They are only written when they are set, so that the
+// MachineConfig of the DPU nodes is unchanged otherwise.
+const (
+	hostConfigDir      = "host-config"
+	hostConfigUnitsDir = "host-config-units"
+)
+
+// setHostConfigRenderData adds the kernel modules, sysctls and udev rules of
+// the hostConfig of cfg to the machine config render data
+func setHostConfigRenderData(cfg *dpuv1alpha1.DpuClusterConfig, data *mcrender.RenderData) {
+	modules, sysctls, rules := []string{}, []string{}, []string{}
+	if spec := cfg.Spec.HostConfig; spec != nil {
+		modules = append(modules, spec.KernelModules...)
+		for k, v := range spec.Sysctls {
+			sysctls = append(sysctls, k+" = "+v)
+		}
+		sort.Strings(sysctls)
+		rules = append(rules, spec.UdevRules...)
+	}
+	data.Data["KernelModules"] = modules
+	data.Data["Sysctls"] = sysctls
+	data.Data["UdevRules"] = rules
+}
+
+// hostConfigFiles renders the files of the hostConfig, timeSync and
+// hostNetwork
+func hostConfigFiles(data *mcrender.RenderData) ([]string, error) {
+	files, err := renderFileTemplates(filepath.Join(utils.MachineConfigPath, hostConfigDir), data)
+	if err != nil {
+		return nil, err
+	}
+	keyfiles, err := renderNetworkKeyfiles(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, keyfiles...), nil
+}
+
+// mergeHostConfig adds the files and units of the hostConfig, timeSync and
+// hostNetwork to the ignition config of mc
+func mergeHostConfig(mc *mcfgv1.MachineConfig, data *mcrender.RenderData) error {
+	files, err := hostConfigFiles(data)
+	if err != nil {
+		return err
+	}
+	units, err := renderFileTemplates(filepath.Join(utils.MachineConfigPath, hostConfigUnitsDir), data)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 && len(units) == 0 {
+		return nil
+	}
+	extra, err := common.TranspileCoreOSConfigToIgn(files, units)
+	if err != nil {
+		return err
+	}
+	base := ign3types.Config{}
+	if err := json.Unmarshal(mc.Spec.Config.Raw, &base); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(ign3.Merge(base, *extra))
+	if err != nil {
+		return err
+	}
+	mc.Spec.Config.Raw = raw
+	return nil
+}
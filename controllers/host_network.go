@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+
+	mcrender "github.com/k8snetworkplumbingwg/sriov-network-operator/pkg/render"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// hostNetworkKeyfile is the template of the NetworkManager keyfile of a
+// secondary interface, rendered once per interface of the hostNetwork
+const hostNetworkKeyfile = "host-network/nmconnection.yaml"
+
+// nmKeyfile is the render data of the keyfile of a secondary interface
+type nmKeyfile struct {
+	Name string
+	IPv4 nmIPConfig
+	IPv6 nmIPConfig
+}
+
+// nmIPConfig is the ipv4 or ipv6 section of a keyfile
+type nmIPConfig struct {
+	Method    string
+	Addresses []string
+	Routes    []string
+}
+
+// validateHostNetwork checks the addresses and routes of the hostNetwork of
+// cfg
+func validateHostNetwork(cfg *dpuv1alpha1.DpuClusterConfig) error {
+	if cfg.Spec.HostNetwork == nil {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, iface := range cfg.Spec.HostNetwork.Interfaces {
+		if names[iface.Name] {
+			return fmt.Errorf("duplicate interface %q", iface.Name)
+		}
+		names[iface.Name] = true
+		for _, address := range iface.Addresses {
+			if _, _, err := net.ParseCIDR(address); err != nil {
+				return fmt.Errorf("invalid address %q of interface %q: %v", address, iface.Name, err)
+			}
+		}
+		for _, route := range iface.Routes {
+			_, dst, err := net.ParseCIDR(route.Destination)
+			if err != nil {
+				return fmt.Errorf("invalid route destination %q of interface %q: %v", route.Destination, iface.Name, err)
+			}
+			if route.Gateway == "" {
+				continue
+			}
+			gw := net.ParseIP(route.Gateway)
+			if gw == nil {
+				return fmt.Errorf("invalid gateway %q of interface %q", route.Gateway, iface.Name)
+			}
+			if (gw.To4() == nil) != (dst.IP.To4() == nil) {
+				return fmt.Errorf("gateway %q and destination %q of interface %q are of different address families", route.Gateway, route.Destination, iface.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// setHostNetworkRenderData adds the keyfiles of the hostNetwork of cfg to the
+// machine config render data. cfg is expected to be validated.
+func setHostNetworkRenderData(cfg *dpuv1alpha1.DpuClusterConfig, data *mcrender.RenderData) {
+	keyfiles := []nmKeyfile{}
+	if cfg.Spec.HostNetwork != nil {
+		for _, iface := range cfg.Spec.HostNetwork.Interfaces {
+			keyfiles = append(keyfiles, makeNmKeyfile(iface))
+		}
+	}
+	data.Data["NetworkInterfaces"] = keyfiles
+}
+
+func makeNmKeyfile(iface dpuv1alpha1.HostInterfaceSpec) nmKeyfile {
+	k := nmKeyfile{Name: iface.Name}
+	for _, address := range iface.Addresses {
+		ip, _, _ := net.ParseCIDR(address)
+		if ip.To4() != nil {
+			k.IPv4.Addresses = append(k.IPv4.Addresses, address)
+		} else {
+			k.IPv6.Addresses = append(k.IPv6.Addresses, address)
+		}
+	}
+	for _, route := range iface.Routes {
+		_, dst, _ := net.ParseCIDR(route.Destination)
+		ipv4 := dst.IP.To4() != nil
+		// The keyfile route format is dest,gateway,metric, an unspecified
+		// gateway standing for an on-link route
+		value := route.Destination
+		if route.Gateway != "" || route.Metric != nil {
+			gw := route.Gateway
+			if gw == "" && ipv4 {
+				gw = "0.0.0.0"
+			} else if gw == "" {
+				gw = "::"
+			}
+			value += "," + gw
+		}
+		if route.Metric != nil {
+			value += "," + strconv.Itoa(int(*route.Metric))
+		}
+		if ipv4 {
+			k.IPv4.Routes = append(k.IPv4.Routes, value)
+		} else {
+			k.IPv6.Routes = append(k.IPv6.Routes, value)
+		}
+	}
+	k.IPv4.Method = nmIPMethod(k.IPv4)
+	k.IPv6.Method = nmIPMethod(k.IPv6)
+	return k
+}
+
+// nmIPMethod returns manual for a family with static addresses, and auto,
+// i.e. DHCP or SLAAC, otherwise
+func nmIPMethod(ip nmIPConfig) string {
+	if len(ip.Addresses) > 0 {
+		return "manual"
+	}
+	return "auto"
+}
+
+// renderNetworkKeyfiles renders the NetworkManager keyfiles of the secondary
+// interfaces of the render data
+func renderNetworkKeyfiles(data *mcrender.RenderData) ([]string, error) {
+	files := []string{}
+	for _, k := range data.Data["NetworkInterfaces"].([]nmKeyfile) {
+		rendered, err := renderFileTemplate(filepath.Join(utils.MachineConfigPath, hostNetworkKeyfile), k)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, rendered)
+	}
+	return files, nil
+}
@@ -0,0 +1,611 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+const (
+	// defaultJoinTokenTTL is the lifetime of the bootstrap tokens, unless set
+	// in the spec
+	defaultJoinTokenTTL = time.Hour
+	// joinPollPeriod is how often the joining nodes are looked up until
+	// they register
+	joinPollPeriod = 10 * time.Second
+	// joinTokenLabel is set on the bootstrap tokens of the DpuClusterConfig
+	// of the namespace it holds
+	joinTokenLabel = "dpu.openshift.io/join"
+	// joinGroup is the group the bootstrap tokens authenticate as, bound
+	// to the node bootstrapper role by joinClusterRoleBinding
+	joinGroup              = "system:bootstrappers:dpu-network-operator"
+	joinClusterRoleBinding = "dpu-network-operator-join"
+	nodeBootstrapperRole   = "system:node-bootstrapper"
+	// joinKubeconfigKey is the key of the bootstrap kubeconfig of the
+	// token Secret of the namespace
+	joinKubeconfigKey = "kubeconfig"
+	// joiningNodesAnnotation tracks the nodes whose client certificate was
+	// approved on the token Secret of the namespace, along with the time of
+	// the approval. It is written before the approval, so that the serving
+	// certificates of the nodes are approved even if the status is not.
+	joiningNodesAnnotation = "dpu.openshift.io/joining-nodes"
+
+	bootstrapTokenNamespace = "kube-system"
+	bootstrapTokenPrefix    = "bootstrap-token-"
+	bootstrapUserPrefix     = "system:bootstrap:"
+	nodeUserPrefix          = "system:node:"
+	nodesGroup              = "system:nodes"
+	// tokenChars are the characters of the bootstrap tokens
+	tokenChars = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+var infrastructureGVK = schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "Infrastructure"}
+
+// joinTokenTTL returns the tokenTTL of the join of cfg
+func joinTokenTTL(cfg *dpuv1alpha1.DpuClusterConfig) time.Duration {
+	if cfg.Spec.Join.TokenTTL == nil || cfg.Spec.Join.TokenTTL.Duration <= 0 {
+		return defaultJoinTokenTTL
+	}
+	return cfg.Spec.Join.TokenTTL.Duration
+}
+
+// joinTokenSecretName returns the name of the Secret of the namespace of cfg
+// holding the bootstrap kubeconfig of the DPUs
+func joinTokenSecretName(cfg *dpuv1alpha1.DpuClusterConfig) string {
+	return cfg.Name + "-join"
+}
+
+// joinPool returns the pool the joining DPUs are added to
+func joinPool(cfg *dpuv1alpha1.DpuClusterConfig) (dpuPool, error) {
+	name := cfg.Spec.Join.PoolName
+	if name == "" {
+		name = cfg.Spec.PoolName
+	}
+	for _, pool := range dpuPools(cfg) {
+		if pool.cfg.Spec.PoolName == name {
+			return pool, nil
+		}
+	}
+	return dpuPool{}, fmt.Errorf("join pool %s is not a pool of the DpuClusterConfig", name)
+}
+
+// validateJoin returns an error when the joining DPUs cannot be added to
+// their pool by labeling them
+func validateJoin(cfg *dpuv1alpha1.DpuClusterConfig) error {
+	if cfg.Spec.Join == nil {
+		return nil
+	}
+	pool, err := joinPool(cfg)
+	if err != nil {
+		return err
+	}
+	if selector := pool.cfg.Spec.NodeSelector; selector == nil || len(selector.MatchLabels) == 0 {
+		return fmt.Errorf("the nodeSelector of join pool %s has no matchLabels to label the joining nodes with", pool.cfg.Spec.PoolName)
+	}
+	return nil
+}
+
+// randomToken returns n random characters of tokenChars
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	for i := range b {
+		c, err := rand.Int(rand.Reader, big.NewInt(int64(len(tokenChars))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = tokenChars[c.Int64()]
+	}
+	return string(b), nil
+}
+
+// tokenExpiration returns the expiration of the bootstrap token, the zero
+// time when it cannot be parsed
+func tokenExpiration(secret *corev1.Secret) time.Time {
+	expiration, err := time.Parse(time.RFC3339, string(secret.Data["expiration"]))
+	if err != nil {
+		return time.Time{}
+	}
+	return expiration
+}
+
+// syncJoin lets new DPUs join the infra cluster with a bootstrap token. It
+// returns how long until the state of the join has to be looked at again,
+// e.g. to rotate the token.
+func (r *DpuClusterConfigReconciler) syncJoin(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (time.Duration, error) {
+	tokens := &corev1.SecretList{}
+	if err := r.List(ctx, tokens, client.InNamespace(bootstrapTokenNamespace), client.MatchingLabels{joinTokenLabel: cfg.Namespace}); err != nil {
+		return 0, err
+	}
+	if cfg.Spec.Join == nil {
+		if err := r.deleteJoin(ctx, cfg.Namespace); err != nil {
+			return 0, err
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: joinTokenSecretName(cfg), Namespace: cfg.Namespace}}
+		if err := utils.DeleteObject(r.Client, secret); err != nil {
+			return 0, err
+		}
+		cfg.Status.Join = nil
+		return 0, nil
+	}
+	logger.Info("Start to sync the join of new DPUs")
+	if cfg.Status.Join == nil {
+		cfg.Status.Join = &dpuv1alpha1.JoinStatus{}
+	}
+	if err := r.syncJoinClusterRoleBinding(ctx, cfg); err != nil {
+		return 0, err
+	}
+
+	// The expired tokens are deleted, and a new token is created once half
+	// of the lifetime of the current one elapsed
+	ttl := joinTokenTTL(cfg)
+	var current *corev1.Secret
+	tokenIDs := map[string]bool{}
+	for i := range tokens.Items {
+		token := &tokens.Items[i]
+		expiration := tokenExpiration(token)
+		if time.Now().After(expiration) {
+			if err := utils.DeleteObject(r.Client, token); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		tokenIDs[string(token.Data["token-id"])] = true
+		if current == nil || expiration.After(tokenExpiration(current)) {
+			current = token
+		}
+	}
+	if current == nil || time.Until(tokenExpiration(current)) < ttl/2 {
+		token, err := r.createJoinToken(ctx, cfg, ttl)
+		if err != nil {
+			return 0, err
+		}
+		current = token
+		tokenIDs[string(token.Data["token-id"])] = true
+	}
+	if err := r.syncJoinKubeconfig(ctx, cfg, current); err != nil {
+		return 0, err
+	}
+	expiration := metav1.NewTime(tokenExpiration(current))
+	cfg.Status.Join.TokenSecret = joinTokenSecretName(cfg)
+	cfg.Status.Join.TokenExpiration = &expiration
+
+	joining, err := r.getJoiningNodes(ctx, cfg, ttl)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.addJoiningNodes(ctx, cfg, joining); err != nil {
+		return 0, err
+	}
+	if err := r.approveJoinCSRs(ctx, cfg, tokenIDs, joining); err != nil {
+		return 0, err
+	}
+	if len(joining) > 0 {
+		return joinPollPeriod, nil
+	}
+	return time.Until(expiration.Add(-ttl / 2)), nil
+}
+
+// deleteJoin deletes the bootstrap tokens of namespace and, once no other
+// DpuClusterConfig has a join, the join ClusterRoleBinding. The token Secret
+// of the namespace is owned by the DpuClusterConfig.
+func (r *DpuClusterConfigReconciler) deleteJoin(ctx context.Context, namespace string) error {
+	tokens := &corev1.SecretList{}
+	if err := r.List(ctx, tokens, client.InNamespace(bootstrapTokenNamespace), client.MatchingLabels{joinTokenLabel: namespace}); err != nil {
+		return err
+	}
+	for i := range tokens.Items {
+		if err := utils.DeleteObject(r.Client, &tokens.Items[i]); err != nil {
+			return err
+		}
+	}
+	cfgList := &dpuv1alpha1.DpuClusterConfigList{}
+	if err := r.List(ctx, cfgList); err != nil {
+		return err
+	}
+	for _, cfg := range cfgList.Items {
+		if cfg.Namespace != namespace && cfg.Spec.Join != nil {
+			return nil
+		}
+	}
+	binding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: joinClusterRoleBinding}}
+	return utils.DeleteObject(r.Client, binding)
+}
+
+// syncJoinClusterRoleBinding lets the bootstrap tokens request the client
+// certificates of the kubelets
+func (r *DpuClusterConfigReconciler) syncJoinClusterRoleBinding(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	binding := &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+		ObjectMeta: metav1.ObjectMeta{Name: joinClusterRoleBinding},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: nodeBootstrapperRole},
+		Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: joinGroup}},
+	}
+	setCommonMetadata(cfg, binding)
+	_, err := utils.GetOrCreateObject(r.Client, binding, logger)
+	return err
+}
+
+// createJoinToken creates a bootstrap token of the namespace of cfg valid for
+// ttl
+func (r *DpuClusterConfigReconciler) createJoinToken(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, ttl time.Duration) (*corev1.Secret, error) {
+	id, err := randomToken(6)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	token := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapTokenPrefix + id,
+			Namespace: bootstrapTokenNamespace,
+			Labels:    map[string]string{joinTokenLabel: cfg.Namespace},
+		},
+		Type: corev1.SecretTypeBootstrapToken,
+		Data: map[string][]byte{
+			"description":                    []byte(fmt.Sprintf("Join of the DPUs of DpuClusterConfig %s/%s", cfg.Namespace, cfg.Name)),
+			"token-id":                       []byte(id),
+			"token-secret":                   []byte(secret),
+			"expiration":                     []byte(time.Now().Add(ttl).UTC().Format(time.RFC3339)),
+			"usage-bootstrap-authentication": []byte("true"),
+			"auth-extra-groups":              []byte(joinGroup),
+		},
+	}
+	logger.Info("Create a bootstrap token for the join of new DPUs", "token", token.Name)
+	if err := r.Create(ctx, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// joinAPIServerURL returns the URL of the API server the DPUs join
+func (r *DpuClusterConfigReconciler) joinAPIServerURL(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (string, error) {
+	if cfg.Spec.Join.APIServerURL != "" {
+		return cfg.Spec.Join.APIServerURL, nil
+	}
+	infra := &unstructured.Unstructured{}
+	infra.SetGroupVersionKind(infrastructureGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: "cluster"}, infra); err != nil {
+		return "", fmt.Errorf("failed to get the API server URL of the cluster infrastructure, set apiServerURL: %w", err)
+	}
+	url, _, _ := unstructured.NestedString(infra.Object, "status", "apiServerInternalURI")
+	if url == "" {
+		return "", fmt.Errorf("the cluster infrastructure has no internal API server URL, set apiServerURL")
+	}
+	return url, nil
+}
+
+// syncJoinKubeconfig publishes the bootstrap kubeconfig of token in the
+// namespace of cfg, for the tools imaging the DPUs
+func (r *DpuClusterConfigReconciler) syncJoinKubeconfig(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, token *corev1.Secret) error {
+	server, err := r.joinAPIServerURL(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	ca := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: "kube-root-ca.crt"}, ca); err != nil {
+		return err
+	}
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters["cluster"] = &clientcmdapi.Cluster{Server: server, CertificateAuthorityData: []byte(ca.Data["ca.crt"])}
+	kubeconfig.AuthInfos["kubelet-bootstrap"] = &clientcmdapi.AuthInfo{Token: string(token.Data["token-id"]) + "." + string(token.Data["token-secret"])}
+	kubeconfig.Contexts["kubelet-bootstrap"] = &clientcmdapi.Context{Cluster: "cluster", AuthInfo: "kubelet-bootstrap"}
+	kubeconfig.CurrentContext = "kubelet-bootstrap"
+	data, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: joinTokenSecretName(cfg), Namespace: cfg.Namespace}}
+	_, err = ctrl.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		setCommonMetadata(cfg, secret)
+		secret.Data = map[string][]byte{joinKubeconfigKey: data}
+		return ctrl.SetControllerReference(cfg, secret, r.Scheme)
+	})
+	return err
+}
+
+// getJoiningNodes returns the joining nodes tracked on the token Secret of
+// the namespace of cfg, without the ones tracked for longer than ttl which
+// are not expected to request a serving certificate anymore
+func (r *DpuClusterConfigReconciler) getJoiningNodes(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, ttl time.Duration) (map[string]metav1.Time, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: joinTokenSecretName(cfg)}, secret); err != nil {
+		return nil, err
+	}
+	tracked := map[string]metav1.Time{}
+	if value, ok := secret.Annotations[joiningNodesAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &tracked); err != nil {
+			logger.Info("Ignore the invalid joining nodes of the token Secret", "secret", secret.Name, "error", err.Error())
+		}
+	}
+	joining := map[string]metav1.Time{}
+	for name, since := range tracked {
+		if time.Since(since.Time) < ttl {
+			joining[name] = since
+		}
+	}
+	// The nodes only listed in the status were tracked before the
+	// annotation existed
+	for _, name := range cfg.Status.Join.JoiningNodes {
+		if _, ok := joining[name]; !ok {
+			joining[name] = metav1.Now()
+		}
+	}
+	if len(joining) != len(tracked) {
+		return joining, r.saveJoiningNodes(ctx, cfg, joining)
+	}
+	return joining, nil
+}
+
+// saveJoiningNodes tracks the joining nodes on the token Secret of the
+// namespace of cfg
+func (r *DpuClusterConfigReconciler) saveJoiningNodes(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, joining map[string]metav1.Time) error {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: joinTokenSecretName(cfg)}, secret); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(secret.DeepCopy())
+	if len(joining) == 0 {
+		delete(secret.Annotations, joiningNodesAnnotation)
+	} else {
+		value, err := json.Marshal(joining)
+		if err != nil {
+			return err
+		}
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[joiningNodesAnnotation] = string(value)
+	}
+	return r.Patch(ctx, secret, patch)
+}
+
+// parseNodeCSR returns the certificate request of the CSR after checking it is
+// the request of a node for the given usages
+func parseNodeCSR(csr *certificatesv1.CertificateSigningRequest, allowed ...certificatesv1.KeyUsage) (*x509.CertificateRequest, string, error) {
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, "", fmt.Errorf("no PEM certificate request")
+	}
+	req, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := req.CheckSignature(); err != nil {
+		return nil, "", err
+	}
+	if !strings.HasPrefix(req.Subject.CommonName, nodeUserPrefix) {
+		return nil, "", fmt.Errorf("common name %s is not a node", req.Subject.CommonName)
+	}
+	if len(req.Subject.Organization) != 1 || req.Subject.Organization[0] != nodesGroup {
+		return nil, "", fmt.Errorf("organization %v is not %s", req.Subject.Organization, nodesGroup)
+	}
+	for _, usage := range csr.Spec.Usages {
+		found := false
+		for _, a := range allowed {
+			found = found || usage == a
+		}
+		if !found {
+			return nil, "", fmt.Errorf("usage %s is not allowed", usage)
+		}
+	}
+	return req, strings.TrimPrefix(req.Subject.CommonName, nodeUserPrefix), nil
+}
+
+// checkServingSANs returns an error unless the subject alternative names of
+// the serving certificate request are addresses of the node
+func checkServingSANs(req *x509.CertificateRequest, node *corev1.Node) error {
+	addresses := map[string]bool{}
+	for _, a := range node.Status.Addresses {
+		addresses[a.Address] = true
+	}
+	for _, name := range req.DNSNames {
+		if !addresses[name] {
+			return fmt.Errorf("DNS name %s is not an address of the node", name)
+		}
+	}
+	for _, ip := range req.IPAddresses {
+		found := false
+		for a := range addresses {
+			found = found || ip.Equal(net.ParseIP(a))
+		}
+		if !found {
+			return fmt.Errorf("IP %s is not an address of the node", ip)
+		}
+	}
+	if len(req.EmailAddresses) > 0 || len(req.URIs) > 0 {
+		return fmt.Errorf("only DNS names and IPs are allowed")
+	}
+	return nil
+}
+
+// approveJoinCSRs approves the kubelet client certificates requested with the
+// bootstrap tokens of tokenIDs, tracking their nodes in joining, and the first
+// kubelet serving certificates of the tracked nodes added to the join pool,
+// which the machine approver of OpenShift leaves alone without a Machine. The
+// serving certificates of the other nodes are left to the approver of the
+// cluster.
+func (r *DpuClusterConfigReconciler) approveJoinCSRs(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, tokenIDs map[string]bool, joining map[string]metav1.Time) error {
+	pool, err := joinPool(cfg)
+	if err != nil {
+		return err
+	}
+	csrs := &certificatesv1.CertificateSigningRequestList{}
+	if err := r.List(ctx, csrs); err != nil {
+		return err
+	}
+	for i := range csrs.Items {
+		csr := &csrs.Items[i]
+		if !isCSRPending(csr) {
+			continue
+		}
+		var nodeName string
+		var err error
+		switch csr.Spec.SignerName {
+		case certificatesv1.KubeAPIServerClientKubeletSignerName:
+			if !strings.HasPrefix(csr.Spec.Username, bootstrapUserPrefix) || !tokenIDs[strings.TrimPrefix(csr.Spec.Username, bootstrapUserPrefix)] {
+				continue
+			}
+			var req *x509.CertificateRequest
+			if req, nodeName, err = parseNodeCSR(csr, certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageClientAuth); err == nil {
+				if len(req.DNSNames) > 0 || len(req.IPAddresses) > 0 || len(req.EmailAddresses) > 0 || len(req.URIs) > 0 {
+					err = fmt.Errorf("subject alternative names are not allowed")
+				}
+			}
+			// A token must not get the identity of a registered node
+			if _, tracked := joining[nodeName]; err == nil && !tracked {
+				node := &corev1.Node{}
+				if getErr := r.Get(ctx, types.NamespacedName{Name: nodeName}, node); getErr == nil {
+					err = fmt.Errorf("node %s is already registered", nodeName)
+				} else if !errors.IsNotFound(getErr) {
+					return getErr
+				}
+			}
+		case certificatesv1.KubeletServingSignerName:
+			if !strings.HasPrefix(csr.Spec.Username, nodeUserPrefix) {
+				continue
+			}
+			var req *x509.CertificateRequest
+			if req, nodeName, err = parseNodeCSR(csr, certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageServerAuth); err != nil {
+				break
+			}
+			if _, tracked := joining[nodeName]; !tracked || csr.Spec.Username != nodeUserPrefix+nodeName {
+				continue
+			}
+			node := &corev1.Node{}
+			if err := r.Get(ctx, types.NamespacedName{Name: nodeName}, node); errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			// The node is untracked once its serving certificate is
+			// approved, so it has to be in its pool by then
+			if !isSubset(pool.cfg.Spec.NodeSelector.MatchLabels, node.Labels) {
+				continue
+			}
+			err = checkServingSANs(req, node)
+		default:
+			continue
+		}
+		if err != nil {
+			logger.Info("Skip the certificate request of a joining node", "csr", csr.Name, "reason", err.Error())
+			continue
+		}
+
+		// The node is tracked before its client certificate is approved
+		isClient := csr.Spec.SignerName == certificatesv1.KubeAPIServerClientKubeletSignerName
+		if _, tracked := joining[nodeName]; isClient && !tracked {
+			joining[nodeName] = metav1.Now()
+			if err := r.saveJoiningNodes(ctx, cfg, joining); err != nil {
+				return err
+			}
+			cfg.Status.Join.JoiningNodes = append(cfg.Status.Join.JoiningNodes, nodeName)
+			sort.Strings(cfg.Status.Join.JoiningNodes)
+		}
+		logger.Info("Approve the certificate request of a joining node", "csr", csr.Name, "node", nodeName, "signer", csr.Spec.SignerName)
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+			Type:    certificatesv1.CertificateApproved,
+			Status:  corev1.ConditionTrue,
+			Reason:  "DpuNetworkOperatorApprove",
+			Message: fmt.Sprintf("node %s joins DpuClusterConfig %s/%s", nodeName, cfg.Namespace, cfg.Name),
+		})
+		if err := r.SubResource("approval").Update(ctx, csr); err != nil {
+			return err
+		}
+		// The node joined once its serving certificate is approved
+		if !isClient {
+			delete(joining, nodeName)
+			if err := r.saveJoiningNodes(ctx, cfg, joining); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addJoiningNodes labels the registered joining nodes so that they are added
+// to the join pool, and creates their DpuNodeConfig. The nodes waiting to
+// register are listed in the status.
+func (r *DpuClusterConfigReconciler) addJoiningNodes(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, joining map[string]metav1.Time) error {
+	pool, err := joinPool(cfg)
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for name := range joining {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pending := []string{}
+	for _, name := range names {
+		node := &corev1.Node{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, node); errors.IsNotFound(err) {
+			pending = append(pending, name)
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !isSubset(pool.cfg.Spec.NodeSelector.MatchLabels, node.Labels) {
+			patch := client.MergeFrom(node.DeepCopy())
+			node.Labels = mergeStringMap(node.Labels, pool.cfg.Spec.NodeSelector.MatchLabels, true)
+			logger.Info("Add the joining node to its pool", "node", name, "pool", pool.cfg.Spec.PoolName)
+			if err := r.Patch(ctx, node, patch); err != nil {
+				return err
+			}
+		}
+
+		nodeConfig := &dpuv1alpha1.DpuNodeConfig{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cfg.Namespace}}
+		if err := r.Create(ctx, nodeConfig); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	cfg.Status.Join.JoiningNodes = pending
+	if len(pending) == 0 {
+		cfg.Status.Join.JoiningNodes = nil
+	}
+	return nil
+}
+
+// joinRequests enqueues the DpuClusterConfigs with a join on the changes of
+// the CertificateSigningRequests
+func (r *DpuClusterConfigReconciler) joinRequests(obj client.Object) []reconcile.Request {
+	cfgList := &dpuv1alpha1.DpuClusterConfigList{}
+	if err := r.List(context.TODO(), cfgList); err != nil {
+		logger.Error(err, "Fail to list the DpuClusterConfigs")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, cfg := range cfgList.Items {
+		if cfg.Spec.Join != nil {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+const testJoinTokenID = "abcdef"
+
+func newJoinTestConfig() *dpuv1alpha1.DpuClusterConfig {
+	cfg := newPoolsTestConfig()
+	cfg.Spec.Join = &dpuv1alpha1.JoinSpec{}
+	cfg.Status.Join = &dpuv1alpha1.JoinStatus{}
+	return cfg
+}
+
+func newJoinTokenSecret(cfg *dpuv1alpha1.DpuClusterConfig) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: joinTokenSecretName(cfg), Namespace: cfg.Namespace}}
+}
+
+func newClientCSR(t *testing.T, name, node string) *certificatesv1.CertificateSigningRequest {
+	return newTestCSR(t, name, bootstrapUserPrefix+testJoinTokenID, certificatesv1.KubeAPIServerClientKubeletSignerName,
+		pkix.Name{CommonName: nodeUserPrefix + node, Organization: []string{nodesGroup}}, nil,
+		certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageClientAuth)
+}
+
+func newServingCSR(t *testing.T, name, node string, ip string) *certificatesv1.CertificateSigningRequest {
+	return newTestCSR(t, name, nodeUserPrefix+node, certificatesv1.KubeletServingSignerName,
+		pkix.Name{CommonName: nodeUserPrefix + node, Organization: []string{nodesGroup}}, []net.IP{net.ParseIP(ip)},
+		certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageServerAuth)
+}
+
+func newJoinTestNode(name, ip string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status:     corev1.NodeStatus{Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: ip}}},
+	}
+}
+
+// approveJoin runs the CSR approval and the node addition of a reconcile of
+// the join of cfg
+func approveJoin(g *WithT, r *DpuClusterConfigReconciler, cfg *dpuv1alpha1.DpuClusterConfig) map[string]metav1.Time {
+	ctx := context.Background()
+	joining, err := r.getJoiningNodes(ctx, cfg, defaultJoinTokenTTL)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(r.addJoiningNodes(ctx, cfg, joining)).To(Succeed())
+	g.Expect(r.approveJoinCSRs(ctx, cfg, map[string]bool{testJoinTokenID: true}, joining)).To(Succeed())
+	return joining
+}
+
+func getCSR(g *WithT, c client.Client, name string) *certificatesv1.CertificateSigningRequest {
+	csr := &certificatesv1.CertificateSigningRequest{}
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Name: name}, csr)).To(Succeed())
+	return csr
+}
+
+func TestApproveJoinCSRsClient(t *testing.T) {
+	cfg := newJoinTestConfig()
+	tests := []struct {
+		name     string
+		csr      *certificatesv1.CertificateSigningRequest
+		objs     []client.Object
+		approved bool
+	}{
+		{
+			name:     "new node",
+			csr:      newClientCSR(t, "csr", "dpu-0"),
+			approved: true,
+		},
+		{
+			name: "registered node",
+			csr:  newClientCSR(t, "csr", "dpu-0"),
+			objs: []client.Object{newJoinTestNode("dpu-0", "192.168.0.10", nil)},
+		},
+		{
+			name: "unknown token",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newClientCSR(t, "csr", "dpu-0")
+				csr.Spec.Username = bootstrapUserPrefix + "zzzzzz"
+				return csr
+			}(),
+		},
+		{
+			name: "server usage",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newClientCSR(t, "csr", "dpu-0")
+				csr.Spec.Usages = append(csr.Spec.Usages, certificatesv1.UsageServerAuth)
+				return csr
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cfg := cfg.DeepCopy()
+			r := &DpuClusterConfigReconciler{Client: newFakeClient(append(tt.objs, tt.csr, newJoinTokenSecret(cfg))...)}
+
+			joining := approveJoin(g, r, cfg)
+
+			g.Expect(isCSRApproved(getCSR(g, r.Client, "csr"))).To(Equal(tt.approved))
+			if tt.approved {
+				g.Expect(joining).To(HaveKey("dpu-0"))
+				g.Expect(cfg.Status.Join.JoiningNodes).To(Equal([]string{"dpu-0"}))
+			} else {
+				g.Expect(joining).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestApproveJoinCSRsServing(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	cfg := newJoinTestConfig()
+	poolLabels := cfg.Spec.NodeSelector.MatchLabels
+	r := &DpuClusterConfigReconciler{Client: newFakeClient(
+		newJoinTokenSecret(cfg),
+		newClientCSR(t, "client", "dpu-0"),
+		// A node of the pool which did not join with the token
+		newJoinTestNode("dpu-1", "192.168.0.11", poolLabels),
+		newServingCSR(t, "serving-pool", "dpu-1", "192.168.0.11"),
+	)}
+
+	approveJoin(g, r, cfg)
+	g.Expect(isCSRApproved(getCSR(g, r.Client, "client"))).To(BeTrue())
+	g.Expect(isCSRApproved(getCSR(g, r.Client, "serving-pool"))).To(BeFalse())
+
+	// The status of the approval of the client certificate is lost, the
+	// node is still tracked on the token Secret
+	cfg.Status.Join = &dpuv1alpha1.JoinStatus{}
+	g.Expect(r.Create(ctx, newJoinTestNode("dpu-0", "192.168.0.10", nil))).To(Succeed())
+	g.Expect(r.Create(ctx, newServingCSR(t, "serving-other-ip", "dpu-0", "192.168.0.99"))).To(Succeed())
+	g.Expect(r.Create(ctx, newServingCSR(t, "serving", "dpu-0", "192.168.0.10"))).To(Succeed())
+	joining := approveJoin(g, r, cfg)
+
+	g.Expect(isCSRApproved(getCSR(g, r.Client, "serving-other-ip"))).To(BeFalse())
+	g.Expect(isCSRApproved(getCSR(g, r.Client, "serving"))).To(BeTrue())
+	g.Expect(isCSRApproved(getCSR(g, r.Client, "serving-pool"))).To(BeFalse())
+	g.Expect(joining).To(BeEmpty())
+	node := &corev1.Node{}
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: "dpu-0"}, node)).To(Succeed())
+	g.Expect(node.Labels).To(Equal(poolLabels))
+	secret := &corev1.Secret{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(newJoinTokenSecret(cfg)), secret)).To(Succeed())
+	g.Expect(secret.Annotations).NotTo(HaveKey(joiningNodesAnnotation))
+
+	// The later serving certificates are left to the approver of the cluster
+	g.Expect(r.Create(ctx, newServingCSR(t, "serving-renewal", "dpu-0", "192.168.0.10"))).To(Succeed())
+	approveJoin(g, r, cfg)
+	g.Expect(isCSRApproved(getCSR(g, r.Client, "serving-renewal"))).To(BeFalse())
+}
+
+func TestGetJoiningNodesExpiry(t *testing.T) {
+	g := NewWithT(t)
+	cfg := newJoinTestConfig()
+	secret := newJoinTokenSecret(cfg)
+	secret.Annotations = map[string]string{
+		joiningNodesAnnotation: `{"dpu-0":"2000-01-01T00:00:00Z","dpu-1":"` + metav1.Now().UTC().Format("2006-01-02T15:04:05Z") + `"}`,
+	}
+	r := &DpuClusterConfigReconciler{Client: newFakeClient(secret)}
+
+	joining, err := r.getJoiningNodes(context.Background(), cfg, defaultJoinTokenTTL)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(joining).To(HaveLen(1))
+	g.Expect(joining).To(HaveKey("dpu-1"))
+}
+
+func TestDeleteJoin(t *testing.T) {
+	token := func(namespace string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapTokenPrefix + namespace,
+			Namespace: bootstrapTokenNamespace,
+			Labels:    map[string]string{joinTokenLabel: namespace},
+		}}
+	}
+	binding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: joinClusterRoleBinding}}
+	other := newJoinTestConfig()
+	other.Namespace = "tenant-b"
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		keepBinding bool
+	}{
+		{name: "last join"},
+		{name: "other join", objs: []client.Object{other}, keepBinding: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			r := &DpuClusterConfigReconciler{Client: newFakeClient(append(tt.objs, binding.DeepCopy(), token("tenant-a"), token("tenant-b"))...)}
+
+			g.Expect(r.deleteJoin(ctx, "tenant-a")).To(Succeed())
+
+			g.Expect(errors.IsNotFound(r.Get(ctx, client.ObjectKeyFromObject(token("tenant-a")), &corev1.Secret{}))).To(BeTrue())
+			g.Expect(r.Get(ctx, client.ObjectKeyFromObject(token("tenant-b")), &corev1.Secret{})).To(Succeed())
+			err := r.Get(ctx, client.ObjectKeyFromObject(binding), &rbacv1.ClusterRoleBinding{})
+			if tt.keepBinding {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+// defaultMasterDiscoveryTTL is how long the discovered ovnkube-master pods
+// are cached, unless set in the spec
+const defaultMasterDiscoveryTTL = time.Minute
+
+// masterDiscovery caches the ovnkube-master pods discovered in a tenant
+// cluster, so that the reconciles do not list them in the tenant cluster
+type masterDiscovery struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	ips       []string
+	expected  int
+	err       error
+	fetchedAt time.Time
+}
+
+// masterDiscoveryTTL returns the masterDiscoveryTTL of cfg
+func masterDiscoveryTTL(cfg *dpuv1alpha1.DpuClusterConfig) time.Duration {
+	if cfg.Spec.MasterDiscoveryTTL == nil || cfg.Spec.MasterDiscoveryTTL.Duration <= 0 {
+		return defaultMasterDiscoveryTTL
+	}
+	return cfg.Spec.MasterDiscoveryTTL.Duration
+}
+
+// getCachedTenantClusterMasterIPs returns the result of
+// getTenantClusterMasterIPs cached by the tenant syncer of cfg. The pods are
+// only looked up in the tenant cluster when nothing is cached yet or the
+// cache expired, e.g. when its refresh keeps failing.
+func (r *DpuClusterConfigReconciler) getCachedTenantClusterMasterIPs(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) ([]string, int, error) {
+	ts, ok := r.syncers[cfg.Namespace]
+	if !ok || ts.masters == nil {
+		return r.getTenantClusterMasterIPs(ctx, cfg)
+	}
+	d := ts.masters
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.ttl = masterDiscoveryTTL(cfg)
+	if !d.fetchedAt.IsZero() && time.Since(d.fetchedAt) < d.ttl {
+		return d.ips, d.expected, d.err
+	}
+	d.ips, d.expected, d.err = r.getTenantClusterMasterIPs(ctx, cfg)
+	d.fetchedAt = time.Now()
+	return d.ips, d.expected, d.err
+}
+
+// runMasterDiscovery refreshes the cached ovnkube-master pods of the tenant
+// cluster of cfg before they expire, until stopCh is closed. The pods are
+// looked up every tenantDiscoveryRetryPeriod at most while they are not all
+// found, and the DpuClusterConfig is reconciled whenever they change.
+func (r *DpuClusterConfigReconciler) runMasterDiscovery(cfg *dpuv1alpha1.DpuClusterConfig, d *masterDiscovery, stopCh <-chan struct{}) error {
+	for {
+		d.mu.Lock()
+		period := d.ttl
+		if d.err != nil || len(d.ips) < d.expected || len(d.ips) == 0 {
+			if period > tenantDiscoveryRetryPeriod {
+				period = tenantDiscoveryRetryPeriod
+			}
+		}
+		// The refresh happens shortly before the expiry, so that the
+		// reconciles find a valid cache
+		wait := period * 9 / 10
+		if !d.fetchedAt.IsZero() {
+			wait = time.Until(d.fetchedAt.Add(wait))
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-stopCh:
+			return nil
+		case <-time.After(wait):
+		}
+
+		ips, expected, err := r.getTenantClusterMasterIPs(context.Background(), cfg)
+		d.mu.Lock()
+		changed := fmt.Sprint(ips, expected, err) != fmt.Sprint(d.ips, d.expected, d.err)
+		d.ips, d.expected, d.err = ips, expected, err
+		d.fetchedAt = time.Now()
+		d.mu.Unlock()
+		if changed {
+			logger.Info("The ovnkube-master pods of the tenant cluster changed", "namespace", cfg.Namespace, "ips", ips, "expected", expected)
+			r.notifyTenantChange(cfg)
+		}
+	}
+}
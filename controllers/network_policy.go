@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+
+// syncNetworkPolicies restricts the ingress traffic in the operator namespace
+// to the metrics endpoint, scraped from the monitoring namespace
+func (r *DpuClusterConfigReconciler) syncNetworkPolicies(ctx context.Context) error {
+	if utils.Namespace == "" {
+		return nil
+	}
+	data := render.MakeRenderData()
+	data.Data["Namespace"] = utils.Namespace
+	objs, err := renderDir(ctx, utils.NetworkPolicyPath, &data)
+	if err != nil {
+		logger.Error(err, "Fail to render the network policies")
+		return err
+	}
+	// The policies belong to the operator namespace rather than to a
+	// DpuClusterConfig, so they are not owned
+	for _, obj := range objs {
+		if err := applyObject(ctx, r.Client, obj); err != nil {
+			return fmt.Errorf("failed to apply object %v with err: %v", obj, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+const (
+	// ovnNodeUserPrefix and ovnNodesGroup are the subject of the per-node
+	// certificates of ovnkube-node, as issued by ovnkube-identity
+	ovnNodeUserPrefix = "system:ovn-node:"
+	ovnNodesGroup     = "system:ovn-nodes"
+	// nodeIdentityPollPeriod is how often the CSR of a DPU node is looked up
+	// while its ovnkube-node pod waits for its certificate
+	nodeIdentityPollPeriod = 10 * time.Second
+	// nodeIdentityResyncPeriod is how often the renewals of the per-node
+	// certificates are looked up
+	nodeIdentityResyncPeriod = 10 * time.Minute
+)
+
+// setNodeIdentityRenderData sets the render data of the per-node certificates
+// of ovnkube-node
+func setNodeIdentityRenderData(cfg *dpuv1alpha1.DpuClusterConfig, data *render.RenderData) {
+	data.Data["NodeIdentity"] = cfg.Spec.NodeIdentity != nil
+	data.Data["NodeIdentityCertDuration"] = ""
+	if cfg.Spec.NodeIdentity != nil && cfg.Spec.NodeIdentity.CertDuration != nil {
+		data.Data["NodeIdentityCertDuration"] = cfg.Spec.NodeIdentity.CertDuration.Duration.String()
+	}
+}
+
+// tenantKubeconfigUsername returns the user the tenant cluster authenticates
+// restConfig as, "" when it cannot be told, e.g. for an exec plugin
+func tenantKubeconfigUsername(restConfig *restclient.Config) string {
+	if restConfig == nil {
+		return ""
+	}
+	if restConfig.BearerToken != "" {
+		if namespace, name, ok := serviceAccountOfToken(restConfig.BearerToken); ok {
+			return serviceAccountUsernamePrefix + namespace + ":" + name
+		}
+		return ""
+	}
+	certData := restConfig.CertData
+	if len(certData) == 0 && restConfig.CertFile != "" {
+		var err error
+		if certData, err = os.ReadFile(restConfig.CertFile); err != nil {
+			return ""
+		}
+	}
+	block, _ := pem.Decode(certData)
+	if block == nil {
+		return ""
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ""
+	}
+	return cert.Subject.CommonName
+}
+
+// checkNodeIdentityCSR returns an error unless the CSR is a request of the
+// ovnkube-node of tenantNode for its client certificate, made with the
+// tenant kubeconfig of username
+func checkNodeIdentityCSR(csr *certificatesv1.CertificateSigningRequest, username, tenantNode string) error {
+	if csr.Spec.Username != username {
+		return fmt.Errorf("requested by %s rather than %s", csr.Spec.Username, username)
+	}
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return fmt.Errorf("no PEM certificate request")
+	}
+	req, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return err
+	}
+	if err := req.CheckSignature(); err != nil {
+		return err
+	}
+	if req.Subject.CommonName != ovnNodeUserPrefix+tenantNode {
+		return fmt.Errorf("common name %s is not %s", req.Subject.CommonName, ovnNodeUserPrefix+tenantNode)
+	}
+	if len(req.Subject.Organization) != 1 || req.Subject.Organization[0] != ovnNodesGroup {
+		return fmt.Errorf("organization %v is not %s", req.Subject.Organization, ovnNodesGroup)
+	}
+	if len(req.DNSNames) > 0 || len(req.IPAddresses) > 0 || len(req.EmailAddresses) > 0 || len(req.URIs) > 0 {
+		return fmt.Errorf("subject alternative names are not allowed")
+	}
+	for _, usage := range csr.Spec.Usages {
+		switch usage {
+		case certificatesv1.UsageClientAuth, certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment:
+		default:
+			return fmt.Errorf("usage %s is not allowed", usage)
+		}
+	}
+	return nil
+}
+
+// isCSRPending returns true until the CSR is approved, denied or failed
+func isCSRPending(csr *certificatesv1.CertificateSigningRequest) bool {
+	for _, c := range csr.Status.Conditions {
+		switch c.Type {
+		case certificatesv1.CertificateApproved, certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return false
+		}
+	}
+	return true
+}
+
+// syncNodeIdentity approves the CertificateSigningRequests of the per-node
+// certificate of the ovnkube-node of the DPU node. ovnkube-node requests it
+// with the tenant kubeconfig rather than as the tenant node, so the approver
+// of ovnkube-identity leaves it alone. It returns true while the ovnkube-node
+// pod is not Ready, e.g. waiting for its certificate.
+func (r *DpuNodeLifecycleController) syncNodeIdentity(ctx context.Context, log logr.Logger, cfg *dpuv1alpha1.DpuClusterConfig, node *corev1.Node, tenantClient client.Client, tenantNode string) (bool, error) {
+	if cfg == nil || cfg.Spec.NodeIdentity == nil {
+		return false, nil
+	}
+	username := tenantKubeconfigUsername(r.TenantConfigs.Get(cfg.Namespace))
+	if username == "" {
+		log.Info("The user of the tenant kubeconfig is unknown, the node identity CSRs are not approved")
+		return false, nil
+	}
+
+	csrs := &certificatesv1.CertificateSigningRequestList{}
+	if err := tenantClient.List(ctx, csrs, client.MatchingFields{"spec.signerName": certificatesv1.KubeAPIServerClientSignerName}); err != nil {
+		return false, err
+	}
+	for i := range csrs.Items {
+		csr := &csrs.Items[i]
+		if !isCSRPending(csr) {
+			continue
+		}
+		if err := checkNodeIdentityCSR(csr, username, tenantNode); err != nil {
+			log.V(1).Info("Skip the CSR", "csr", csr.Name, "reason", err.Error())
+			continue
+		}
+		log.Info("Approve the ovnkube-node certificate request", "csr", csr.Name, "tenantNode", tenantNode)
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+			Type:    certificatesv1.CertificateApproved,
+			Status:  corev1.ConditionTrue,
+			Reason:  "DpuNetworkOperatorApprove",
+			Message: fmt.Sprintf("the ovnkube-node of DPU node %s requested the certificate of tenant node %s", node.Name, tenantNode),
+		})
+		if err := tenantClient.SubResource("approval").Update(ctx, csr); err != nil {
+			return false, err
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(cfg.Namespace), client.MatchingLabels{"app": utils.OvnkubeNodeDsName}); err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != node.Name || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status != corev1.ConditionTrue {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
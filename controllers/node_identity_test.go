@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+const testTenantUsername = "system:dpu-tenant"
+
+// newTenantClientCertConfig returns a rest config authenticating with a
+// client certificate of username
+func newTenantClientCertConfig(t *testing.T, username string) *restclient.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: username},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &restclient.Config{TLSClientConfig: restclient.TLSClientConfig{CertData: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}}
+}
+
+func newNodeIdentityCSR(t *testing.T, tenantNode string) *certificatesv1.CertificateSigningRequest {
+	return newTestCSR(t, "csr", testTenantUsername, certificatesv1.KubeAPIServerClientSignerName,
+		pkix.Name{CommonName: ovnNodeUserPrefix + tenantNode, Organization: []string{ovnNodesGroup}}, nil,
+		certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageClientAuth)
+}
+
+func TestSyncNodeIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		csr      func() *certificatesv1.CertificateSigningRequest
+		approved bool
+	}{
+		{
+			name:     "ovnkube-node certificate",
+			csr:      func() *certificatesv1.CertificateSigningRequest { return newNodeIdentityCSR(t, "worker-0") },
+			approved: true,
+		},
+		{
+			name: "wrong signer",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newNodeIdentityCSR(t, "worker-0")
+				csr.Spec.SignerName = certificatesv1.KubeAPIServerClientKubeletSignerName
+				return csr
+			},
+		},
+		{
+			name: "wrong requester",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newNodeIdentityCSR(t, "worker-0")
+				csr.Spec.Username = "system:serviceaccount:default:other"
+				return csr
+			},
+		},
+		{
+			name: "wrong common name",
+			csr:  func() *certificatesv1.CertificateSigningRequest { return newNodeIdentityCSR(t, "worker-1") },
+		},
+		{
+			name: "wrong organization",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				return newTestCSR(t, "csr", testTenantUsername, certificatesv1.KubeAPIServerClientSignerName,
+					pkix.Name{CommonName: ovnNodeUserPrefix + "worker-0", Organization: []string{nodesGroup}}, nil,
+					certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageClientAuth)
+			},
+		},
+		{
+			name: "extra usage",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newNodeIdentityCSR(t, "worker-0")
+				csr.Spec.Usages = append(csr.Spec.Usages, certificatesv1.UsageServerAuth)
+				return csr
+			},
+		},
+		{
+			name: "subject alternative names",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				return newTestCSR(t, "csr", testTenantUsername, certificatesv1.KubeAPIServerClientSignerName,
+					pkix.Name{CommonName: ovnNodeUserPrefix + "worker-0", Organization: []string{ovnNodesGroup}}, []net.IP{net.ParseIP("10.0.0.1")},
+					certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageClientAuth)
+			},
+		},
+		{
+			name: "already approved",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newNodeIdentityCSR(t, "worker-0")
+				csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue}}
+				return csr
+			},
+			approved: true,
+		},
+		{
+			name: "already denied",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newNodeIdentityCSR(t, "worker-0")
+				csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateDenied, Status: corev1.ConditionTrue}}
+				return csr
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			cfg := &dpuv1alpha1.DpuClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "tenant-a"},
+				Spec:       dpuv1alpha1.DpuClusterConfigSpec{NodeIdentity: &dpuv1alpha1.NodeIdentitySpec{}},
+			}
+			tenantConfigs := utils.NewTenantRestConfigStore()
+			tenantConfigs.Set(cfg.Namespace, newTenantClientCertConfig(t, testTenantUsername))
+			tenantClient := newFakeClientBuilder().
+				WithObjects(tt.csr()).
+				WithIndex(&certificatesv1.CertificateSigningRequest{}, "spec.signerName", func(obj client.Object) []string {
+					return []string{obj.(*certificatesv1.CertificateSigningRequest).Spec.SignerName}
+				}).
+				Build()
+			r := &DpuNodeLifecycleController{Client: newFakeClient(), TenantConfigs: tenantConfigs}
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "dpu-0"}}
+
+			pending, err := r.syncNodeIdentity(ctx, logr.Discard(), cfg, node, tenantClient, "worker-0")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(pending).To(BeFalse())
+
+			csr := &certificatesv1.CertificateSigningRequest{}
+			g.Expect(tenantClient.Get(ctx, client.ObjectKey{Name: "csr"}, csr)).To(Succeed())
+			g.Expect(isCSRApproved(csr)).To(Equal(tt.approved))
+			g.Expect(len(csr.Status.Conditions)).To(BeNumerically("<=", 1))
+		})
+	}
+}
+
+func TestSyncNodeIdentityPodNotReady(t *testing.T) {
+	g := NewWithT(t)
+	cfg := &dpuv1alpha1.DpuClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "tenant-a"},
+		Spec:       dpuv1alpha1.DpuClusterConfigSpec{NodeIdentity: &dpuv1alpha1.NodeIdentitySpec{}},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ovnkube-node-abcde", Namespace: cfg.Namespace, Labels: map[string]string{"app": utils.OvnkubeNodeDsName}},
+		Spec:       corev1.PodSpec{NodeName: "dpu-0"},
+		Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}},
+	}
+	tenantConfigs := utils.NewTenantRestConfigStore()
+	tenantConfigs.Set(cfg.Namespace, newTenantClientCertConfig(t, testTenantUsername))
+	tenantClient := newFakeClientBuilder().
+		WithIndex(&certificatesv1.CertificateSigningRequest{}, "spec.signerName", func(obj client.Object) []string {
+			return []string{obj.(*certificatesv1.CertificateSigningRequest).Spec.SignerName}
+		}).
+		Build()
+	r := &DpuNodeLifecycleController{Client: newFakeClient(pod), TenantConfigs: tenantConfigs}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "dpu-0"}}
+
+	pending, err := r.syncNodeIdentity(context.Background(), logr.Discard(), cfg, node, tenantClient, "worker-0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pending).To(BeTrue())
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// nodeMaintenanceAvailableCondition is the condition of the DPU nodes
+	// telling whether the tenant cluster serves a NodeMaintenance API. The
+	// tenant node cannot be drained, and so the DPU node stays undrainable,
+	// as long as it does not.
+	nodeMaintenanceAvailableCondition corev1.NodeConditionType = "DpuNodeMaintenanceAvailable"
+
+	nodeMaintenanceKind = "NodeMaintenance"
+	// nodeMaintenanceSucceeded is the status.phase of a NodeMaintenance
+	// whose node is drained
+	nodeMaintenanceSucceeded = "Succeeded"
+)
+
+// nodeMaintenanceGroups are the API groups of the NodeMaintenance kind, by
+// order of preference: the one of the medik8s node-maintenance-operator, then
+// the one of its former KubeVirt releases. The version served by the tenant
+// cluster is discovered, their spec and status being compatible.
+var nodeMaintenanceGroups = []string{"nodemaintenance.medik8s.io", "nodemaintenance.kubevirt.io"}
+
+// nodeMaintenanceGVK returns the NodeMaintenance kind served by the tenant
+// cluster, nil if none is
+func nodeMaintenanceGVK(tenantClient client.Client) (*schema.GroupVersionKind, error) {
+	for _, group := range nodeMaintenanceGroups {
+		mapping, err := tenantClient.RESTMapper().RESTMapping(schema.GroupKind{Group: group, Kind: nodeMaintenanceKind})
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &mapping.GroupVersionKind, nil
+	}
+	return nil, nil
+}
+
+// syncNodeMaintenanceCondition sets the DpuNodeMaintenanceAvailable condition
+// of the DPU node from the NodeMaintenance kind gvk of the tenant cluster
+func (r *DpuNodeLifecycleController) syncNodeMaintenanceCondition(ctx context.Context, node *corev1.Node, gvk *schema.GroupVersionKind) error {
+	condition := corev1.NodeCondition{
+		Type:   nodeMaintenanceAvailableCondition,
+		Status: corev1.ConditionFalse,
+		Reason: "NotInstalled",
+		Message: fmt.Sprintf("The tenant cluster serves none of the %v NodeMaintenance APIs, install the node-maintenance-operator to drain the tenant nodes",
+			nodeMaintenanceGroups),
+	}
+	if gvk != nil {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "Discovered"
+		condition.Message = fmt.Sprintf("The tenant nodes are drained with the %s API", gvk.GroupVersion())
+	}
+	changed, err := r.setNodeCondition(ctx, node, condition)
+	if changed && gvk == nil {
+		r.Log.Info("No NodeMaintenance API in the tenant cluster, the drain of the DPU node stays blocked", "node", node.Name)
+	}
+	return err
+}
+
+// newNodeMaintenance returns an empty NodeMaintenance of kind gvk
+func newNodeMaintenance(gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+	nm := &unstructured.Unstructured{}
+	nm.SetGroupVersionKind(gvk)
+	nm.SetNamespace(namespace)
+	nm.SetName(name)
+	return nm
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// syncOpiBridge deploys the OPI bridge on the DPU nodes when it is enabled
+// in the spec, and removes it otherwise.
+func (r *DpuClusterConfigReconciler) syncOpiBridge(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	if cfg.Spec.OpiBridge == nil {
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: utils.OpiBridgeDsName, Namespace: cfg.Namespace}}
+		return utils.DeleteObject(r.Client, ds)
+	}
+
+	logger.Info("Start to sync the OPI bridge daemonset")
+	nodeSelector, err := r.getDpuNodeSelector(cfg)
+	if err != nil {
+		return err
+	}
+	port := cfg.Spec.OpiBridge.Port
+	if port == 0 {
+		port = 50051
+	}
+
+	data := render.MakeRenderData()
+	data.Data["Namespace"] = cfg.Namespace
+	data.Data["OpiBridgeImage"] = cfg.Spec.OpiBridge.Image
+	data.Data["OpiBridgePort"] = port
+
+	objs, err := renderDir(ctx, utils.OpiBridgeManifestPath, &data)
+	if err != nil {
+		logger.Error(err, "Fail to render the OPI bridge manifests")
+		return err
+	}
+	return r.applyObjects(ctx, cfg, objs, nodeSelector)
+}
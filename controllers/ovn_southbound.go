@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+//+kubebuilder:rbac:groups="",resources=pods/status,verbs=patch
+
+const (
+	// ovnSouthboundConnectedCondition is the condition of the DPU nodes
+	// telling whether their ovn-controller is connected to the southbound
+	// database, as checked by the OVS agent
+	ovnSouthboundConnectedCondition corev1.NodeConditionType = "DpuOvnSouthboundConnected"
+)
+
+// syncOvnSouthboundCondition sets the DpuOvnSouthboundConnected condition of
+// the DPU node from the condition of its OVS agent pod. The condition is set
+// to Unknown when the agent no longer reports it, e.g. once disabled.
+func (r *DpuNodeLifecycleController) syncOvnSouthboundCondition(ctx context.Context, log logr.Logger, cfg *dpuv1alpha1.DpuClusterConfig, node *corev1.Node) error {
+	var reported *corev1.PodCondition
+	if cfg != nil && cfg.Spec.OvsAgent != nil {
+		pods := &corev1.PodList{}
+		if err := r.List(ctx, pods, client.InNamespace(cfg.Namespace), client.MatchingLabels{"app": utils.OvsAgentName}); err != nil {
+			return err
+		}
+		for i := range pods.Items {
+			if pods.Items[i].Spec.NodeName != node.Name {
+				continue
+			}
+			for j, c := range pods.Items[i].Status.Conditions {
+				if c.Type == utils.OvnSouthboundPodCondition {
+					reported = &pods.Items[i].Status.Conditions[j]
+				}
+			}
+		}
+	}
+
+	condition := corev1.NodeCondition{Type: ovnSouthboundConnectedCondition}
+	if reported != nil {
+		condition.Status = reported.Status
+		condition.Reason = reported.Reason
+		condition.Message = reported.Message
+	} else {
+		// The condition is only set once the agent reported it
+		found := false
+		for _, c := range node.Status.Conditions {
+			found = found || c.Type == ovnSouthboundConnectedCondition
+		}
+		if !found {
+			return nil
+		}
+		condition.Status = corev1.ConditionUnknown
+		condition.Reason = "NotReported"
+		condition.Message = "the OVS agent of the node does not report the southbound database connection"
+	}
+	changed, err := r.setNodeCondition(ctx, node, condition)
+	if changed && condition.Status == corev1.ConditionFalse {
+		log.Info("ovn-controller is not connected to the southbound database", "reason", condition.Reason, "message", condition.Message)
+	}
+	return err
+}
+
+// dpuNodePodRequests enqueues the DPU node of an OVS agent or ovnkube-node
+// pod, whose conditions are reflected on the DPU node and its tenant node
+func dpuNodePodRequests(obj client.Object) []reconcile.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil
+	}
+	if app := pod.Labels["app"]; app != utils.OvsAgentName && app != utils.OvnkubeNodeDsName {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: pod.Spec.NodeName}}}
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/dpu-network-operator/api"
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+const (
+	// defaultOvnServerName is the name the certificates of the OVN
+	// databases of OpenShift are issued for
+	defaultOvnServerName = "ovn"
+	// ovnCaBundleKey is the key of the CA bundle of the ovn-ca ConfigMap
+	ovnCaBundleKey = "ca-bundle.crt"
+)
+
+// ovnServerName returns the name ovnkube-node verifies the certificates of
+// the OVN databases against
+func ovnServerName(cfg *dpuv1alpha1.DpuClusterConfig) string {
+	if cfg.Spec.OvnTLS != nil && cfg.Spec.OvnTLS.ServerName != "" {
+		return cfg.Spec.OvnTLS.ServerName
+	}
+	return defaultOvnServerName
+}
+
+// setOvnTLSRenderData sets the TLS options of the OVS daemons connecting to
+// or serving the OVN databases. OVS takes the accepted protocols rather than
+// the lowest one.
+func setOvnTLSRenderData(cfg *dpuv1alpha1.DpuClusterConfig, data *render.RenderData) {
+	data.Data["OvnServerName"] = ovnServerName(cfg)
+	data.Data["OvnSslProtocols"] = ""
+	data.Data["OvnSslCiphers"] = ""
+	spec := cfg.Spec.OvnTLS
+	if spec == nil {
+		return
+	}
+	switch spec.MinVersion {
+	case "TLSv1.2":
+		data.Data["OvnSslProtocols"] = "TLSv1.2,TLSv1.3"
+	case "TLSv1.3":
+		data.Data["OvnSslProtocols"] = "TLSv1.3"
+	}
+	data.Data["OvnSslCiphers"] = spec.Ciphers
+}
+
+// checkOvnTLS sets the OvnTLSValid condition of cfg when ovnTLS is set. The
+// ovn-cert synced from the tenant cluster must be signed by the ovn-ca and,
+// as the OVSDB relay serves it, valid for the server name verified by the
+// ovnkube-node pods. Nothing is checked until both are synced.
+func (r *DpuClusterConfigReconciler) checkOvnTLS(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	if cfg.Spec.OvnTLS == nil {
+		meta.RemoveStatusCondition(&cfg.Status.Conditions, api.OvnTLSValid)
+		return nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: utils.SecretNameOvnCert}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: utils.CmNameOvnCa}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	serverName := ""
+	if cfg.Spec.OvsdbRelay != nil {
+		serverName = ovnServerName(cfg)
+	}
+	reason, err := verifyOvnCert(secret.Data[corev1.TLSCertKey], []byte(cm.Data[ovnCaBundleKey]), serverName)
+	if err != nil {
+		logger.Info("The ovn-cert does not pass the TLS checks", "reason", reason, "error", err.Error())
+		meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().NotOvnTLSValid().Reason(reason).Msg(err.Error()).Build())
+		return nil
+	}
+	meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().OvnTLSValid().Reason(api.ReasonValid).Build())
+	return nil
+}
+
+// verifyOvnCert checks that the first certificate of certPEM chains to the
+// CA bundle, the other ones being intermediates, and that it is valid for
+// serverName unless empty. It returns the reason of the failure along with
+// the error.
+func verifyOvnCert(certPEM, caPEM []byte, serverName string) (string, error) {
+	var cert *x509.Certificate
+	intermediates := x509.NewCertPool()
+	for rest := certPEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return api.ReasonCertificateInvalid, fmt.Errorf("failed to parse the ovn-cert: %v", err)
+		}
+		if cert == nil {
+			cert = c
+		} else {
+			intermediates.AddCert(c)
+		}
+	}
+	if cert == nil {
+		return api.ReasonCertificateInvalid, fmt.Errorf("no PEM certificate found in the ovn-cert")
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return api.ReasonCertificateInvalid, fmt.Errorf("no PEM certificate found in the ovn-ca")
+	}
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return api.ReasonCertificateInvalid, fmt.Errorf("the ovn-cert is not signed by the ovn-ca: %v", err)
+	}
+	if serverName == "" {
+		return "", nil
+	}
+	if err := cert.VerifyHostname(serverName); err != nil {
+		return api.ReasonMissingSubjectAltName, fmt.Errorf("the ovn-cert served by the OVSDB relay is not valid for %s, its subject alternative names are %v %v", serverName, cert.DNSNames, cert.IPAddresses)
+	}
+	return "", nil
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// ovnkubeConfigKey is the key of the configuration file of the ovnkube-config
+const ovnkubeConfigKey = "ovnkube.conf"
+
+// getOvnkubeConfig returns the ovnkube-config mirrored from the tenant
+// cluster, nil until it is mirrored
+func (r *DpuClusterConfigReconciler) getOvnkubeConfig(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: utils.CmNameOvnkubeConfig}, cm)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// ovnkubeConfigHash hashes the data of the ovnkube-config. ovnkube only reads
+// its configuration file at startup, so the hash is set on the pod template
+// of the DaemonSets to restart their pods when it changes.
+func ovnkubeConfigHash(cm *corev1.ConfigMap) string {
+	if cm == nil {
+		return ""
+	}
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(cm.Data[k]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ovnkubeConfigMtu returns the MTU of the [default] section of the
+// ovnkube-config, "" when unset
+func ovnkubeConfigMtu(cm *corev1.ConfigMap) string {
+	if cm == nil {
+		return ""
+	}
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(cm.Data[ovnkubeConfigKey]))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if found && section == "default" && strings.TrimSpace(key) == "mtu" {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return ""
+}
+
+// setDaemonSetConfigHash sets the hash of the ovnkube-config on the pod
+// template of the DaemonSet, its pods being rolled out one at a time when the
+// hash changes
+func setDaemonSetConfigHash(obj *unstructured.Unstructured, hash string) error {
+	if hash == "" {
+		return nil
+	}
+	return unstructured.SetNestedField(obj.Object, hash, "spec", "template", "metadata", "annotations", utils.OvnkubeConfigHashAnnotation)
+}
+
+// logOvnkubeConfigChange logs the roll out of the DaemonSet following a
+// change of the ovnkube-config of the tenant cluster
+func (r *DpuClusterConfigReconciler) logOvnkubeConfigChange(ctx context.Context, namespace, name string, cm *corev1.ConfigMap, hash string) {
+	ds := &appsv1.DaemonSet{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, ds); err != nil {
+		return
+	}
+	if old := ds.Spec.Template.Annotations[utils.OvnkubeConfigHashAnnotation]; old != "" && old != hash {
+		logger.Info("The ovnkube-config of the tenant cluster changed, roll out the daemonset", "daemonset", name, "mtu", ovnkubeConfigMtu(cm))
+	}
+}
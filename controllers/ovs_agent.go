@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/agent"
+	"github.com/openshift/dpu-network-operator/pkg/metrics"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// syncOvsAgent deploys the agent enforcing the OVS settings and the functions
+// of the DpuResources on the DPU nodes when it is enabled in the spec, and
+// removes it otherwise.
+func (r *DpuClusterConfigReconciler) syncOvsAgent(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	if cfg.Spec.OvsAgent == nil {
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: utils.OvsAgentName, Namespace: cfg.Namespace}}
+		if err := utils.DeleteObject(r.Client, ds); err != nil {
+			return err
+		}
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: utils.OvsAgentName, Namespace: cfg.Namespace}}
+		if err := utils.DeleteObject(r.Client, cm); err != nil {
+			return err
+		}
+		binding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: utils.OvsAgentName, Namespace: cfg.Namespace}}
+		if err := utils.DeleteObject(r.Client, binding); err != nil {
+			return err
+		}
+		role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: utils.OvsAgentName, Namespace: cfg.Namespace}}
+		return utils.DeleteObject(r.Client, role)
+	}
+
+	logger.Info("Start to sync the OVS agent daemonset")
+	nodeSelector, err := r.getDpuNodeSelector(cfg)
+	if err != nil {
+		return err
+	}
+	functions, err := r.dpuResourceFunctions(ctx, cfg, nodeSelector)
+	if err != nil {
+		return err
+	}
+	ovsSettings := ovsAgentSettings(cfg)
+	ovsSettings.Functions = functions
+	settings, err := json.MarshalIndent(ovsSettings, "", "  ")
+	if err != nil {
+		return err
+	}
+	interval := time.Minute
+	if cfg.Spec.OvsAgent.Interval != nil {
+		interval = cfg.Spec.OvsAgent.Interval.Duration
+	}
+
+	data := render.MakeRenderData()
+	data.Data["Namespace"] = cfg.Namespace
+	data.Data["Image"] = cfg.Spec.OvsAgent.Image
+	data.Data["Settings"] = string(settings)
+	data.Data["Interval"] = interval.String()
+	data.Data["PriorityClassName"] = priorityClassName(cfg)
+	data.Data["SimulateHardware"] = utils.SimulateHardware
+	data.Data["Statistics"] = false
+	if stats := cfg.Spec.OvsAgent.Statistics; stats != nil {
+		statsInterval, statsPort := 30*time.Second, int32(9106)
+		if stats.Interval != nil {
+			statsInterval = stats.Interval.Duration
+		}
+		if stats.Port != 0 {
+			statsPort = stats.Port
+		}
+		data.Data["Statistics"] = true
+		data.Data["StatsInterval"] = statsInterval.String()
+		data.Data["StatsPort"] = statsPort
+		data.Data["StatsPortName"] = metrics.OvsAgentPortName
+	}
+
+	objs, err := renderDir(ctx, utils.OvsAgentPath, &data)
+	if err != nil {
+		logger.Error(err, "Fail to render the OVS agent manifests")
+		return err
+	}
+	return r.applyObjects(ctx, cfg, objs, nodeSelector)
+}
+
+// ovsAgentSettings returns the OVS settings enforced by the agent, i.e. the
+// other_config options of the hardwareOffload and the ones of the ovsAgent
+func ovsAgentSettings(cfg *dpuv1alpha1.DpuClusterConfig) agent.OvsSettings {
+	settings := agent.OvsSettings{OtherConfig: map[string]string{}}
+	for _, option := range ovsOtherConfig(cfg) {
+		settings.OtherConfig[option.key] = option.value
+	}
+	spec := cfg.Spec.OvsAgent
+	if spec.FlowLimit != nil {
+		settings.OtherConfig["flow-limit"] = strconv.Itoa(int(*spec.FlowLimit))
+	}
+	if spec.BridgeMappings != "" {
+		settings.ExternalIDs = map[string]string{"ovn-bridge-mappings": spec.BridgeMappings}
+	}
+	return settings
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// syncOvsDump requests an OVS dump of the DPU node to its OVS agent when the
+// value of the ovs-dump annotation of the node changed since the last dump.
+// The request is the dpu-ovs-dump-<node> ConfigMap of the namespace of cfg,
+// emptied and annotated with the requested value, which the agent fills with
+// the dumps.
+func (r *DpuNodeLifecycleController) syncOvsDump(ctx context.Context, log logr.Logger, cfg *dpuv1alpha1.DpuClusterConfig, node *corev1.Node) error {
+	request, found := node.Annotations[utils.OvsDumpAnnotation]
+	if !found || cfg == nil {
+		return nil
+	}
+	if cfg.Spec.OvsAgent == nil {
+		log.Info("OVS dump requested but the OVS agent is not enabled", "request", request)
+		return nil
+	}
+	name := types.NamespacedName{Namespace: cfg.Namespace, Name: utils.OvsDumpConfigMapPrefix + node.Name}
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, name, cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && cm.Annotations[utils.OvsDumpAnnotation] == request {
+		return nil
+	}
+
+	log.Info("Request an OVS dump", "request", request, "configmap", name.Name)
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}}
+		if err := ctrl.SetControllerReference(cfg, cm, r.Scheme); err != nil {
+			return err
+		}
+	}
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[utils.OvsDumpAnnotation] = request
+	delete(cm.Annotations, utils.OvsDumpCompletedAnnotation)
+	cm.Data = nil
+	if cm.ResourceVersion == "" {
+		return r.Create(ctx, cm)
+	}
+	return r.Update(ctx, cm)
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+
+// renderOvsdbRelayManifests renders the relay of the southbound database at
+// sbDbList, running image on the nodes selected in the spec of cfg. The image
+// of the spec is preferred to image, the ovnkube image.
+func renderOvsdbRelayManifests(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, image, sbDbList string) ([]*unstructured.Unstructured, error) {
+	spec := cfg.Spec.OvsdbRelay
+	replicas := spec.Replicas
+	if replicas == 0 {
+		replicas = 2
+	}
+	if spec.Image != "" {
+		image = spec.Image
+	}
+	if image == "" {
+		return nil, fmt.Errorf("the ovnkube image is required to render the OVSDB relay manifests")
+	}
+
+	data := render.MakeRenderData()
+	data.Data["Namespace"] = cfg.Namespace
+	data.Data["OvsdbRelayImage"] = image
+	data.Data["OvsdbRelayPort"] = OVN_SB_PORT
+	data.Data["Replicas"] = replicas
+	data.Data["OVN_SB_DB_LIST"] = sbDbList
+	setOvnTLSRenderData(cfg, &data)
+
+	objs, err := renderDir(ctx, utils.OvsdbRelayPath, &data)
+	if err != nil {
+		logger.Error(err, "Fail to render the OVSDB relay manifests")
+		return nil, err
+	}
+	for _, obj := range objs {
+		if obj.GetKind() != "Deployment" || len(spec.NodeSelector) == 0 {
+			continue
+		}
+		nodeSelector, _, err := unstructured.NestedStringMap(obj.Object, "spec", "template", "spec", "nodeSelector")
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range spec.NodeSelector {
+			nodeSelector[k] = v
+		}
+		if err := unstructured.SetNestedStringMap(obj.Object, nodeSelector, "spec", "template", "spec", "nodeSelector"); err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
+// getOvsdbRelayDbList returns the address of the relay Service of cfg, to
+// which the ovnkube-node pods connect. It is empty until the Service is
+// created and has its ClusterIP.
+func (r *DpuClusterConfigReconciler) getOvsdbRelayDbList(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (string, error) {
+	svc := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: utils.OvsdbRelayName}, svc); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if svc.Spec.ClusterIP == "" || svc.Spec.ClusterIP == corev1.ClusterIPNone {
+		return "", nil
+	}
+	return dbList([]string{svc.Spec.ClusterIP}, OVN_SB_PORT), nil
+}
+
+// deleteOvsdbRelay removes the relay of cfg once it is disabled in the spec
+func (r *DpuClusterConfigReconciler) deleteOvsdbRelay(cfg *dpuv1alpha1.DpuClusterConfig) error {
+	meta := metav1.ObjectMeta{Name: utils.OvsdbRelayName, Namespace: cfg.Namespace}
+	for _, obj := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: meta},
+		&corev1.Service{ObjectMeta: meta},
+		&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: utils.OvsdbRelayPolicyName, Namespace: cfg.Namespace}},
+	} {
+		if err := utils.DeleteObject(r.Client, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
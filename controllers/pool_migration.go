@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+const (
+	// poolMigrationLabel is set on the DPU nodes moved to the renamed pool
+	// of their DpuClusterConfig, it is removed once all the nodes are moved
+	poolMigrationLabel = "dpu.openshift.io/pool"
+
+	poolMigrationPollPeriod = 30 * time.Second
+)
+
+// getStaleMachineConfigPools returns the pools previously created for cfg
+// which are not among its pools anymore, they remain until their nodes are
+// moved to the main pool of cfg
+func (r *DpuClusterConfigReconciler) getStaleMachineConfigPools(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) ([]mcfgv1.MachineConfigPool, error) {
+	mcps := &mcfgv1.MachineConfigPoolList{}
+	if err := r.List(ctx, mcps, client.MatchingLabels{dpuClusterConfigUIDLabel: string(cfg.UID)}); err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for _, pool := range dpuPools(cfg) {
+		names[pool.cfg.Spec.PoolName] = true
+	}
+	stale := []mcfgv1.MachineConfigPool{}
+	for _, mcp := range mcps.Items {
+		if !names[mcp.Name] {
+			stale = append(stale, mcp)
+		}
+	}
+	return stale, nil
+}
+
+// poolMigrationNodeSelector restricts selector to the nodes labelled with
+// poolMigrationLabel, or not labelled with it when exclude is true
+func poolMigrationNodeSelector(selector *metav1.LabelSelector, pool string, exclude bool) *metav1.LabelSelector {
+	s := &metav1.LabelSelector{}
+	if selector != nil {
+		s = selector.DeepCopy()
+	}
+	requirement := metav1.LabelSelectorRequirement{Key: poolMigrationLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{pool}}
+	if exclude {
+		requirement.Operator = metav1.LabelSelectorOpNotIn
+	}
+	for _, r := range s.MatchExpressions {
+		if equality.Semantic.DeepEqual(r, requirement) {
+			return s
+		}
+	}
+	s.MatchExpressions = append(s.MatchExpressions, requirement)
+	return s
+}
+
+// migratePool moves the nodes of the pools previously created for cfg to its
+// main pool one at a time, waiting for the pool to roll out after each move,
+// then deletes the previous pools. The new pool only selects
+// the nodes labelled with poolMigrationLabel in the meantime, while the
+// previous pools select the other ones. A message describing the progress is
+// returned until the migration is over.
+func (r *DpuClusterConfigReconciler) migratePool(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (string, error) {
+	stale, err := r.getStaleMachineConfigPools(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+	pool := cfg.Spec.PoolName
+	if len(stale) == 0 {
+		if err := r.removePoolMigrationLabels(ctx, pool); err != nil {
+			return "", err
+		}
+		return "", r.deleteStaleMachineConfigObjs(ctx, cfg)
+	}
+
+	for i := range stale {
+		selector := poolMigrationNodeSelector(stale[i].Spec.NodeSelector, pool, true)
+		if equality.Semantic.DeepEqual(selector, stale[i].Spec.NodeSelector) {
+			continue
+		}
+		logger.Info("Exclude the migrated nodes from the previous MachineConfigPool", "name", stale[i].Name)
+		stale[i].Spec.NodeSelector = selector
+		if err := r.Update(ctx, &stale[i]); err != nil {
+			return "", fmt.Errorf("couldn't update MachineConfigPool: %v", err)
+		}
+	}
+
+	mcp := &mcfgv1.MachineConfigPool{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pool}, mcp); err != nil {
+		return "", err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(cfg.Spec.NodeSelector)
+	if err != nil {
+		return "", err
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return "", err
+	}
+	var next *corev1.Node
+	migrated := int32(0)
+	for i := range nodes.Items {
+		if nodes.Items[i].Labels[poolMigrationLabel] == pool {
+			migrated++
+		} else if next == nil {
+			next = &nodes.Items[i]
+		}
+	}
+	if mcp.Status.MachineCount != migrated || mcp.Status.UpdatedMachineCount != migrated {
+		return fmt.Sprintf("waiting for MachineConfigPool %s to update %d nodes", pool, migrated), nil
+	}
+	if next != nil {
+		logger.Info("Move node to the renamed MachineConfigPool", "node", next.Name, "pool", pool)
+		patch := client.MergeFrom(next.DeepCopy())
+		if next.Labels == nil {
+			next.Labels = map[string]string{}
+		}
+		next.Labels[poolMigrationLabel] = pool
+		if err := r.Patch(ctx, next, patch); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("moving node %s to MachineConfigPool %s", next.Name, pool), nil
+	}
+
+	// The nodes left in the previous pools are not selected by the main pool,
+	// e.g. the ones of a removed additional pool, they are given back to the
+	// worker pool
+	if err := r.deleteStaleMachineConfigObjs(ctx, cfg); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted the previous MachineConfigPools of %s", pool), nil
+}
+
+// removePoolMigrationLabels removes poolMigrationLabel from the nodes moved
+// to pool once it selects them without it
+func (r *DpuClusterConfigReconciler) removePoolMigrationLabels(ctx context.Context, pool string) error {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabels{poolMigrationLabel: pool}); err != nil {
+		return err
+	}
+	for i := range nodes.Items {
+		patch := client.MergeFrom(nodes.Items[i].DeepCopy())
+		delete(nodes.Items[i].Labels, poolMigrationLabel)
+		if err := r.Patch(ctx, &nodes.Items[i], patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isPoolMigrating tells whether the nodes of cfg are being moved to its pool
+func (r *DpuClusterConfigReconciler) isPoolMigrating(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (bool, error) {
+	stale, err := r.getStaleMachineConfigPools(ctx, cfg)
+	return len(stale) > 0, err
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// selectsNode tells whether the MachineConfigPool named pool selects node
+func selectsNode(g *WithT, c client.Client, pool string, node string) bool {
+	ctx := context.Background()
+	mcp := &mcfgv1.MachineConfigPool{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: pool}, mcp)).To(Succeed())
+	n := &corev1.Node{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: node}, n)).To(Succeed())
+	selector, err := metav1.LabelSelectorAsSelector(mcp.Spec.NodeSelector)
+	g.Expect(err).NotTo(HaveOccurred())
+	return selector.Matches(labels.Set(n.Labels))
+}
+
+// setMachineCounts sets the machine counts of the status of the
+// MachineConfigPool named pool, as the machine-config-operator would
+func setMachineCounts(g *WithT, c client.Client, pool string, count int32) {
+	ctx := context.Background()
+	mcp := &mcfgv1.MachineConfigPool{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: pool}, mcp)).To(Succeed())
+	mcp.Status.MachineCount = count
+	mcp.Status.UpdatedMachineCount = count
+	g.Expect(c.Status().Update(ctx, mcp)).To(Succeed())
+}
+
+func TestMigratePool(t *testing.T) {
+	g := NewWithT(t)
+	chdirRepoRoot(t)
+	ctx := context.Background()
+	cfg := newTestConfig()
+	nodeLabels := cfg.Spec.NodeSelector.MatchLabels
+	r := &DpuClusterConfigReconciler{Client: newFakeClient(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "dpu-0", Labels: nodeLabels}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "dpu-1", Labels: nodeLabels}},
+	)}
+
+	// The pool is created as dpu-old, then renamed to dpu-bf2
+	old := cfg.DeepCopy()
+	old.Spec.PoolName = "dpu-old"
+	g.Expect(r.syncMachineConfigObjs(ctx, old)).To(Succeed())
+	g.Expect(r.syncMachineConfigObjs(ctx, cfg)).To(Succeed())
+
+	// Each pool renders its own MachineConfig, so that moving a node
+	// changes its rendered config
+	mcs := &mcfgv1.MachineConfigList{}
+	g.Expect(r.List(ctx, mcs)).To(Succeed())
+	for _, pool := range []string{"dpu-old", "dpu-bf2"} {
+		mcp := &mcfgv1.MachineConfigPool{}
+		g.Expect(r.Get(ctx, client.ObjectKey{Name: pool}, mcp)).To(Succeed())
+		g.Expect(selectedMachineConfigs(t, mcp, mcs.Items)).To(Equal([]string{"00-" + pool + "-bluefield-switchdev"}))
+	}
+
+	msg, err := r.migratePool(ctx, cfg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(msg).To(ContainSubstring("moving node dpu-0"))
+	g.Expect(selectsNode(g, r.Client, "dpu-bf2", "dpu-0")).To(BeTrue())
+	g.Expect(selectsNode(g, r.Client, "dpu-old", "dpu-0")).To(BeFalse())
+	g.Expect(selectsNode(g, r.Client, "dpu-bf2", "dpu-1")).To(BeFalse())
+	g.Expect(selectsNode(g, r.Client, "dpu-old", "dpu-1")).To(BeTrue())
+
+	// The next node is only moved once the pool updated the first one
+	msg, err = r.migratePool(ctx, cfg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(msg).To(ContainSubstring("waiting for MachineConfigPool dpu-bf2"))
+	setMachineCounts(g, r.Client, "dpu-bf2", 1)
+	msg, err = r.migratePool(ctx, cfg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(msg).To(ContainSubstring("moving node dpu-1"))
+	setMachineCounts(g, r.Client, "dpu-bf2", 2)
+
+	msg, err = r.migratePool(ctx, cfg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(msg).To(ContainSubstring("deleted the previous MachineConfigPools"))
+	g.Expect(errors.IsNotFound(r.Get(ctx, client.ObjectKey{Name: "dpu-old"}, &mcfgv1.MachineConfigPool{}))).To(BeTrue())
+	g.Expect(errors.IsNotFound(r.Get(ctx, client.ObjectKey{Name: machineConfigName(old)}, &mcfgv1.MachineConfig{}))).To(BeTrue())
+
+	// Once the pool selects the nodes without them, the labels are removed
+	g.Expect(r.syncMachineConfigObjs(ctx, cfg)).To(Succeed())
+	msg, err = r.migratePool(ctx, cfg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(msg).To(BeEmpty())
+	for _, name := range []string{"dpu-0", "dpu-1"} {
+		node := &corev1.Node{}
+		g.Expect(r.Get(ctx, client.ObjectKey{Name: name}, node)).To(Succeed())
+		g.Expect(node.Labels).To(Equal(nodeLabels))
+		g.Expect(selectsNode(g, r.Client, "dpu-bf2", name)).To(BeTrue())
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"fmt"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// dpuPool is a pool of DPU nodes of a DpuClusterConfig
+type dpuPool struct {
+	// cfg is the DpuClusterConfig with the overrides of the pool applied,
+	// it is the DpuClusterConfig itself for its main pool
+	cfg *dpuv1alpha1.DpuClusterConfig
+	// main is true for the pool of spec.poolName
+	main bool
+}
+
+// dpuPools returns the pools of cfg, starting with its main pool
+func dpuPools(cfg *dpuv1alpha1.DpuClusterConfig) []dpuPool {
+	pools := []dpuPool{{cfg: cfg, main: true}}
+	for _, p := range cfg.Spec.Pools {
+		poolCfg := cfg.DeepCopy()
+		poolCfg.Spec.Pools = nil
+		poolCfg.Spec.PoolName = p.PoolName
+		poolCfg.Spec.NodeSelector = p.NodeSelector
+		if p.OvnkubeImage != "" {
+			poolCfg.Spec.OvnkubeImage = p.OvnkubeImage
+		}
+		if p.HostPFRepresentor != "" {
+			poolCfg.Spec.HostPFRepresentor = p.HostPFRepresentor
+		}
+		if p.DrainBlockerImage != "" {
+			if poolCfg.Spec.DrainBlocker == nil {
+				poolCfg.Spec.DrainBlocker = &dpuv1alpha1.DrainBlockerSpec{}
+			}
+			poolCfg.Spec.DrainBlocker.Image = p.DrainBlockerImage
+		}
+		pools = append(pools, dpuPool{cfg: poolCfg})
+	}
+	return pools
+}
+
+// ovnkubeNodeName returns the name of the ovnkube-node DaemonSet of the pool
+func (p dpuPool) ovnkubeNodeName() string {
+	if p.main {
+		return utils.OvnkubeNodeDsName
+	}
+	return utils.OvnkubeNodeDsName + "-" + p.cfg.Spec.PoolName
+}
+
+// ovnIPsecName returns the name of the ovn-ipsec DaemonSet of the pool
+func (p dpuPool) ovnIPsecName() string {
+	if p.main {
+		return utils.OvnIPsecDsName
+	}
+	return utils.OvnIPsecDsName + "-" + p.cfg.Spec.PoolName
+}
+
+// validatePools checks the names of the pools of cfg
+func validatePools(cfg *dpuv1alpha1.DpuClusterConfig) error {
+	names := map[string]bool{}
+	for _, p := range dpuPools(cfg) {
+		if err := validatePoolName(p.cfg.Spec.PoolName); err != nil {
+			return err
+		}
+		// The main pool selects the MachineConfigs of the dpu-worker role
+		if !p.main && p.cfg.Spec.PoolName == dpuMcRole {
+			return fmt.Errorf("pool name %s is reserved for the main pool", dpuMcRole)
+		}
+		if names[p.cfg.Spec.PoolName] {
+			return fmt.Errorf("pool %s is defined more than once", p.cfg.Spec.PoolName)
+		}
+		names[p.cfg.Spec.PoolName] = true
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// newPoolsTestConfig returns a DpuClusterConfig of the dpu-bf2 pool and the
+// dpu-bf3 additional pool
+func newPoolsTestConfig() *dpuv1alpha1.DpuClusterConfig {
+	cfg := newTestConfig()
+	cfg.Spec.Pools = []dpuv1alpha1.DpuPoolSpec{{
+		PoolName:          "dpu-bf3",
+		NodeSelector:      &metav1.LabelSelector{MatchLabels: map[string]string{"node-role.kubernetes.io/dpu-bf3": ""}},
+		HostPFRepresentor: "pf0hpf",
+	}}
+	return cfg
+}
+
+// selectedMachineConfigs returns the names of the MachineConfigs rendered by
+// mcp
+func selectedMachineConfigs(t *testing.T, mcp *mcfgv1.MachineConfigPool, mcs []mcfgv1.MachineConfig) []string {
+	selector, err := metav1.LabelSelectorAsSelector(mcp.Spec.MachineConfigSelector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, mc := range mcs {
+		if selector.Matches(labels.Set(mc.Labels)) {
+			names = append(names, mc.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSyncMachineConfigObjsPerPool(t *testing.T) {
+	g := NewWithT(t)
+	chdirRepoRoot(t)
+	ctx := context.Background()
+	workerMc := &mcfgv1.MachineConfig{ObjectMeta: metav1.ObjectMeta{
+		Name:   "00-worker",
+		Labels: map[string]string{mcfgv1.MachineConfigRoleLabelKey: "worker"},
+	}}
+	r := &DpuClusterConfigReconciler{Client: newFakeClient(workerMc)}
+	cfg := newPoolsTestConfig()
+
+	g.Expect(r.syncMachineConfigObjs(ctx, cfg)).To(Succeed())
+
+	mcs := &mcfgv1.MachineConfigList{}
+	g.Expect(r.List(ctx, mcs)).To(Succeed())
+	g.Expect(mcs.Items).To(HaveLen(3))
+	for _, pool := range dpuPools(cfg) {
+		mcp := &mcfgv1.MachineConfigPool{}
+		g.Expect(r.Get(ctx, client.ObjectKey{Name: pool.cfg.Spec.PoolName}, mcp)).To(Succeed())
+		g.Expect(selectedMachineConfigs(t, mcp, mcs.Items)).To(Equal([]string{machineConfigName(pool.cfg), "00-worker"}),
+			"pool %s", pool.cfg.Spec.PoolName)
+	}
+}
+
+func TestSyncMachineConfigObjsPoolConflict(t *testing.T) {
+	g := NewWithT(t)
+	chdirRepoRoot(t)
+	ctx := context.Background()
+	r := &DpuClusterConfigReconciler{Client: newFakeClient()}
+	cfg := newPoolsTestConfig()
+	other := newPoolsTestConfig()
+	other.Namespace = "tenant-b"
+	other.UID = "uid-b"
+	other.Spec.Pools = nil
+
+	g.Expect(r.syncMachineConfigObjs(ctx, cfg)).To(Succeed())
+	err := r.syncMachineConfigObjs(ctx, other)
+	var conflict *poolConflictError
+	g.Expect(errors.As(err, &conflict)).To(BeTrue(), "unexpected error %v", err)
+	g.Expect(conflict.kind).To(Equal("MachineConfigPool"))
+
+	mcp := &mcfgv1.MachineConfigPool{}
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: cfg.Spec.PoolName}, mcp)).To(Succeed())
+	g.Expect(mcp.Labels).To(HaveKeyWithValue(dpuClusterConfigUIDLabel, "uid-a"))
+
+	// The MachineConfig is checked as well when the pool was adopted
+	delete(mcp.Labels, dpuClusterConfigUIDLabel)
+	g.Expect(r.Update(ctx, mcp)).To(Succeed())
+	err = r.syncMachineConfigObjs(ctx, other)
+	g.Expect(errors.As(err, &conflict)).To(BeTrue(), "unexpected error %v", err)
+	g.Expect(conflict.kind).To(Equal("MachineConfig"))
+	mc := &mcfgv1.MachineConfig{}
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: machineConfigName(cfg)}, mc)).To(Succeed())
+	g.Expect(mc.Labels).To(HaveKeyWithValue(dpuClusterConfigUIDLabel, "uid-a"))
+}
+
+func TestDpuNodeOfAdditionalPool(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	cfg := newPoolsTestConfig()
+	// The node of the additional pool has no DPU worker label
+	poolNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "dpu-bf3-0", Labels: map[string]string{"node-role.kubernetes.io/dpu-bf3": ""}}}
+	worker := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Labels: map[string]string{"node-role.kubernetes.io/worker": ""}}}
+	r := &DpuNodeLifecycleController{
+		Client:        newFakeClient(cfg, poolNode, worker),
+		Config:        &Config{},
+		Log:           logr.Discard(),
+		TenantConfigs: utils.NewTenantRestConfigStore(),
+		tenantClients: map[string]*tenantClient{},
+		requeue:       newRequeuePolicy(logr.Discard(), "DpuNodeLifecycle"),
+	}
+
+	g.Expect(r.isDpuNode(poolNode)).To(BeTrue())
+	g.Expect(r.isDpuNode(worker)).To(BeFalse())
+	g.Expect(r.dpuClusterConfigNodeRequests(cfg)).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(poolNode)}))
+	poolCfg, err := r.getDpuClusterConfig(ctx, poolNode)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(poolCfg.Spec.PoolName).To(Equal("dpu-bf3"))
+
+	// The node of the additional pool waits for the tenant client of its
+	// DpuClusterConfig, the other nodes are skipped
+	result, err := r.reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(poolNode)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(tenantClientRetryPeriod))
+	result, err = r.reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(worker)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func TestSyncMachineConfigObjsUpgrade(t *testing.T) {
+	g := NewWithT(t)
+	chdirRepoRoot(t)
+	ctx := context.Background()
+	cfg := newPoolsTestConfig()
+	cfg.Spec.Pools = nil
+	// The MachineConfigPool and MachineConfig created before the pools got
+	// their own roles, with a MachineConfig of the user for the pool
+	legacySelector, err := metav1.ParseToLabelSelector(mcfgv1.MachineConfigRoleLabelKey + " in (worker,dpu-worker)")
+	g.Expect(err).NotTo(HaveOccurred())
+	r := &DpuClusterConfigReconciler{Client: newFakeClient(
+		&mcfgv1.MachineConfigPool{
+			ObjectMeta: metav1.ObjectMeta{Name: cfg.Spec.PoolName, Labels: map[string]string{dpuClusterConfigUIDLabel: string(cfg.UID)}},
+			Spec:       mcfgv1.MachineConfigPoolSpec{MachineConfigSelector: legacySelector, NodeSelector: cfg.Spec.NodeSelector},
+		},
+		&mcfgv1.MachineConfig{ObjectMeta: metav1.ObjectMeta{
+			Name:   machineConfigName(cfg),
+			Labels: map[string]string{mcfgv1.MachineConfigRoleLabelKey: dpuMcRole, dpuClusterConfigUIDLabel: string(cfg.UID)},
+		}},
+		&mcfgv1.MachineConfig{ObjectMeta: metav1.ObjectMeta{
+			Name:   "50-user",
+			Labels: map[string]string{mcfgv1.MachineConfigRoleLabelKey: dpuMcRole},
+		}},
+		&mcfgv1.MachineConfig{ObjectMeta: metav1.ObjectMeta{
+			Name:   "00-worker",
+			Labels: map[string]string{mcfgv1.MachineConfigRoleLabelKey: "worker"},
+		}},
+	)}
+
+	g.Expect(r.syncMachineConfigObjs(ctx, cfg)).To(Succeed())
+
+	mc := &mcfgv1.MachineConfig{}
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: machineConfigName(cfg)}, mc)).To(Succeed())
+	g.Expect(mc.Labels).To(HaveKeyWithValue(mcfgv1.MachineConfigRoleLabelKey, cfg.Spec.PoolName))
+	mcs := &mcfgv1.MachineConfigList{}
+	g.Expect(r.List(ctx, mcs)).To(Succeed())
+	mcp := &mcfgv1.MachineConfigPool{}
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: cfg.Spec.PoolName}, mcp)).To(Succeed())
+	g.Expect(selectedMachineConfigs(t, mcp, mcs.Items)).To(Equal([]string{machineConfigName(cfg), "00-worker", "50-user"}))
+
+	// The MachineConfigs of the user for the dpu-worker role are not
+	// rendered in the additional pools
+	cfg = newPoolsTestConfig()
+	g.Expect(r.syncMachineConfigObjs(ctx, cfg)).To(Succeed())
+	g.Expect(r.List(ctx, mcs)).To(Succeed())
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: "dpu-bf3"}, mcp)).To(Succeed())
+	g.Expect(selectedMachineConfigs(t, mcp, mcs.Items)).To(Equal([]string{"00-dpu-bf3-bluefield-switchdev", "00-worker"}))
+}
+
+func TestValidatePoolsReservedRole(t *testing.T) {
+	g := NewWithT(t)
+	cfg := newPoolsTestConfig()
+	g.Expect(validatePools(cfg)).To(Succeed())
+	cfg.Spec.Pools[0].PoolName = dpuMcRole
+	g.Expect(validatePools(cfg)).NotTo(Succeed())
+	// The main pool may still use the role as its name
+	cfg = newPoolsTestConfig()
+	cfg.Spec.PoolName = dpuMcRole
+	g.Expect(validatePools(cfg)).To(Succeed())
+}
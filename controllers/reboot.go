@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// bootIDAnnotation holds the boot ID of the DPU node when it was last
+	// reconciled, a different boot ID in the node status meaning that the
+	// DPU rebooted since
+	bootIDAnnotation = "dpu.openshift.io/boot-id"
+	// rebootedEventReason is the reason of the events of the DPU node
+	// recording its reboots
+	rebootedEventReason = "DpuRebooted"
+)
+
+// syncBootID compares the boot ID of the DPU node with the one it had when it
+// was last reconciled, and records a reboot as an event of the node. A reboot
+// is expected when the DPU node was cordoned, i.e. under maintenance, and is
+// recorded as a warning otherwise. It returns whether the DPU node rebooted.
+func (r *DpuNodeLifecycleController) syncBootID(ctx context.Context, log logr.Logger, node *corev1.Node) (bool, error) {
+	bootID := node.Status.NodeInfo.BootID
+	lastBootID := node.Annotations[bootIDAnnotation]
+	if bootID == "" || bootID == lastBootID {
+		return false, nil
+	}
+	rebooted := lastBootID != ""
+	if rebooted {
+		// The drain state is the one from before the reboot, the DPU node
+		// being uncordoned by then once the maintenance is over
+		expected := node.Spec.Unschedulable || node.Annotations[drainStateAnnotation] == drainStateTenantDrained ||
+			node.Annotations[drainStateAnnotation] == drainStateWaitingForTenantDrain
+		if expected {
+			log.Info("DPU node rebooted", "bootID", bootID, "lastBootID", lastBootID)
+		} else {
+			log.Info("DPU node rebooted unexpectedly", "bootID", bootID, "lastBootID", lastBootID)
+		}
+		if r.Events != nil {
+			if expected {
+				r.Events.Eventf(node, corev1.EventTypeNormal, rebootedEventReason, "DPU node rebooted during its maintenance, boot ID %s", bootID)
+			} else {
+				r.Events.Eventf(node, corev1.EventTypeWarning, rebootedEventReason, "DPU node rebooted unexpectedly, boot ID %s", bootID)
+			}
+		}
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[bootIDAnnotation] = bootID
+	return rebooted, r.Patch(ctx, node, patch)
+}
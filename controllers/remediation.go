@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/audit"
+)
+
+const (
+	// defaultUnreachableTimeout is how long a DPU node is unreachable before
+	// its tenant node is remediated, unless set in the remediation
+	defaultUnreachableTimeout = 5 * time.Minute
+	// remediationDpuNodeLabel marks the remediation requests created by the
+	// operator with the name of the unreachable DPU node
+	remediationDpuNodeLabel = "dpu.openshift.io/dpu-node"
+)
+
+//+kubebuilder:rbac:groups=self-node-remediation.medik8s.io,resources=selfnoderemediationtemplates,verbs=get
+//+kubebuilder:rbac:groups=self-node-remediation.medik8s.io,resources=selfnoderemediations,verbs=get;create;delete
+
+// syncRemediation requests the remediation of the tenant node of the DPU node
+// once the DPU node has been unreachable for the unreachableTimeout of cfg,
+// and withdraws the request once the DPU node is back. It returns how long
+// to wait before the DPU node may be deemed dead, zero if it is not
+// unreachable or already deemed dead.
+func (r *DpuNodeLifecycleController) syncRemediation(ctx context.Context, log logr.Logger, cfg *dpuv1alpha1.DpuClusterConfig, node *corev1.Node, tenantClient client.Client, tenantNode string) (time.Duration, error) {
+	if cfg == nil || cfg.Spec.Remediation == nil {
+		return 0, nil
+	}
+	spec := cfg.Spec.Remediation
+	gv, err := schema.ParseGroupVersion(spec.Template.APIVersion)
+	if err != nil {
+		return 0, err
+	}
+	remediation := &unstructured.Unstructured{}
+	remediation.SetGroupVersionKind(gv.WithKind(strings.TrimSuffix(spec.Template.Kind, "Template")))
+	remediation.SetNamespace(spec.Template.Namespace)
+	remediation.SetName(tenantNode)
+	auditedClient := audit.NewTenantClient(tenantClient, fmt.Sprintf("remediate the tenant node of unreachable DPU node %s", node.Name), node, r.Recorder)
+
+	since, unreachable := dpuNodeUnreachableSince(node)
+	if !unreachable {
+		err := tenantClient.Get(ctx, client.ObjectKeyFromObject(remediation), remediation)
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		// The requests of the other remediators, e.g. NodeHealthCheck, are
+		// left alone
+		if remediation.GetLabels()[remediationDpuNodeLabel] != node.Name {
+			return 0, nil
+		}
+		log.Info("DPU node is reachable again, delete the remediation request of its tenant node", "tenantNode", tenantNode)
+		return 0, client.IgnoreNotFound(auditedClient.Delete(ctx, remediation))
+	}
+
+	timeout := defaultUnreachableTimeout
+	if spec.UnreachableTimeout != nil {
+		timeout = spec.UnreachableTimeout.Duration
+	}
+	if wait := time.Until(since.Add(timeout)); wait > 0 {
+		log.Info("DPU node is unreachable, wait before remediating its tenant node", "tenantNode", tenantNode, "wait", wait)
+		return wait, nil
+	}
+
+	err = tenantClient.Get(ctx, client.ObjectKeyFromObject(remediation), remediation)
+	if err == nil || !errors.IsNotFound(err) {
+		return 0, err
+	}
+	template := &unstructured.Unstructured{}
+	template.SetGroupVersionKind(gv.WithKind(spec.Template.Kind))
+	if err := tenantClient.Get(ctx, types.NamespacedName{Namespace: spec.Template.Namespace, Name: spec.Template.Name}, template); err != nil {
+		return 0, fmt.Errorf("failed to get the remediation template %s/%s: %v", spec.Template.Namespace, spec.Template.Name, err)
+	}
+	// The remediation templates hold the spec of the requests in
+	// spec.template.spec
+	templateSpec, _, err := unstructured.NestedMap(template.Object, "spec", "template", "spec")
+	if err != nil {
+		return 0, err
+	}
+	if templateSpec != nil {
+		remediation.Object["spec"] = templateSpec
+	}
+	remediation.SetLabels(map[string]string{remediationDpuNodeLabel: node.Name})
+	log.Info("DPU node is unreachable, request the remediation of its tenant node", "tenantNode", tenantNode, "kind", remediation.GetKind(), "since", since)
+	return 0, client.IgnoreAlreadyExists(auditedClient.Create(ctx, remediation))
+}
+
+// dpuNodeUnreachableSince returns since when the Ready condition of the DPU
+// node is not True, and whether it is not
+func dpuNodeUnreachableSince(node *corev1.Node) (time.Time, bool) {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.LastTransitionTime.Time, c.Status != corev1.ConditionTrue
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+// RenderOptions are the inputs of the rendering that the operator otherwise
+// discovers in the clusters
+type RenderOptions struct {
+	// OvnkubeImage is the image of ovnkube-node
+	OvnkubeImage string
+	// MasterIPs are the IPs of the ovnkube-master pods of the tenant cluster
+	MasterIPs []string
+	// OvsdbRelayIP is the ClusterIP of the OVSDB relay Service, to which the
+	// ovnkube-node pods connect when the relay is enabled
+	OvsdbRelayIP string
+	// SriovManagedDevices are the PCI addresses of the NICs configured by
+	// the sriov-network-operator, used with the Defer integration
+	SriovManagedDevices []string
+}
+
+// Render renders the MachineConfigs and the ovnkube-node manifests of cfg
+// offline, with the templates and the render data of the reconciler, and
+// returns them as a multi-document YAML. The ovnkube-node manifests are only
+// rendered when the OVN databases are known, i.e. for a hosted cluster or
+// when the master IPs are given.
+func Render(cfg *dpuv1alpha1.DpuClusterConfig, opts RenderOptions) (string, error) {
+	ctx := context.Background()
+	manifests := []string{}
+
+	if cfg.Spec.PoolName != "" {
+		if err := validatePools(cfg); err != nil {
+			return "", err
+		}
+		if err := validateHardwareOffload(cfg); err != nil {
+			return "", err
+		}
+		if err := validateHostNetwork(cfg); err != nil {
+			return "", err
+		}
+		for _, pool := range dpuPools(cfg) {
+			data := makeMachineConfigRenderData(pool.cfg, opts.SriovManagedDevices)
+			mc, err := renderMachineConfigManifest(ctx, pool.cfg, &data)
+			if err != nil {
+				return "", err
+			}
+			mc.APIVersion = "machineconfiguration.openshift.io/v1"
+			mc.Kind = "MachineConfig"
+			manifest, err := toYAML(mc)
+			if err != nil {
+				return "", err
+			}
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	var nbDbList, sbDbList string
+	if cfg.Spec.HostedCluster != nil {
+		nbDbList = cfg.Spec.HostedCluster.NbDbAddress
+		sbDbList = cfg.Spec.HostedCluster.SbDbAddress
+	} else if len(opts.MasterIPs) > 0 {
+		nbDbList = dbList(opts.MasterIPs, OVN_NB_PORT)
+		sbDbList = dbList(opts.MasterIPs, OVN_SB_PORT)
+	}
+	if nbDbList == "" {
+		return strings.Join(manifests, "---\n"), nil
+	}
+	if cfg.Spec.OvsdbRelay != nil {
+		if opts.OvsdbRelayIP == "" {
+			return "", fmt.Errorf("the ClusterIP of the OVSDB relay service is required to render the ovnkube-node manifests")
+		}
+		image := cfg.Spec.OvnkubeImage
+		if image == "" {
+			image = opts.OvnkubeImage
+		}
+		objs, err := renderOvsdbRelayManifests(ctx, cfg, image, sbDbList)
+		if err != nil {
+			return "", err
+		}
+		for _, obj := range objs {
+			manifest, err := toYAML(obj)
+			if err != nil {
+				return "", err
+			}
+			manifests = append(manifests, manifest)
+		}
+		sbDbList = dbList([]string{opts.OvsdbRelayIP}, OVN_SB_PORT)
+	}
+	for _, pool := range dpuPools(cfg) {
+		image := pool.cfg.Spec.OvnkubeImage
+		if image == "" {
+			image = opts.OvnkubeImage
+		}
+		if image == "" {
+			return "", fmt.Errorf("the ovnkube image is required to render the ovnkube-node manifests")
+		}
+		objs, err := renderOvnkubeNodeManifests(ctx, pool, nil, image, tenantKubeconfigKey(cfg), nbDbList, sbDbList)
+		if err != nil {
+			return "", err
+		}
+		nodeSelector := map[string]string{}
+		if pool.cfg.Spec.NodeSelector != nil {
+			nodeSelector = pool.cfg.Spec.NodeSelector.MatchLabels
+		}
+		for _, obj := range objs {
+			if obj.GetKind() == "DaemonSet" {
+				if err := setDaemonSetNodeSelector(obj, nodeSelector); err != nil {
+					return "", err
+				}
+			}
+			manifest, err := toYAML(obj)
+			if err != nil {
+				return "", err
+			}
+			manifests = append(manifests, manifest)
+		}
+	}
+	return strings.Join(manifests, "---\n"), nil
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/dpu-network-operator/api"
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+// networkAttachmentDefinitionGVK is the kind of the Multus secondary networks
+// of the tenant cluster
+var networkAttachmentDefinitionGVK = schema.GroupVersionKind{Group: "k8s.cni.cncf.io", Version: "v1", Kind: "NetworkAttachmentDefinition"}
+
+// representorName returns the name of the representor of the VF of the PF on
+// the DPU nodes, which the udev rules of the switchdev configuration keep
+func representorName(pfID, vf int32) string {
+	return fmt.Sprintf("pf%dvf%d", pfID, vf)
+}
+
+// validateRepresentorNetworks returns an error when a VF backs several
+// representor networks of cfg, or backs both a network and the management
+// port of the dpuHost
+func validateRepresentorNetworks(cfg *dpuv1alpha1.DpuClusterConfig) error {
+	owners := map[string]string{}
+	if cfg.Spec.DpuHost != nil && cfg.Spec.DpuHost.ManagementPort != nil {
+		port := cfg.Spec.DpuHost.ManagementPort
+		owners[representorName(port.PfID, port.FuncID)] = "the management port"
+	}
+	for _, network := range cfg.Spec.RepresentorNetworks {
+		for vf := network.FirstVF; vf <= network.LastVF; vf++ {
+			name := representorName(network.PfID, vf)
+			if owner, ok := owners[name]; ok {
+				return fmt.Errorf("representor %s of network %s is already used by %s", name, network.Network, owner)
+			}
+			owners[name] = "network " + network.Network
+		}
+	}
+	return nil
+}
+
+// syncRepresentorNetworks reports the representors of the DPU nodes backing
+// the Multus secondary networks of the tenant cluster in the status of cfg,
+// and whether the networks are valid with the RepresentorNetworksValid
+// condition
+func (r *DpuClusterConfigReconciler) syncRepresentorNetworks(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	if len(cfg.Spec.RepresentorNetworks) == 0 {
+		cfg.Status.RepresentorNetworks = nil
+		meta.RemoveStatusCondition(&cfg.Status.Conditions, api.RepresentorNetworksValid)
+		return nil
+	}
+	c, err := r.tenantClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	statuses := []dpuv1alpha1.RepresentorNetworkStatus{}
+	missing := []string{}
+	for _, network := range cfg.Spec.RepresentorNetworks {
+		status := dpuv1alpha1.RepresentorNetworkStatus{Network: network.Network, Representors: []string{}}
+		for vf := network.FirstVF; vf <= network.LastVF; vf++ {
+			status.Representors = append(status.Representors, representorName(network.PfID, vf))
+		}
+		namespace, name, _ := strings.Cut(network.Network, "/")
+		nad := &unstructured.Unstructured{}
+		nad.SetGroupVersionKind(networkAttachmentDefinitionGVK)
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, nad)
+		switch {
+		case err == nil:
+			status.Found = true
+		case errors.IsNotFound(err) || meta.IsNoMatchError(err):
+			// Without Multus, the tenant cluster does not serve the kind
+			missing = append(missing, network.Network)
+		default:
+			return err
+		}
+		statuses = append(statuses, status)
+	}
+	cfg.Status.RepresentorNetworks = statuses
+
+	if err := validateRepresentorNetworks(cfg); err != nil {
+		logger.Info("The representor networks are invalid", "error", err.Error())
+		meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().NotRepresentorNetworksValid().Reason(api.ReasonRepresentorConflict).Msg(err.Error()).Build())
+	} else if len(missing) > 0 {
+		msg := fmt.Sprintf("NetworkAttachmentDefinitions %s not found in the tenant cluster", strings.Join(missing, ", "))
+		meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().NotRepresentorNetworksValid().Reason(api.ReasonNetworkNotFound).Msg(msg).Build())
+	} else {
+		meta.SetStatusCondition(&cfg.Status.Conditions, *api.Conditions().RepresentorNetworksValid().Reason(api.ReasonValid).Build())
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	requeueBaseDelay = 5 * time.Second
+	requeueMaxDelay  = 5 * time.Minute
+)
+
+// reconcileErrors counts the failed reconciles, which are requeued with a
+// delay instead of returning the error and are thus not counted by
+// controller_runtime_reconcile_errors_total
+var reconcileErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "dpu_operator_reconcile_errors_total",
+		Help: "Number of failed reconciles of the operator, retried with a backoff or waiting for a change.",
+	},
+	[]string{"controller"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(reconcileErrors)
+}
+
+// requeuePolicy makes the retry decision of every reconcile explicit: an
+// error is retried with a per-request exponential backoff, a wait on
+// something that is not watched is polled, and an error that cannot be fixed
+// by retrying waits for the objects to change.
+type requeuePolicy struct {
+	log        logr.Logger
+	controller string
+	mu         sync.Mutex
+	failures   map[types.NamespacedName]int
+}
+
+func newRequeuePolicy(log logr.Logger, controller string) *requeuePolicy {
+	return &requeuePolicy{
+		log:        log,
+		controller: controller,
+		failures:   map[types.NamespacedName]int{},
+	}
+}
+
+// Retry requeues the request after a delay doubling with each consecutive
+// failure, from requeueBaseDelay up to requeueMaxDelay. The failure is
+// counted by dpu_operator_reconcile_errors_total.
+func (p *requeuePolicy) Retry(req ctrl.Request, err error) (ctrl.Result, error) {
+	delay, failures := p.backoff(req)
+	reconcileErrors.WithLabelValues(p.controller).Inc()
+	p.log.Error(err, "Reconcile failed, retrying", "request", req.NamespacedName, "failures", failures, "after", delay.String())
+	return ctrl.Result{RequeueAfter: delay}, nil
+}
+
+// Wait requeues the request with the backoff of Retry, on a state expected to
+// settle shortly after the creation of the objects, e.g. an object missing
+// from the cache. It is not logged as a failure.
+func (p *requeuePolicy) Wait(req ctrl.Request, reason string) (ctrl.Result, error) {
+	delay, attempts := p.backoff(req)
+	p.log.V(1).Info("Reconcile waiting, retrying", "request", req.NamespacedName, "reason", reason, "attempts", attempts, "after", delay.String())
+	return ctrl.Result{RequeueAfter: delay}, nil
+}
+
+// backoff returns the delay before the next attempt of the request, doubling
+// with each consecutive attempt, along with the number of attempts
+func (p *requeuePolicy) backoff(req ctrl.Request) (time.Duration, int) {
+	p.mu.Lock()
+	failures := p.failures[req.NamespacedName]
+	p.failures[req.NamespacedName] = failures + 1
+	p.mu.Unlock()
+
+	delay := requeueMaxDelay
+	if failures < 16 {
+		if d := requeueBaseDelay << failures; d < requeueMaxDelay {
+			delay = d
+		}
+	}
+	return delay, failures + 1
+}
+
+// Poll requeues the request after period, to check a state change that
+// triggers no event
+func (p *requeuePolicy) Poll(req ctrl.Request, period time.Duration) (ctrl.Result, error) {
+	p.forget(req)
+	return ctrl.Result{RequeueAfter: period}, nil
+}
+
+// Done ends the reconcile of the request, which is reconciled again on the
+// next event
+func (p *requeuePolicy) Done(req ctrl.Request) (ctrl.Result, error) {
+	p.forget(req)
+	return ctrl.Result{}, nil
+}
+
+// Terminal ends the reconcile of the request on an error that retrying cannot
+// fix, e.g. an invalid spec. It is reconciled again on the next event.
+func (p *requeuePolicy) Terminal(req ctrl.Request, err error) (ctrl.Result, error) {
+	reconcileErrors.WithLabelValues(p.controller).Inc()
+	p.log.Error(err, "Reconcile failed, waiting for a change", "request", req.NamespacedName)
+	return p.Done(req)
+}
+
+// Deleted ends the reconcile of a request whose object is gone. The failures
+// of the other requests of its namespace, e.g. mapped from the objects of a
+// deleted DpuClusterConfig, are forgotten along with it.
+func (p *requeuePolicy) Deleted(req ctrl.Request) (ctrl.Result, error) {
+	p.mu.Lock()
+	for name := range p.failures {
+		if name == req.NamespacedName || (req.Namespace != "" && name.Namespace == req.Namespace) {
+			delete(p.failures, name)
+		}
+	}
+	p.mu.Unlock()
+	return ctrl.Result{}, nil
+}
+
+func (p *requeuePolicy) forget(req ctrl.Request) {
+	p.mu.Lock()
+	delete(p.failures, req.NamespacedName)
+	p.mu.Unlock()
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func reconcileErrorCount(g *WithT, controller string) float64 {
+	m := &dto.Metric{}
+	g.Expect(reconcileErrors.WithLabelValues(controller).Write(m)).To(Succeed())
+	return m.GetCounter().GetValue()
+}
+
+func TestRequeuePolicyRetry(t *testing.T) {
+	g := NewWithT(t)
+	p := newRequeuePolicy(logr.Discard(), "test-retry")
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-a", Name: "config"}}
+
+	for _, delay := range []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second} {
+		result, err := p.Retry(req, errors.New("failed"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(delay))
+	}
+	g.Expect(reconcileErrorCount(g, "test-retry")).To(Equal(3.0))
+
+	// The backoff starts over once the reconcile succeeds
+	_, _ = p.Done(req)
+	result, _ := p.Retry(req, errors.New("failed"))
+	g.Expect(result.RequeueAfter).To(Equal(requeueBaseDelay))
+
+	// The waits are not counted as errors
+	_, _ = p.Wait(req, "waiting")
+	g.Expect(reconcileErrorCount(g, "test-retry")).To(Equal(4.0))
+
+	for i := 0; i < 20; i++ {
+		result, _ = p.Retry(req, errors.New("failed"))
+	}
+	g.Expect(result.RequeueAfter).To(Equal(requeueMaxDelay))
+}
+
+func TestRequeuePolicyDeleted(t *testing.T) {
+	g := NewWithT(t)
+	p := newRequeuePolicy(logr.Discard(), "test-deleted")
+	config := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-a", Name: "config"}}
+	mapped := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-a", Name: "tenant-kubeconfig"}}
+	other := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-b", Name: "config"}}
+	node := ctrl.Request{NamespacedName: types.NamespacedName{Name: "dpu-0"}}
+	for _, req := range []ctrl.Request{config, mapped, other, node} {
+		_, _ = p.Retry(req, errors.New("failed"))
+	}
+
+	_, _ = p.Deleted(config)
+	g.Expect(p.failures).NotTo(HaveKey(config.NamespacedName))
+	g.Expect(p.failures).NotTo(HaveKey(mapped.NamespacedName))
+	g.Expect(p.failures).To(HaveKey(other.NamespacedName))
+	g.Expect(p.failures).To(HaveKey(node.NamespacedName))
+
+	// A deleted cluster-scoped object only forgets its own request
+	_, _ = p.Deleted(node)
+	g.Expect(p.failures).To(HaveLen(1))
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	mcrender "github.com/k8snetworkplumbingwg/sriov-network-operator/pkg/render"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+var sriovNetworkNodeStateGVK = schema.GroupVersionKind{
+	Group:   "sriovnetwork.openshift.io",
+	Version: "v1",
+	Kind:    "SriovNetworkNodeState",
+}
+
+//+kubebuilder:rbac:groups=sriovnetwork.openshift.io,resources=sriovnetworknodestates,verbs=get;list;watch
+
+// machineConfigRenderData returns the data used to render the DPU host
+// configuration files
+func (r *DpuClusterConfigReconciler) machineConfigRenderData(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (mcrender.RenderData, error) {
+	devices := []string{}
+	if cfg.Spec.SriovIntegration == dpuv1alpha1.SriovIntegrationDefer {
+		var err error
+		devices, err = r.getSriovManagedDevices(ctx, cfg)
+		if err != nil {
+			return mcrender.MakeRenderData(), err
+		}
+	}
+	return makeMachineConfigRenderData(cfg, devices), nil
+}
+
+// makeMachineConfigRenderData returns the data used to render the DPU host
+// configuration files, devices being the NICs managed by the
+// sriov-network-operator
+func makeMachineConfigRenderData(cfg *dpuv1alpha1.DpuClusterConfig, devices []string) mcrender.RenderData {
+	data := mcrender.MakeRenderData()
+	mode := cfg.Spec.SriovIntegration
+	data.Data["SriovIntegration"] = mode != "" && mode != dpuv1alpha1.SriovIntegrationNone
+	data.Data["SriovManagedDevices"] = strings.Join(devices, " ")
+	data.Data["HostPFRepresentor"] = cfg.Spec.HostPFRepresentor
+	data.Data["OvsOtherConfig"] = ovsOtherConfig(cfg)
+	setHostConfigRenderData(cfg, &data)
+	setTimeSyncRenderData(cfg, &data)
+	setHostNetworkRenderData(cfg, &data)
+	return data
+}
+
+// getSriovManagedDevices returns the PCI addresses of the NICs that the
+// sriov-network-operator configures in switchdev mode on the DPU nodes,
+// according to the spec of their SriovNetworkNodeState.
+func (r *DpuClusterConfigReconciler) getSriovManagedDevices(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) ([]string, error) {
+	if !r.Platform.Serves(sriovNetworkNodeStateGVK) {
+		return []string{}, nil
+	}
+	nodes := &corev1.NodeList{}
+	if cfg.Spec.NodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(cfg.Spec.NodeSelector)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.List(ctx, nodes, &client.ListOptions{LabelSelector: selector}); err != nil {
+			return nil, err
+		}
+	}
+	dpuNodes := map[string]bool{}
+	for _, n := range nodes.Items {
+		dpuNodes[n.Name] = true
+	}
+
+	states := &unstructured.UnstructuredList{}
+	states.SetGroupVersionKind(sriovNetworkNodeStateGVK.GroupVersion().WithKind(sriovNetworkNodeStateGVK.Kind + "List"))
+	if err := r.List(ctx, states); err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	for _, state := range states.Items {
+		if !dpuNodes[state.GetName()] {
+			continue
+		}
+		interfaces, _, err := unstructured.NestedSlice(state.Object, "spec", "interfaces")
+		if err != nil {
+			logger.Error(err, "Invalid interfaces in SriovNetworkNodeState", "name", state.GetName())
+			continue
+		}
+		for _, i := range interfaces {
+			iface, ok := i.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			mode, _, _ := unstructured.NestedString(iface, "eSwitchMode")
+			pciAddress, _, _ := unstructured.NestedString(iface, "pciAddress")
+			if mode == "switchdev" && pciAddress != "" {
+				found[pciAddress] = true
+			}
+		}
+	}
+	devices := []string{}
+	for d := range found {
+		devices = append(devices, d)
+	}
+	sort.Strings(devices)
+	return devices, nil
+}
+
+// sriovIntegrationRequests maps a SriovNetworkNodeState event to the
+// DpuClusterConfigs deferring to the sriov-network-operator
+func (r *DpuClusterConfigReconciler) sriovIntegrationRequests(obj client.Object) []reconcile.Request {
+	cfgList := &dpuv1alpha1.DpuClusterConfigList{}
+	if err := r.List(context.TODO(), cfgList); err != nil {
+		logger.Error(err, "Fail to list DpuClusterConfigs")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, cfg := range cfgList.Items {
+		if cfg.Spec.SriovIntegration == dpuv1alpha1.SriovIntegrationDefer {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	mcrender "github.com/k8snetworkplumbingwg/sriov-network-operator/pkg/render"
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	"sigs.k8s.io/yaml"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// hostConfigFile is a file of the DPU host configuration, as described by
+// the ignition file entries in bindata/machine-config/files
+type hostConfigFile struct {
+	Key      string `json:"-"`
+	Path     string `json:"path"`
+	Mode     int    `json:"mode"`
+	Contents struct {
+		Inline string `json:"inline"`
+	} `json:"contents"`
+}
+
+// syncSwitchdevDaemonSet is the DPU host configuration backend used when the
+// infra cluster doesn't serve the MachineConfig API, e.g. MicroShift. The
+// files of the machine config are shipped in a ConfigMap and installed on the
+// DPU nodes by a privileged DaemonSet.
+func (r *DpuClusterConfigReconciler) syncSwitchdevDaemonSet(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	logger.Info("Start to sync the DPU host config daemonset")
+	mcData, err := r.machineConfigRenderData(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	files, err := machineConfigFiles(&mcData)
+	if err != nil {
+		return err
+	}
+	image, err := r.getOvnkubeImage()
+	if err != nil {
+		return err
+	}
+
+	data := render.MakeRenderData()
+	data.Data["Image"] = image
+	data.Data["Namespace"] = cfg.Namespace
+	data.Data["Files"] = files
+	data.Data["OvsOtherConfig"] = ovsOtherConfig(cfg)
+	data.Data["HostConfig"] = cfg.Spec.HostConfig != nil
+	data.Data["TimeSync"] = len(mcData.Data["NtpServers"].([]string)) > 0 || mcData.Data["PtpInterface"] != ""
+	data.Data["PtpInterface"] = mcData.Data["PtpInterface"]
+	data.Data["NetworkInterfaces"] = mcData.Data["NetworkInterfaces"]
+
+	objs, err := renderDir(ctx, utils.SwitchdevDaemonPath, &data)
+	if err != nil {
+		logger.Error(err, "Fail to render the DPU host config manifests")
+		return err
+	}
+	nodeSelector, err := r.getDpuNodeSelector(cfg)
+	if err != nil {
+		return err
+	}
+	return r.applyObjects(ctx, cfg, objs, nodeSelector)
+}
+
+// machineConfigFiles renders the files that the MachineConfig would write on
+// the DPU nodes, including the ones of the hostConfig
+func machineConfigFiles(d *mcrender.RenderData) ([]hostConfigFile, error) {
+	rendered, err := renderFileTemplates(filepath.Join(utils.MachineConfigPath, "files"), d)
+	if err != nil {
+		return nil, err
+	}
+	extra, err := hostConfigFiles(d)
+	if err != nil {
+		return nil, err
+	}
+	files := []hostConfigFile{}
+	for _, r := range append(rendered, extra...) {
+		f := hostConfigFile{}
+		if err = yaml.Unmarshal([]byte(r), &f); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", r, err)
+		}
+		f.Key = filepath.Base(f.Path)
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// renderFileTemplates renders the ignition file or unit templates of dir, skipping
+// the ones rendered to whitespace only
+func renderFileTemplates(dir string, d *mcrender.RenderData) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, p := range paths {
+		rendered, err := renderFileTemplate(p, d.Data)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(rendered) == "" {
+			continue
+		}
+		files = append(files, rendered)
+	}
+	return files, nil
+}
+
+// renderFileTemplate renders the template file p with data
+func renderFileTemplate(p string, data interface{}) (string, error) {
+	tmpl := template.New(p).Option("missingkey=error").Funcs(sprig.TxtFuncMap())
+	source, err := os.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmpl.Parse(string(source)); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %v", p, err)
+	}
+	rendered := bytes.Buffer{}
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %v", p, err)
+	}
+	return rendered.String(), nil
+}
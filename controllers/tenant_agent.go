@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// syncTenantAgent deploys the agent publishing the DPU serial numbers on the
+// workers of the tenant cluster when it is enabled in the spec, and removes
+// it otherwise.
+func (r *DpuClusterConfigReconciler) syncTenantAgent(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	tenantClient, err := r.auditedTenantClient(cfg, "sync the tenant agent")
+	if err != nil {
+		return err
+	}
+
+	if cfg.Spec.TenantAgent == nil {
+		objMeta := metav1.ObjectMeta{Name: utils.TenantAgentDsName, Namespace: tenantNamespace(cfg)}
+		clusterMeta := metav1.ObjectMeta{Name: utils.TenantAgentDsName}
+		return deleteTenantObjects(tenantClient,
+			&appsv1.DaemonSet{ObjectMeta: objMeta},
+			&corev1.ServiceAccount{ObjectMeta: objMeta},
+			&rbacv1.ClusterRole{ObjectMeta: clusterMeta},
+			&rbacv1.ClusterRoleBinding{ObjectMeta: clusterMeta},
+		)
+	}
+
+	logger.Info("Start to sync the tenant agent daemonset")
+	tenantPlatform, err := utils.DetectPlatform(r.TenantConfigs.Get(cfg.Namespace))
+	if err != nil {
+		return err
+	}
+	data := render.MakeRenderData()
+	data.Data["Namespace"] = tenantNamespace(cfg)
+	data.Data["Image"] = cfg.Spec.TenantAgent.Image
+	data.Data["HasSecurityContextConstraints"] = tenantPlatform.HasSecurityContextConstraints()
+	data.Data["SimulateHardware"] = utils.SimulateHardware
+	return applyTenantManifests(ctx, tenantClient, cfg, utils.TenantAgentPath, &data, nil)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+const (
+	// tenantHeartbeatPeriod is how often the API server of the tenant
+	// cluster is probed
+	tenantHeartbeatPeriod = 30 * time.Second
+	// tenantHeartbeatTimeout bounds each probe
+	tenantHeartbeatTimeout = 10 * time.Second
+	// tenantUnreachableThreshold is the number of heartbeats missed in a row
+	// after which the tenant cluster is reported as unreachable
+	tenantUnreachableThreshold = 3
+)
+
+var tenantReachable = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "dpu_operator_tenant_reachable",
+		Help: "Whether the API server of the tenant cluster answers the heartbeat of the operator, 1 if it does.",
+	},
+	[]string{"namespace"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(tenantReachable)
+}
+
+// runTenantHeartbeat probes the readyz endpoint of the tenant API server
+// every tenantHeartbeatPeriod until stopCh is closed. The reachability of the
+// tenant cluster is recorded in the TenantConfigs, so that the other
+// controllers skip the tenant cluster while it is unreachable, and the
+// DpuClusterConfig is reconciled whenever it changes.
+func (r *DpuClusterConfigReconciler) runTenantHeartbeat(cfg *dpuv1alpha1.DpuClusterConfig, restConfig *restclient.Config, stopCh <-chan struct{}) error {
+	clientset, err := tenantProbeClient(restConfig)
+	if err != nil {
+		return err
+	}
+	failures := 0
+	for {
+		err := probeTenantAPI(context.Background(), clientset)
+		wasUnreachable := r.TenantConfigs.Unreachable(cfg.Namespace) != nil
+		if err == nil {
+			failures = 0
+			r.TenantConfigs.SetUnreachable(cfg.Namespace, nil)
+			tenantReachable.WithLabelValues(cfg.Namespace).Set(1)
+			if wasUnreachable {
+				logger.Info("The tenant cluster is reachable again", "namespace", cfg.Namespace)
+				r.notifyTenantChange(cfg)
+			}
+		} else if failures++; failures >= tenantUnreachableThreshold {
+			r.TenantConfigs.SetUnreachable(cfg.Namespace, fmt.Errorf("%d heartbeats missed, last at %s: %v",
+				failures, time.Now().UTC().Format(time.RFC3339), err))
+			tenantReachable.WithLabelValues(cfg.Namespace).Set(0)
+			if !wasUnreachable {
+				logger.Info("The tenant cluster is unreachable", "namespace", cfg.Namespace, "error", err.Error())
+				r.notifyTenantChange(cfg)
+			}
+		}
+
+		select {
+		case <-stopCh:
+			return nil
+		case <-time.After(tenantHeartbeatPeriod):
+		}
+	}
+}
+
+// tenantProbeClient returns a client probing the API server of the tenant
+// cluster of restConfig, each probe being bounded by tenantHeartbeatTimeout
+func tenantProbeClient(restConfig *restclient.Config) (kubernetes.Interface, error) {
+	restConfig = restclient.CopyConfig(restConfig)
+	restConfig.Timeout = tenantHeartbeatTimeout
+	// The probes are not delayed by the rate limit of the other clients,
+	// which would make a busy tenant cluster look unreachable
+	restConfig.RateLimiter = nil
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// probeTenantAPI returns an error unless the readyz endpoint of the tenant
+// API server answers
+func probeTenantAPI(ctx context.Context, clientset kubernetes.Interface) error {
+	return clientset.Discovery().RESTClient().Get().AbsPath("/readyz").Do(ctx).Error()
+}
+
+// notifyTenantChange reconciles the DpuClusterConfig, e.g. to update its
+// TenantReachable condition. The notification is dropped when a reconcile is
+// already pending.
+func (r *DpuClusterConfigReconciler) notifyTenantChange(cfg *dpuv1alpha1.DpuClusterConfig) {
+	obj := &dpuv1alpha1.DpuClusterConfig{ObjectMeta: metav1.ObjectMeta{Namespace: cfg.Namespace, Name: cfg.Name}}
+	select {
+	case r.tenantEvents <- event.GenericEvent{Object: obj}:
+	default:
+	}
+}
+
+// deleteTenantHeartbeatMetrics drops the metric of the tenant cluster of the
+// namespace once its DpuClusterConfig is deleted
+func deleteTenantHeartbeatMetrics(namespace string) {
+	tenantReachable.DeleteLabelValues(namespace)
+}
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/openshift/dpu-network-operator/api"
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// tenantKubeconfigError is a failure of the tenant kubeconfig, along with the
+// reason of the TenantKubeconfigValid condition reporting it
+type tenantKubeconfigError struct {
+	reason string
+	err    error
+}
+
+func (e *tenantKubeconfigError) Error() string {
+	return e.err.Error()
+}
+
+func (e *tenantKubeconfigError) Unwrap() error {
+	return e.err
+}
+
+// getTenantKubeconfig returns the tenant cluster kubeconfig, read either from
+// the file at spec.kubeConfigPath or from the secret spec.kubeConfigFile,
+// along with a version identifying its content. When spec.kubeConfigContext
+// is set, the kubeconfig is narrowed to that context.
+func (r *DpuClusterConfigReconciler) getTenantKubeconfig(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) ([]byte, string, error) {
+	var bytes []byte
+	var version string
+	if cfg.Spec.KubeConfigPath != "" {
+		var err error
+		if bytes, err = os.ReadFile(cfg.Spec.KubeConfigPath); err != nil {
+			if os.IsNotExist(err) {
+				return nil, "", &tenantKubeconfigError{reason: api.ReasonKubeconfigNotFound, err: err}
+			}
+			return nil, "", err
+		}
+		version = fmt.Sprintf("%x", sha256.Sum256(bytes))
+	} else {
+		s := &corev1.Secret{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: cfg.Spec.KubeConfigFile, Namespace: cfg.Namespace}, s); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, "", &tenantKubeconfigError{reason: api.ReasonKubeconfigNotFound, err: err}
+			}
+			return nil, "", err
+		}
+		var err error
+		if _, bytes, err = utils.GetSecretKubeconfig(s, tenantKubeconfigKey(cfg)); err != nil {
+			return nil, "", &tenantKubeconfigError{reason: api.ReasonKubeconfigKeyMissing, err: err}
+		}
+		version = s.ResourceVersion
+	}
+
+	if cfg.Spec.KubeConfigContext == "" {
+		return bytes, version, nil
+	}
+	bytes, err := withContext(bytes, cfg.Spec.KubeConfigContext)
+	if err != nil {
+		return nil, "", &tenantKubeconfigError{reason: api.ReasonKubeconfigInvalid, err: err}
+	}
+	// Switching to another context restarts the tenant syncer as well
+	return bytes, version + "/" + cfg.Spec.KubeConfigContext, nil
+}
+
+// checkTenantKubeconfig checks that the tenant kubeconfig of cfg is found,
+// parses and reaches the API server of the tenant cluster, before the tenant
+// syncer is started with it. The returned error is a tenantKubeconfigError
+// when the check fails.
+func (r *DpuClusterConfigReconciler) checkTenantKubeconfig(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) error {
+	bytes, _, err := r.getTenantKubeconfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(bytes)
+	if err != nil {
+		return &tenantKubeconfigError{reason: api.ReasonKubeconfigInvalid, err: err}
+	}
+	clientset, err := tenantProbeClient(restConfig)
+	if err != nil {
+		return &tenantKubeconfigError{reason: api.ReasonKubeconfigInvalid, err: err}
+	}
+	if err := probeTenantAPI(ctx, clientset); err != nil {
+		return &tenantKubeconfigError{reason: api.ReasonTenantUnreachable, err: fmt.Errorf("failed to reach the tenant cluster: %v", err)}
+	}
+	return nil
+}
+
+// withContext returns the kubeconfig with the given context as its
+// current-context, the other contexts and their clusters and users being
+// dropped, so that the ovnkube-node pods mounting it use the same context
+func withContext(kubeconfig []byte, contextName string) ([]byte, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := cfg.Contexts[contextName]; !ok {
+		contexts := []string{}
+		for name := range cfg.Contexts {
+			contexts = append(contexts, name)
+		}
+		sort.Strings(contexts)
+		return nil, fmt.Errorf("context %q not found in the tenant kubeconfig, its contexts are %v", contextName, contexts)
+	}
+	cfg.CurrentContext = contextName
+	if err := clientcmdapi.MinifyConfig(cfg); err != nil {
+		return nil, err
+	}
+	return clientcmd.Write(*cfg)
+}
+
+// getTenantKubeconfigKey returns the key of the tenant kubeconfig in the
+// secret mounted by the ovnkube-node pods
+func (r *DpuClusterConfigReconciler) getTenantKubeconfigKey(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig) (string, error) {
+	if rendersTenantKubeconfigSecret(cfg) {
+		return utils.KubeconfigKey, nil
+	}
+	if cfg.Spec.KubeConfigFile == "" {
+		return tenantKubeconfigKey(cfg), nil
+	}
+	s := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: cfg.Spec.KubeConfigFile, Namespace: cfg.Namespace}, s); err != nil {
+		return "", err
+	}
+	key, _, err := utils.GetSecretKubeconfig(s, tenantKubeconfigKey(cfg))
+	return key, err
+}
+
+// syncTenantKubeconfigSecret copies the kubeconfig file into a secret, which
+// is mounted by the ovnkube-node pods. Nothing is done when the kubeconfig is
+// provided as a secret already and its token is not renewed.
+func (r *DpuClusterConfigReconciler) syncTenantKubeconfigSecret(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, kubeconfig []byte) error {
+	if !rendersTenantKubeconfigSecret(cfg) {
+		return nil
+	}
+	s := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.SecretNameTenantKubeconfig,
+			Namespace: cfg.Namespace,
+		},
+		Data: map[string][]byte{utils.KubeconfigKey: kubeconfig},
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(s)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	if err := ctrl.SetControllerReference(cfg, obj, r.Scheme); err != nil {
+		return err
+	}
+	setCommonMetadata(cfg, obj)
+	return applyObject(ctx, r.Client, obj)
+}
+
+// tenantKubeconfigSecretName returns the name of the secret holding the
+// tenant kubeconfig
+func tenantKubeconfigSecretName(cfg *dpuv1alpha1.DpuClusterConfig) string {
+	if rendersTenantKubeconfigSecret(cfg) {
+		return utils.SecretNameTenantKubeconfig
+	}
+	return cfg.Spec.KubeConfigFile
+}
+
+// rendersTenantKubeconfigSecret returns true if the operator writes the
+// tenant kubeconfig secret mounted by the ovnkube-node pods, either because
+// the kubeconfig is a file, because it is narrowed to a context or because
+// its token is renewed
+func rendersTenantKubeconfigSecret(cfg *dpuv1alpha1.DpuClusterConfig) bool {
+	return cfg.Spec.KubeConfigPath != "" || cfg.Spec.KubeConfigContext != "" || cfg.Spec.TokenRenewal != nil
+}
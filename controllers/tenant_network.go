@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+const (
+	// tenantNetworkName is the name of the network operator config of an
+	// OpenShift cluster
+	tenantNetworkName = "cluster"
+
+	gatewayModeShared = "shared"
+	gatewayModeLocal  = "local"
+)
+
+// tenantNetworkGVR is the network operator config of the tenant cluster
+var tenantNetworkGVR = schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "networks"}
+
+// tenantNetworkSettings are the settings of the network operator config of
+// the tenant cluster the ovnkube pods of the DPUs have to agree with
+type tenantNetworkSettings struct {
+	// RoutingViaHost runs the gateway of ovnkube in local mode
+	RoutingViaHost bool
+	// IPsec is set when the tenant cluster encrypts the pod traffic
+	IPsec bool
+	// ClusterSubnets is the cluster network of the tenant cluster, as
+	// comma-separated cidr/hostPrefix entries
+	ClusterSubnets string
+	// ServiceCidrs is the comma-separated service network of the tenant
+	// cluster
+	ServiceCidrs string
+	// MTU is the MTU of the pod network, 0 when it is detected by the
+	// network operator of the tenant cluster
+	MTU uint32
+}
+
+// tenantNetwork caches the settings of the network operator config watched in
+// a tenant cluster
+type tenantNetwork struct {
+	mu sync.Mutex
+	// settings is nil until the config is found, e.g. for a tenant cluster
+	// without network operator
+	settings *tenantNetworkSettings
+}
+
+// get returns the cached settings, nil when unknown
+func (n *tenantNetwork) get() *tenantNetworkSettings {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.settings
+}
+
+// set caches settings and returns true when they changed
+func (n *tenantNetwork) set(settings *tenantNetworkSettings) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	changed := (n.settings == nil) != (settings == nil) || (settings != nil && *n.settings != *settings)
+	n.settings = settings
+	return changed
+}
+
+// getTenantNetworkSettings returns the network settings of the tenant cluster
+// of cfg, nil while they are unknown
+func (r *DpuClusterConfigReconciler) getTenantNetworkSettings(cfg *dpuv1alpha1.DpuClusterConfig) *tenantNetworkSettings {
+	ts, ok := r.syncers[cfg.Namespace]
+	if !ok || ts.network == nil {
+		return nil
+	}
+	return ts.network.get()
+}
+
+// parseTenantNetwork extracts the settings relevant to the DPUs from the
+// network operator config
+func parseTenantNetwork(obj *unstructured.Unstructured) (*tenantNetworkSettings, error) {
+	network := &operatorv1.Network{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, network); err != nil {
+		return nil, err
+	}
+	subnets := make([]string, 0, len(network.Spec.ClusterNetwork))
+	for _, entry := range network.Spec.ClusterNetwork {
+		if entry.HostPrefix > 0 {
+			subnets = append(subnets, fmt.Sprintf("%s/%d", entry.CIDR, entry.HostPrefix))
+		} else {
+			subnets = append(subnets, entry.CIDR)
+		}
+	}
+	settings := &tenantNetworkSettings{
+		ClusterSubnets: strings.Join(subnets, ","),
+		ServiceCidrs:   strings.Join(network.Spec.ServiceNetwork, ","),
+	}
+	if ovn := network.Spec.DefaultNetwork.OVNKubernetesConfig; ovn != nil {
+		settings.IPsec = ovn.IPsecConfig != nil
+		settings.RoutingViaHost = ovn.GatewayConfig != nil && ovn.GatewayConfig.RoutingViaHost
+		if ovn.MTU != nil {
+			settings.MTU = *ovn.MTU
+		}
+	}
+	return settings, nil
+}
+
+// runTenantNetworkWatch watches the network operator config of the tenant
+// cluster of cfg until stopCh is closed, the DpuClusterConfig being reconciled
+// whenever the settings of the DPUs change. The watch stops at once when the
+// tenant cluster has no such config, e.g. when it is not an OpenShift cluster.
+func (r *DpuClusterConfigReconciler) runTenantNetworkWatch(cfg *dpuv1alpha1.DpuClusterConfig, n *tenantNetwork, stopCh <-chan struct{}) error {
+	clients, err := r.TenantConfigs.Clients(cfg.Namespace)
+	if err != nil || clients == nil {
+		return err
+	}
+	resource := clients.Dynamic.Resource(tenantNetworkGVR)
+	if _, err := resource.Get(context.Background(), tenantNetworkName, metav1.GetOptions{}); meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+		logger.Info("The tenant cluster has no network operator config, it is not watched", "namespace", cfg.Namespace)
+		return nil
+	}
+
+	selector := fields.OneTermEqualSelector("metadata.name", tenantNetworkName).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return resource.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return resource.Watch(context.Background(), options)
+		},
+	}
+	update := func(obj interface{}) {
+		var settings *tenantNetworkSettings
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			var err error
+			if settings, err = parseTenantNetwork(u); err != nil {
+				logger.Error(err, "Fail to parse the network operator config of the tenant cluster", "namespace", cfg.Namespace)
+				return
+			}
+		}
+		if n.set(settings) {
+			logger.Info("The network operator config of the tenant cluster changed", "namespace", cfg.Namespace, "settings", settings)
+			r.notifyTenantChange(cfg)
+		}
+	}
+	_, informer := cache.NewInformer(lw, &unstructured.Unstructured{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc:    update,
+		UpdateFunc: func(_, obj interface{}) { update(obj) },
+		DeleteFunc: func(interface{}) { update(nil) },
+	})
+	informer.Run(stopCh)
+	return nil
+}
+
+// ipsecEnabled returns true when the OVN IPsec daemons run on the DPU nodes,
+// either set in the spec or enabled in the tenant cluster
+func ipsecEnabled(cfg *dpuv1alpha1.DpuClusterConfig, tenant *tenantNetworkSettings) bool {
+	return cfg.Spec.IPsec || tenant != nil && tenant.IPsec
+}
+
+// setTenantNetworkRenderData sets the render data following the network
+// settings of the tenant cluster, which are nil when unknown
+func setTenantNetworkRenderData(cfg *dpuv1alpha1.DpuClusterConfig, tenant *tenantNetworkSettings, data *render.RenderData) {
+	data.Data["IPsec"] = ipsecEnabled(cfg, tenant)
+	data.Data["GatewayMode"] = gatewayModeShared
+	data.Data["TenantClusterSubnets"], data.Data["TenantServiceCidrs"], data.Data["TenantMtu"] = "", "", ""
+	if tenant == nil {
+		return
+	}
+	if tenant.RoutingViaHost {
+		data.Data["GatewayMode"] = gatewayModeLocal
+	}
+	data.Data["TenantClusterSubnets"] = tenant.ClusterSubnets
+	data.Data["TenantServiceCidrs"] = tenant.ServiceCidrs
+	if tenant.MTU > 0 {
+		data.Data["TenantMtu"] = strconv.FormatUint(uint64(tenant.MTU), 10)
+	}
+}
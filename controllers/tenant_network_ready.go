@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+const (
+	// dpuNetworkReadyCondition is the condition of the tenant nodes telling
+	// whether the network of their DPU is functional
+	dpuNetworkReadyCondition corev1.NodeConditionType = "DpuNetworkReady"
+)
+
+// dpuNetworkCondition returns the DpuNetworkReady condition of the tenant
+// node of the DPU node. The network is ready once the DPU node is Ready, its
+// ovnkube-node pod is Ready, and its ovn-controller is not reported
+// disconnected from the southbound database.
+func (r *DpuNodeLifecycleController) dpuNetworkCondition(ctx context.Context, cfg *dpuv1alpha1.DpuClusterConfig, node *corev1.Node) (corev1.NodeCondition, error) {
+	condition := corev1.NodeCondition{Type: dpuNetworkReadyCondition, Status: corev1.ConditionFalse}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady && c.Status != corev1.ConditionTrue {
+			condition.Reason = "DpuNodeNotReady"
+			condition.Message = fmt.Sprintf("DPU node %s is not Ready", node.Name)
+			return condition, nil
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if cfg != nil {
+		if err := r.List(ctx, pods, client.InNamespace(cfg.Namespace), client.MatchingLabels{"app": utils.OvnkubeNodeDsName}); err != nil {
+			return condition, err
+		}
+	}
+	var ovnkubePod *corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].Spec.NodeName == node.Name && pods.Items[i].DeletionTimestamp == nil {
+			ovnkubePod = &pods.Items[i]
+		}
+	}
+	if ovnkubePod == nil {
+		condition.Reason = "OvnkubeNodeNotRunning"
+		condition.Message = fmt.Sprintf("no ovnkube-node pod runs on DPU node %s", node.Name)
+		return condition, nil
+	}
+	for _, c := range ovnkubePod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status != corev1.ConditionTrue {
+			condition.Reason = "OvnkubeNodeNotReady"
+			condition.Message = fmt.Sprintf("pod %s on DPU node %s is not Ready", ovnkubePod.Name, node.Name)
+			return condition, nil
+		}
+	}
+
+	for _, c := range node.Status.Conditions {
+		if c.Type == ovnSouthboundConnectedCondition && c.Status == corev1.ConditionFalse {
+			condition.Reason = "SouthboundDisconnected"
+			condition.Message = fmt.Sprintf("ovn-controller of DPU node %s is not connected to the southbound database: %s", node.Name, c.Message)
+			return condition, nil
+		}
+	}
+	condition.Status = corev1.ConditionTrue
+	condition.Reason = "OvnkubeNodeReady"
+	condition.Message = fmt.Sprintf("the network of DPU node %s is ready", node.Name)
+	return condition, nil
+}
+
+// syncTenantNetworkReady sets the DpuNetworkReady condition of the tenant
+// node, and with taintTenantNodes keeps the NoSchedule taint on it until the
+// condition is True
+func (r *DpuNodeLifecycleController) syncTenantNetworkReady(ctx context.Context, log logr.Logger, cfg *dpuv1alpha1.DpuClusterConfig, node *corev1.Node, tenantClient client.Client, tenantNode string) error {
+	condition, err := r.dpuNetworkCondition(ctx, cfg, node)
+	if err != nil {
+		return err
+	}
+	tnode := &corev1.Node{}
+	if err := tenantClient.Get(ctx, types.NamespacedName{Name: tenantNode}, tnode); err != nil {
+		return err
+	}
+	changed, err := patchNodeCondition(ctx, tenantClient, tnode, condition)
+	if err != nil {
+		return err
+	}
+	if changed {
+		log.Info("The DPU network readiness of the tenant node changed", "tenantNode", tenantNode, "status", condition.Status, "reason", condition.Reason)
+	}
+
+	tainted := -1
+	for i, t := range tnode.Spec.Taints {
+		if t.Key == utils.TenantNodeNetworkUnavailableTaint {
+			tainted = i
+		}
+	}
+	shouldTaint := cfg != nil && cfg.Spec.TaintTenantNodes && condition.Status != corev1.ConditionTrue
+	if shouldTaint == (tainted >= 0) {
+		return nil
+	}
+	patch := client.MergeFrom(tnode.DeepCopy())
+	if shouldTaint {
+		log.Info("Taint the tenant node until the network of its DPU is ready", "tenantNode", tenantNode)
+		tnode.Spec.Taints = append(tnode.Spec.Taints, corev1.Taint{
+			Key:    utils.TenantNodeNetworkUnavailableTaint,
+			Effect: corev1.TaintEffectNoSchedule,
+		})
+	} else {
+		log.Info("Remove the DPU network taint of the tenant node", "tenantNode", tenantNode)
+		tnode.Spec.Taints = append(tnode.Spec.Taints[:tainted], tnode.Spec.Taints[tainted+1:]...)
+	}
+	return tenantClient.Patch(ctx, tnode, patch)
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+const (
+	// ovnHostAddressesAnnotation lists the addresses of the host of a node,
+	// as a JSON list of IPs
+	ovnHostAddressesAnnotation = "k8s.ovn.org/host-addresses"
+	// ovnManagementPortAnnotation is the PF and VF of the DPU backing the
+	// management port of a dpu-host node, read by ovnkube on the DPU
+	ovnManagementPortAnnotation = "k8s.ovn.org/node-mgmt-port"
+
+	// tenantNodeAnnotationsResyncPeriod is how often the annotations of the
+	// tenant nodes are healed, the tenant nodes not being watched
+	tenantNodeAnnotationsResyncPeriod = 10 * time.Minute
+)
+
+// ovnManagementPort is the value of the k8s.ovn.org/node-mgmt-port annotation
+type ovnManagementPort struct {
+	PfID   int `json:"PfId"`
+	FuncID int `json:"FuncId"`
+}
+
+// syncTenantNodeAnnotations heals the ovn-kubernetes annotations of the
+// tenant node needed by ovnkube in DPU mode: the InternalIP addresses of the
+// tenant node are added back to its host addresses, and the management port
+// and other annotations of the dpuHost of cfg are reset to their configured
+// values. It returns whether the annotations are managed, i.e. need to be
+// resynced.
+func (r *DpuNodeLifecycleController) syncTenantNodeAnnotations(ctx context.Context, log logr.Logger, cfg *dpuv1alpha1.DpuClusterConfig, tenantClient client.Client, tenantNode string) (bool, error) {
+	if cfg == nil || cfg.Spec.DpuHost == nil {
+		return false, nil
+	}
+	spec := cfg.Spec.DpuHost
+	node := &corev1.Node{}
+	if err := tenantClient.Get(ctx, types.NamespacedName{Name: tenantNode}, node); err != nil {
+		return true, err
+	}
+
+	annotations := map[string]string{}
+	for k, v := range spec.NodeAnnotations {
+		annotations[k] = v
+	}
+	if spec.ManagementPort != nil {
+		value, err := json.Marshal(ovnManagementPort{PfID: int(spec.ManagementPort.PfID), FuncID: int(spec.ManagementPort.FuncID)})
+		if err != nil {
+			return true, err
+		}
+		annotations[ovnManagementPortAnnotation] = string(value)
+	}
+	hostAddresses, err := healHostAddresses(node)
+	if err != nil {
+		log.Info("The host addresses of the tenant node are not a JSON list, reset them", "tenantNode", tenantNode, "error", err)
+	}
+	if hostAddresses != "" {
+		annotations[ovnHostAddressesAnnotation] = hostAddresses
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	changed := []string{}
+	for k, v := range annotations {
+		if current, ok := node.Annotations[k]; ok && current == v {
+			continue
+		}
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[k] = v
+		changed = append(changed, k)
+	}
+	if len(changed) == 0 {
+		return true, nil
+	}
+	log.Info("Heal the ovn-kubernetes annotations of the tenant node", "tenantNode", tenantNode, "annotations", changed)
+	return true, tenantClient.Patch(ctx, node, patch)
+}
+
+// healHostAddresses returns the host addresses annotation of the node with
+// its missing InternalIP addresses added, empty if it needs no change. The
+// other addresses set by ovnkube are kept, unless the annotation is invalid.
+func healHostAddresses(node *corev1.Node) (string, error) {
+	addresses := []string{}
+	var err error
+	if value, ok := node.Annotations[ovnHostAddressesAnnotation]; ok {
+		if err = json.Unmarshal([]byte(value), &addresses); err != nil {
+			addresses = []string{}
+		}
+	}
+	known := map[string]bool{}
+	for _, address := range addresses {
+		known[address] = true
+	}
+	missing := false
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP && !known[address.Address] {
+			addresses = append(addresses, address.Address)
+			known[address.Address] = true
+			missing = true
+		}
+	}
+	if !missing && err == nil {
+		return "", nil
+	}
+	value, merr := json.Marshal(addresses)
+	if merr != nil {
+		return "", merr
+	}
+	return string(value), err
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// syncTenantNodeLabels labels the tenant node as backed by a DPU, along with
+// the serial number of the DPU published by the tenant agent, if any, when
+// cfg asks for it
+func (r *DpuNodeLifecycleController) syncTenantNodeLabels(ctx context.Context, log logr.Logger, cfg *dpuv1alpha1.DpuClusterConfig, tenantClient client.Client, tenantNode string) error {
+	if cfg == nil || !cfg.Spec.LabelTenantNodes {
+		return nil
+	}
+	node := &corev1.Node{}
+	if err := tenantClient.Get(ctx, types.NamespacedName{Name: tenantNode}, node); err != nil {
+		return err
+	}
+	labels := map[string]string{utils.TenantNodeDpuLabel: "true"}
+	if serial := node.Annotations[utils.DpuSerialAnnotation]; serial != "" {
+		if errs := validation.IsValidLabelValue(serial); len(errs) > 0 {
+			log.Info("The DPU serial number is not a valid label value, skip it", "tenantNode", tenantNode, "serial", serial, "errors", errs)
+		} else {
+			labels[utils.TenantNodeDpuSerialLabel] = serial
+		}
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	changed := false
+	for k, v := range labels {
+		if node.Labels[k] != v {
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	log.Info("Label the tenant node as backed by a DPU", "tenantNode", tenantNode, "labels", labels)
+	return tenantClient.Patch(ctx, node, patch)
+}
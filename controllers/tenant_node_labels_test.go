@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+func TestSyncTenantNodeLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    *dpuv1alpha1.DpuClusterConfig
+		serial string
+		labels map[string]string
+	}{
+		{name: "no DpuClusterConfig"},
+		{name: "not enabled", cfg: &dpuv1alpha1.DpuClusterConfig{}},
+		{
+			name:   "enabled",
+			cfg:    &dpuv1alpha1.DpuClusterConfig{Spec: dpuv1alpha1.DpuClusterConfigSpec{LabelTenantNodes: true}},
+			labels: map[string]string{utils.TenantNodeDpuLabel: "true"},
+		},
+		{
+			name:   "serial number",
+			cfg:    &dpuv1alpha1.DpuClusterConfig{Spec: dpuv1alpha1.DpuClusterConfigSpec{LabelTenantNodes: true}},
+			serial: "MT2232X00001",
+			labels: map[string]string{utils.TenantNodeDpuLabel: "true", utils.TenantNodeDpuSerialLabel: "MT2232X00001"},
+		},
+		{
+			name:   "invalid serial number",
+			cfg:    &dpuv1alpha1.DpuClusterConfig{Spec: dpuv1alpha1.DpuClusterConfigSpec{LabelTenantNodes: true}},
+			serial: "MT2232 X00001",
+			labels: map[string]string{utils.TenantNodeDpuLabel: "true"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			tenantNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-0"}}
+			if tt.serial != "" {
+				tenantNode.Annotations = map[string]string{utils.DpuSerialAnnotation: tt.serial}
+			}
+			tenantClient := newFakeClient(tenantNode)
+			r := &DpuNodeLifecycleController{}
+
+			g.Expect(r.syncTenantNodeLabels(ctx, logr.Discard(), tt.cfg, tenantClient, "worker-0")).To(Succeed())
+
+			g.Expect(tenantClient.Get(ctx, client.ObjectKey{Name: "worker-0"}, tenantNode)).To(Succeed())
+			if tt.labels == nil {
+				g.Expect(tenantNode.Labels).To(BeEmpty())
+			} else {
+				g.Expect(tenantNode.Labels).To(Equal(tt.labels))
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/audit"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// auditedTenantClient returns a client of the tenant cluster of cfg
+// recording its writes for the given reason
+func (r *DpuClusterConfigReconciler) auditedTenantClient(cfg *dpuv1alpha1.DpuClusterConfig, reason string) (client.Client, error) {
+	c, err := r.tenantClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return audit.NewTenantClient(c, reason, cfg, r.Recorder), nil
+}
+
+// applyTenantManifests renders the manifests of dir and applies them to the
+// tenant cluster. The objects live in the tenant cluster, so they cannot be
+// owned by the DpuClusterConfig, they only carry its common metadata. mutate,
+// when not nil, is called on each object before it is applied.
+func applyTenantManifests(ctx context.Context, tenantClient client.Client, cfg *dpuv1alpha1.DpuClusterConfig, dir string, data *render.RenderData, mutate func(*unstructured.Unstructured) error) error {
+	objs, err := renderDir(ctx, dir, data)
+	if err != nil {
+		logger.Error(err, "Fail to render the tenant manifests", "dir", dir)
+		return err
+	}
+	for _, obj := range objs {
+		setCommonMetadata(cfg, obj)
+		if mutate != nil {
+			if err := mutate(obj); err != nil {
+				return err
+			}
+		}
+		if err := applyObject(ctx, tenantClient, obj); err != nil {
+			return fmt.Errorf("failed to apply object %v with err: %v", obj, err)
+		}
+	}
+	return nil
+}
+
+// deleteTenantObjects deletes objs from the tenant cluster, the ones already
+// gone are ignored
+func deleteTenantObjects(tenantClient client.Client, objs ...client.Object) error {
+	for _, obj := range objs {
+		if err := utils.DeleteObject(tenantClient, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+// setTenantRateLimit sets the tenantRateLimit of cfg on the REST config of
+// the tenant cluster. The rate limiter is shared by all the clients built
+// from the REST config, the tenant syncer included, so that the limit is a
+// budget of the operator rather than one of each client.
+func setTenantRateLimit(cfg *dpuv1alpha1.DpuClusterConfig, restConfig *restclient.Config) {
+	spec := cfg.Spec.TenantRateLimit
+	if spec == nil {
+		return
+	}
+	restConfig.QPS = float32(spec.QPS)
+	restConfig.Burst = int(spec.Burst)
+	restConfig.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(restConfig.QPS, restConfig.Burst)
+}
+
+// tenantRateLimit returns the tenantRateLimit of cfg, the zero value when it
+// is unset
+func tenantRateLimit(cfg *dpuv1alpha1.DpuClusterConfig) dpuv1alpha1.TenantRateLimitSpec {
+	if cfg.Spec.TenantRateLimit == nil {
+		return dpuv1alpha1.TenantRateLimitSpec{}
+	}
+	return *cfg.Spec.TenantRateLimit
+}
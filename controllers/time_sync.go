@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	mcrender "github.com/k8snetworkplumbingwg/sriov-network-operator/pkg/render"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+// setTimeSyncRenderData adds the chrony and PTP settings of the timeSync of
+// cfg to the machine config render data. With a PTP interface, ptp4l
+// synchronizes the hardware clock of the NIC and phc2sys exposes it to chrony
+// as a shared memory reference clock, so that chrony keeps disciplining the
+// system clock and falls back to the NTP servers.
+func setTimeSyncRenderData(cfg *dpuv1alpha1.DpuClusterConfig, data *mcrender.RenderData) {
+	servers, ptpInterface, ptpDomain := []string{}, "", int32(0)
+	if spec := cfg.Spec.TimeSync; spec != nil {
+		servers = append(servers, spec.NTPServers...)
+		ptpInterface = spec.PTPInterface
+		if spec.PTPDomain != nil {
+			ptpDomain = *spec.PTPDomain
+		}
+	}
+	data.Data["NtpServers"] = servers
+	data.Data["PtpInterface"] = ptpInterface
+	data.Data["PtpDomain"] = ptpDomain
+}
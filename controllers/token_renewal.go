@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+const (
+	defaultTokenExpirationSeconds = 3600
+	serviceAccountUsernamePrefix  = "system:serviceaccount:"
+)
+
+// tokenRenewalTimeout bounds the requests of a renewal, so that an
+// unresponsive tenant API server does not block the renewals. It is a
+// variable so that the tests can shorten it.
+var tokenRenewalTimeout = time.Minute
+
+// tenantToken is the ServiceAccount token of the tenant kubeconfig, renewed
+// with the TokenRequest API. The clients of the tenant cluster send the
+// latest token instead of the one of the kubeconfig.
+type tenantToken struct {
+	// namespace and name identify the ServiceAccount in the tenant cluster
+	namespace string
+	name      string
+
+	mu    sync.RWMutex
+	token string
+}
+
+// newTenantToken returns the tenantToken of the kubeconfig, nil when its
+// current context does not authenticate with a ServiceAccount token
+func newTenantToken(kubeconfig []byte) (*tenantToken, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return nil, nil
+	}
+	authInfo, ok := cfg.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return nil, nil
+	}
+	token := authInfo.Token
+	if token == "" && authInfo.TokenFile != "" {
+		bytes, err := os.ReadFile(authInfo.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(bytes))
+	}
+	if token == "" {
+		return nil, nil
+	}
+	namespace, name, ok := serviceAccountOfToken(token)
+	if !ok {
+		return nil, nil
+	}
+	return &tenantToken{namespace: namespace, name: name}, nil
+}
+
+// serviceAccountOfToken returns the namespace and name of the ServiceAccount
+// the token was issued for. The token is not verified, only its subject is
+// read.
+func serviceAccountOfToken(token string) (string, string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", false
+	}
+	claims := struct {
+		Subject string `json:"sub"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", "", false
+	}
+	if !strings.HasPrefix(claims.Subject, serviceAccountUsernamePrefix) {
+		return "", "", false
+	}
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(claims.Subject, serviceAccountUsernamePrefix), ":")
+	return namespace, name, ok && namespace != "" && name != ""
+}
+
+func (t *tenantToken) get() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.token
+}
+
+func (t *tenantToken) set(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token = token
+}
+
+// wrapTransport makes the requests of a rest config use the latest token
+func (t *tenantToken) wrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &tokenRoundTripper{next: rt, token: t}
+}
+
+type tokenRoundTripper struct {
+	next  http.RoundTripper
+	token *tenantToken
+}
+
+func (t *tokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := t.token.get()
+	if token == "" {
+		return t.next.RoundTrip(req)
+	}
+	req = utilnet.CloneRequest(req)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.next.RoundTrip(req)
+}
+
+// renewTenantToken renews the token of the tenant kubeconfig of cfg until
+// stopCh is closed. The token is renewed once 80% of its lifetime elapsed,
+// and the secret of the tenant kubeconfig mounted by the ovnkube-node pods is
+// updated with it. The renewal stops when cfg is deleted.
+func (r *DpuClusterConfigReconciler) renewTenantToken(cfg *dpuv1alpha1.DpuClusterConfig, restConfig *restclient.Config, token *tenantToken, stopCh <-chan struct{}) error {
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	for {
+		ctx, cancel := stopContext(stopCh, tokenRenewalTimeout)
+		renewAt, err := r.renewTenantTokenOnce(ctx, clientset, cfg, token)
+		cancel()
+		select {
+		case <-stopCh:
+			return nil
+		default:
+		}
+		if err != nil || renewAt.IsZero() {
+			return err
+		}
+
+		select {
+		case <-stopCh:
+			return nil
+		case <-time.After(time.Until(renewAt)):
+		}
+	}
+}
+
+// renewTenantTokenOnce renews the token and updates the secret of the tenant
+// kubeconfig, returning when the token is renewed next. A zero time is
+// returned when cfg is deleted.
+func (r *DpuClusterConfigReconciler) renewTenantTokenOnce(ctx context.Context, clientset kubernetes.Interface, cfg *dpuv1alpha1.DpuClusterConfig, token *tenantToken) (time.Time, error) {
+	// The expiration may have changed since the syncer started
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}, cfg); apierrors.IsNotFound(err) {
+		logger.Info("The DpuClusterConfig was deleted, the tenant token is no longer renewed", "namespace", cfg.Namespace)
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, err
+	}
+	expirationSeconds := int64(defaultTokenExpirationSeconds)
+	if cfg.Spec.TokenRenewal != nil && cfg.Spec.TokenRenewal.ExpirationSeconds != 0 {
+		expirationSeconds = cfg.Spec.TokenRenewal.ExpirationSeconds
+	}
+	tr := &authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds}}
+	issued := time.Now()
+	tr, err := clientset.CoreV1().ServiceAccounts(token.namespace).CreateToken(ctx, token.name, tr, metav1.CreateOptions{})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to request a token of ServiceAccount %s/%s: %w", token.namespace, token.name, err)
+	}
+	token.set(tr.Status.Token)
+	logger.Info("Renewed the tenant ServiceAccount token", "namespace", cfg.Namespace, "expiration", tr.Status.ExpirationTimestamp)
+
+	bytes, _, err := r.getTenantKubeconfig(ctx, cfg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if bytes, err = withToken(bytes, tr.Status.Token); err != nil {
+		return time.Time{}, err
+	}
+	if err := r.syncTenantKubeconfigSecret(ctx, cfg, bytes); err != nil {
+		return time.Time{}, err
+	}
+	return tokenRenewalTime(issued, tr.Status.ExpirationTimestamp.Time), nil
+}
+
+// stopContext returns a context cancelled after timeout, or once stopCh is
+// closed
+func stopContext(stopCh <-chan struct{}, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// tokenRenewalTime returns when a token issued at issued and expiring at
+// expiration is renewed, once 80% of its lifetime elapsed
+func tokenRenewalTime(issued, expiration time.Time) time.Time {
+	return issued.Add(expiration.Sub(issued) * 4 / 5)
+}
+
+// withToken returns the kubeconfig with the token of its current context
+// replaced
+func withToken(kubeconfig []byte, token string) ([]byte, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("current context %q not found", cfg.CurrentContext)
+	}
+	authInfo, ok := cfg.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("user %q not found", kubeContext.AuthInfo)
+	}
+	authInfo.Token = token
+	authInfo.TokenFile = ""
+	return clientcmd.Write(*cfg)
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+func newTokenTestKubeconfig() *clientcmdapi.Config {
+	return &clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{"tenant": {Server: "https://api.tenant:6443"}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"dpu":   {TokenFile: "/var/run/secrets/token"},
+			"admin": {Token: "admin-token"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"dpu":   {Cluster: "tenant", AuthInfo: "dpu"},
+			"admin": {Cluster: "tenant", AuthInfo: "admin"},
+			"other": {Cluster: "tenant", AuthInfo: "missing"},
+		},
+		CurrentContext: "dpu",
+	}
+}
+
+func TestWithToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentContext string
+		user           string
+		err            bool
+	}{
+		{name: "token file", currentContext: "dpu", user: "dpu"},
+		{name: "token", currentContext: "admin", user: "admin"},
+		{name: "current context not found", currentContext: "missing", err: true},
+		{name: "user not found", currentContext: "other", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			kubeconfig := newTokenTestKubeconfig()
+			kubeconfig.CurrentContext = tt.currentContext
+			bytes, err := clientcmd.Write(*kubeconfig)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			bytes, err = withToken(bytes, "renewed")
+			if tt.err {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			renewed, err := clientcmd.Load(bytes)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(renewed.CurrentContext).To(Equal(tt.currentContext))
+			for name, authInfo := range renewed.AuthInfos {
+				if name == tt.user {
+					g.Expect(authInfo.Token).To(Equal("renewed"))
+					g.Expect(authInfo.TokenFile).To(BeEmpty())
+				} else {
+					unchanged := newTokenTestKubeconfig().AuthInfos[name]
+					g.Expect(authInfo.Token).To(Equal(unchanged.Token), "user %s", name)
+					g.Expect(authInfo.TokenFile).To(Equal(unchanged.TokenFile), "user %s", name)
+				}
+			}
+		})
+	}
+}
+
+func TestWithTokenInvalidKubeconfig(t *testing.T) {
+	g := NewWithT(t)
+	_, err := withToken([]byte("{"), "renewed")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestTokenRenewalTime(t *testing.T) {
+	issued := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		expiration time.Time
+		renewAt    time.Time
+	}{
+		{name: "default expiration", expiration: issued.Add(time.Hour), renewAt: issued.Add(48 * time.Minute)},
+		{name: "short expiration", expiration: issued.Add(10 * time.Minute), renewAt: issued.Add(8 * time.Minute)},
+		{name: "long expiration", expiration: issued.Add(24 * time.Hour), renewAt: issued.Add(19*time.Hour + 12*time.Minute)},
+		{name: "already expired", expiration: issued, renewAt: issued},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tokenRenewalTime(issued, tt.expiration)).To(Equal(tt.renewAt))
+		})
+	}
+}
+
+func TestRenewTenantTokenConfigDeleted(t *testing.T) {
+	g := NewWithT(t)
+	r := &DpuClusterConfigReconciler{Client: newFakeClient()}
+	cfg := &dpuv1alpha1.DpuClusterConfig{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "tenant-a"}}
+	token := &tenantToken{namespace: "dpu", name: "dpu-network-operator"}
+
+	g.Expect(r.renewTenantToken(cfg, &restclient.Config{}, token, make(chan struct{}))).To(Succeed())
+}
+
+// newHangingTenantServer returns a tenant API server answering no request,
+// along with a channel receiving the path of each request
+func newHangingTenantServer(t *testing.T) (*httptest.Server, <-chan string) {
+	requests := make(chan string, 10)
+	closed := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests <- req.URL.Path
+		select {
+		case <-req.Context().Done():
+		case <-closed:
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(closed) })
+	return server, requests
+}
+
+func TestRenewTenantTokenTimeout(t *testing.T) {
+	g := NewWithT(t)
+	previous := tokenRenewalTimeout
+	tokenRenewalTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { tokenRenewalTimeout = previous })
+	cfg := &dpuv1alpha1.DpuClusterConfig{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "tenant-a"}}
+	r := &DpuClusterConfigReconciler{Client: newFakeClient(cfg.DeepCopy())}
+	token := &tenantToken{namespace: "dpu", name: "dpu-network-operator"}
+	server, requests := newHangingTenantServer(t)
+
+	err := r.renewTenantToken(cfg, &restclient.Config{Host: server.URL}, token, make(chan struct{}))
+	g.Expect(err).To(MatchError(ContainSubstring("context deadline exceeded")))
+	g.Expect(requests).To(Receive(Equal("/api/v1/namespaces/dpu/serviceaccounts/dpu-network-operator/token")))
+}
+
+func TestRenewTenantTokenStop(t *testing.T) {
+	g := NewWithT(t)
+	cfg := &dpuv1alpha1.DpuClusterConfig{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "tenant-a"}}
+	r := &DpuClusterConfigReconciler{Client: newFakeClient(cfg.DeepCopy())}
+	token := &tenantToken{namespace: "dpu", name: "dpu-network-operator"}
+	server, requests := newHangingTenantServer(t)
+	stopCh := make(chan struct{})
+
+	// The pending request is cancelled once the renewal is stopped
+	var wg sync.WaitGroup
+	var err error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err = r.renewTenantToken(cfg, &restclient.Config{Host: server.URL}, token, stopCh)
+	}()
+	g.Eventually(requests, 5*time.Second).Should(Receive())
+	close(stopCh)
+	wg.Wait()
+	g.Expect(err).NotTo(HaveOccurred())
+}
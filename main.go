@@ -17,12 +17,16 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"github.com/kelseyhightower/envconfig"
 	nmoapiv1beta1 "github.com/medik8s/node-maintenance-operator/api/v1beta1"
 	"github.com/openshift/dpu-network-operator/pkg/utils"
-	"github.com/sirupsen/logrus"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -32,12 +36,22 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/yaml"
 
 	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
 	"github.com/openshift/dpu-network-operator/controllers"
+	"github.com/openshift/dpu-network-operator/pkg/agent"
+	"github.com/openshift/dpu-network-operator/pkg/images"
+	"github.com/openshift/dpu-network-operator/pkg/logging"
+	"github.com/openshift/dpu-network-operator/pkg/metrics"
+	"github.com/openshift/dpu-network-operator/pkg/tracing"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -48,6 +62,7 @@ var (
 
 var Options struct {
 	NodeController controllers.Config
+	LocalOvnkube   controllers.LocalOvnkubeConfig
 }
 
 func init() {
@@ -59,14 +74,55 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		runRender(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "pause" {
+		runPause()
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var tenantAgent bool
+	var ovsAgent bool
+	var ovsAgentSettings string
+	var ovsAgentInterval time.Duration
+	var ovsAgentStatsInterval time.Duration
+	var ovsAgentMetricsAddr string
+	var connectivityCheck bool
+	var connectivityCheckInterval time.Duration
+	var auditEvents bool
+	var resyncPeriod time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":49555", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":49556", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&tenantAgent, "tenant-agent", false,
+		"Run the agent publishing the DPU serial number of the tenant node instead of the controller manager.")
+	flag.BoolVar(&ovsAgent, "ovs-agent", false,
+		"Run the agent enforcing the OVS settings of the DPU node instead of the controller manager.")
+	flag.StringVar(&ovsAgentSettings, "ovs-agent-settings", "/etc/dpu-ovs-agent/settings.json",
+		"The JSON file of the OVS settings enforced by the OVS agent.")
+	flag.DurationVar(&ovsAgentInterval, "ovs-agent-interval", time.Minute,
+		"The period at which the OVS agent checks the OVS settings.")
+	flag.DurationVar(&ovsAgentStatsInterval, "ovs-agent-stats-interval", 0,
+		"The period at which the OVS agent samples the datapath flows and the conntrack entries. Disabled when 0.")
+	flag.StringVar(&ovsAgentMetricsAddr, "ovs-agent-metrics-bind-address", ":9106",
+		"The address the OVS agent serves the sampled statistics on.")
+	flag.BoolVar(&connectivityCheck, "connectivity-check", false,
+		"Run the connectivity checker of a tenant worker backed by a DPU instead of the controller manager.")
+	flag.DurationVar(&connectivityCheckInterval, "connectivity-check-interval", 30*time.Second,
+		"The period at which the connectivity checker reaches the other checker pods and their service.")
+	flag.BoolVar(&auditEvents, "audit-events", false,
+		"Record the writes performed against the tenant clusters as events, in addition to the logs.")
+	flag.DurationVar(&resyncPeriod, "resync-period", 0,
+		"Period of the full resync re-rendering and re-applying the objects of the DpuClusterConfigs, to revert out-of-band edits. Disabled when 0.")
+	flag.BoolVar(&tracing.Enabled, "tracing", false,
+		"Time the reconciles, renders, applies and tenant cluster API calls, exposing the durations as metrics and logging them at verbosity 1.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -79,7 +135,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	logLevel := logging.NewLevel(&opts)
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	klog.SetLogger(ctrl.Log.WithName("klog"))
+	if tenantAgent {
+		runTenantAgent()
+		return
+	}
+	if ovsAgent {
+		runOvsAgent(ovsAgentSettings, ovsAgentInterval, ovsAgentStatsInterval, ovsAgentMetricsAddr)
+		return
+	}
+	if connectivityCheck {
+		runConnectivityCheck(connectivityCheckInterval)
+		return
+	}
 	err = nmoapiv1beta1.AddToScheme(scheme)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -99,20 +169,56 @@ func main() {
 		os.Exit(1)
 	}
 
+	platform, err := utils.DetectPlatform(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to detect the cluster platform")
+		os.Exit(1)
+	}
+	if !platform.HasMachineConfig() {
+		setupLog.Info("MachineConfig API is not available, the DPU host config is applied by a DaemonSet")
+	}
+	if !platform.HasSecurityContextConstraints() {
+		setupLog.Info("SecurityContextConstraints API is not available")
+	}
+
+	if Options.NodeController.Image == "" && Options.NodeController.OperatorImage == "" {
+		Options.NodeController.OperatorImage, err = utils.GetOperatorImage(context.TODO(), mgr.GetAPIReader(), utils.Namespace, utils.PodName)
+		if err != nil {
+			setupLog.Error(err, "unable to read the operator image, set IMAGE or OPERATOR_IMAGE")
+			os.Exit(1)
+		}
+	}
+
+	tenantConfigs := utils.NewTenantRestConfigStore()
+	imageResolver := images.NewResolver(mgr.GetAPIReader(), platform)
+	var recorder record.EventRecorder
+	if auditEvents {
+		recorder = mgr.GetEventRecorderFor("dpu-network-operator")
+	}
 	if err = (&controllers.DpuClusterConfigReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Platform:      platform,
+		TenantConfigs: tenantConfigs,
+		Images:        imageResolver,
+		LocalOvnkube:  Options.LocalOvnkube,
+		Recorder:      recorder,
+		ResyncPeriod:  resyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DpuClusterConfig")
 		os.Exit(1)
 	}
 
 	if err = (&controllers.DpuNodeLifecycleController{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		Log:       logrus.New(),
-		Config:    &Options.NodeController,
-		Namespace: utils.Namespace,
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Log:           ctrl.Log.WithName("controllers").WithName("DpuNodeLifecycle"),
+		Config:        &Options.NodeController,
+		TenantConfigs: tenantConfigs,
+		Images:        imageResolver,
+		Recorder:      recorder,
+		Events:        mgr.GetEventRecorderFor("dpu-network-operator"),
+		Namespace:     utils.Namespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DpuController")
 		os.Exit(1)
@@ -128,9 +234,125 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The metrics endpoint is served behind kube-rbac-proxy, which
+	// authorizes the requests to change the verbosity
+	if err := mgr.AddMetricsExtraHandler("/log-level", logLevel); err != nil {
+		setupLog.Error(err, "unable to set up the log level handler")
+		os.Exit(1)
+	}
+
+	ctrlmetrics.Registry.MustRegister(metrics.NewDocaTelemetryCollector(mgr.GetAPIReader()))
+	ctrlmetrics.Registry.MustRegister(metrics.NewOvsMetricsCollector(mgr.GetAPIReader()))
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+func runTenantAgent() {
+	nodeName := os.Getenv("NODE_NAME")
+	if nodeName == "" {
+		setupLog.Info("NODE_NAME is not set")
+		os.Exit(1)
+	}
+	a, err := agent.NewTenantAgent(ctrl.GetConfigOrDie(), nodeName)
+	if err != nil {
+		setupLog.Error(err, "unable to create the tenant agent")
+		os.Exit(1)
+	}
+	a.Run(ctrl.SetupSignalHandler())
+}
+
+func runOvsAgent(settings string, interval, statsInterval time.Duration, metricsAddr string) {
+	ctx := ctrl.SetupSignalHandler()
+	nodeName := os.Getenv("NODE_NAME")
+	if nodeName == "" {
+		setupLog.Info("NODE_NAME is not set")
+		os.Exit(1)
+	}
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create the client of the OVS agent")
+		os.Exit(1)
+	}
+	a := agent.NewOvsAgent(settings, interval)
+	a.Client = c
+	a.NodeName = nodeName
+	a.Namespace = os.Getenv("POD_NAMESPACE")
+	if statsInterval > 0 {
+		go a.RunStatistics(ctx, nodeName, metricsAddr, statsInterval)
+	}
+	go a.RunDumps(ctx, 10*time.Second)
+	go a.RunSouthboundCheck(ctx, os.Getenv("POD_NAME"), 30*time.Second)
+	a.Run(ctx)
+}
+
+func runConnectivityCheck(interval time.Duration) {
+	podName := os.Getenv("POD_NAME")
+	namespace := os.Getenv("POD_NAMESPACE")
+	if podName == "" || namespace == "" {
+		setupLog.Info("POD_NAME or POD_NAMESPACE is not set")
+		os.Exit(1)
+	}
+	c, err := agent.NewConnectivityChecker(ctrl.GetConfigOrDie(), namespace, podName, interval)
+	if err != nil {
+		setupLog.Error(err, "unable to create the connectivity checker")
+		os.Exit(1)
+	}
+	if err := c.Run(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "problem running the connectivity checker")
+		os.Exit(1)
+	}
+}
+
+// runPause waits for a termination signal, it is the command of the drain
+// blocker pods run from the operator image
+func runPause() {
+	<-ctrl.SetupSignalHandler().Done()
+}
+
+// runRender prints the manifests of a DpuClusterConfig read from a file,
+// as the operator would render them
+func runRender(args []string) {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	var file, masterIPs, sriovDevices string
+	opts := controllers.RenderOptions{}
+	fs.StringVar(&file, "f", "", "The DpuClusterConfig YAML file, - for stdin.")
+	fs.StringVar(&opts.OvnkubeImage, "ovnkube-image", os.Getenv("OVNKUBE_IMAGE"), "The ovnkube-node image.")
+	fs.StringVar(&masterIPs, "master-ips", "", "Comma-separated IPs of the ovnkube-master pods of the tenant cluster.")
+	fs.StringVar(&opts.OvsdbRelayIP, "ovsdb-relay-ip", "", "The ClusterIP of the OVSDB relay service, when the relay is enabled.")
+	fs.StringVar(&sriovDevices, "sriov-managed-devices", "", "Comma-separated PCI addresses of the NICs managed by the sriov-network-operator.")
+	fs.Parse(args)
+
+	var content []byte
+	var err error
+	if file == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(file)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the DpuClusterConfig: %v\n", err)
+		os.Exit(1)
+	}
+	cfg := &dpuv1alpha1.DpuClusterConfig{}
+	if err := yaml.UnmarshalStrict(content, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse the DpuClusterConfig: %v\n", err)
+		os.Exit(1)
+	}
+	if masterIPs != "" {
+		opts.MasterIPs = strings.Split(masterIPs, ",")
+	}
+	if sriovDevices != "" {
+		opts.SriovManagedDevices = strings.Split(sriovDevices, ",")
+	}
+
+	manifests, err := controllers.Render(cfg, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render the manifests: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(manifests)
+}
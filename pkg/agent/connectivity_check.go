@@ -0,0 +1,172 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+var checkLogger = ctrl.Log.WithName("connectivity-check")
+
+const (
+	// connectivityCheckPort is the port the checker pods serve on, and
+	// reach each other and their service on
+	connectivityCheckPort = 8080
+	// maxReportedPeers bounds the unreachable peers listed in the message
+	// of the PodToPod condition
+	maxReportedPeers = 10
+)
+
+// ConnectivityChecker validates the paths through the offloaded datapath of
+// the DPU backing a tenant worker. It serves HTTP on its pod IP and, every
+// Interval, reaches the checker pods of the other tenant workers and the
+// ClusterIP service in front of them. The results are set as conditions of
+// its pod, which the operator collects in the DpuConnectivityCheck.
+type ConnectivityChecker struct {
+	client     client.Client
+	httpClient *http.Client
+	namespace  string
+	podName    string
+	// Interval is the period of the checks
+	Interval time.Duration
+	// Timeout bounds each request of a check
+	Timeout time.Duration
+}
+
+func NewConnectivityChecker(cfg *rest.Config, namespace, podName string, interval time.Duration) (*ConnectivityChecker, error) {
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectivityChecker{
+		client: c,
+		// Every request opens a new connection, so that the checks are
+		// not served by flows already offloaded to the DPU
+		httpClient: &http.Client{Transport: &http.Transport{DisableKeepAlives: true}},
+		namespace:  namespace,
+		podName:    podName,
+		Interval:   interval,
+		Timeout:    5 * time.Second,
+	}, nil
+}
+
+// Run serves the checks of the other pods and runs the checks of this pod
+// until ctx is done
+func (c *ConnectivityChecker) Run(ctx context.Context) error {
+	checkLogger.Info("Start the connectivity checker", "pod", c.podName, "interval", c.Interval)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	server := &http.Server{Addr: fmt.Sprintf(":%d", connectivityCheckPort), Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	go wait.UntilWithContext(ctx, c.check, c.Interval)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (c *ConnectivityChecker) check(ctx context.Context) {
+	pods := &corev1.PodList{}
+	if err := c.client.List(ctx, pods, client.InNamespace(c.namespace), client.MatchingLabels{"app": utils.ConnectivityCheckName}); err != nil {
+		checkLogger.Error(err, "Fail to list the checker pods")
+		return
+	}
+	for _, condition := range []corev1.PodCondition{c.podToPodCondition(ctx, pods.Items), c.podToServiceCondition(ctx)} {
+		changed, err := setPodCondition(ctx, c.client, c.namespace, c.podName, condition)
+		if err != nil {
+			checkLogger.Error(err, "Fail to report the connectivity check", "check", condition.Type)
+		}
+		if changed && condition.Status == corev1.ConditionFalse {
+			checkLogger.Info("Connectivity check failed", "check", condition.Type, "message", condition.Message)
+		}
+	}
+}
+
+// podToPodCondition reaches the running checker pods of the other tenant
+// workers on their pod IP
+func (c *ConnectivityChecker) podToPodCondition(ctx context.Context, pods []corev1.Pod) corev1.PodCondition {
+	condition := corev1.PodCondition{Type: utils.PodToPodCondition}
+	peers := 0
+	failed := []string{}
+	for _, pod := range pods {
+		if pod.Name == c.podName || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		peers++
+		if err := c.get(ctx, pod.Status.PodIP); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s): %v", pod.Spec.NodeName, pod.Status.PodIP, err))
+		}
+	}
+	switch {
+	case peers == 0:
+		condition.Status = corev1.ConditionUnknown
+		condition.Reason = "NoPeers"
+		condition.Message = "no checker pod runs on another tenant node"
+	case len(failed) > 0:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "Unreachable"
+		condition.Message = fmt.Sprintf("%d of %d peers unreachable: ", len(failed), peers)
+		if len(failed) > maxReportedPeers {
+			failed = append(failed[:maxReportedPeers], fmt.Sprintf("and %d more", len(failed)-maxReportedPeers))
+		}
+		condition.Message += strings.Join(failed, "; ")
+	default:
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "Reachable"
+		condition.Message = fmt.Sprintf("%d peers reachable", peers)
+	}
+	return condition
+}
+
+// podToServiceCondition reaches the ClusterIP service of the checker pods by
+// its DNS name
+func (c *ConnectivityChecker) podToServiceCondition(ctx context.Context) corev1.PodCondition {
+	condition := corev1.PodCondition{Type: utils.PodToServiceCondition}
+	host := fmt.Sprintf("%s.%s.svc", utils.ConnectivityCheckName, c.namespace)
+	if err := c.get(ctx, host); err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "Unreachable"
+		condition.Message = fmt.Sprintf("%s: %v", host, err)
+		return condition
+	}
+	condition.Status = corev1.ConditionTrue
+	condition.Reason = "Reachable"
+	condition.Message = host + " reachable"
+	return condition
+}
+
+func (c *ConnectivityChecker) get(ctx context.Context, host string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	url := fmt.Sprintf("http://%s/healthz", net.JoinHostPort(host, strconv.Itoa(connectivityCheckPort)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// mlxdevmPath is the mlxdevm tool of mlnx-iproute2, managing the scalable
+// functions of the BlueField DPUs
+const mlxdevmPath = "/opt/mellanox/iproute2/sbin/mlxdevm"
+
+// FunctionSettings are the functions of a host PF requested by a DpuResource
+type FunctionSettings struct {
+	// Resource is the name of the DpuResource
+	Resource string                      `json:"resource"`
+	PfID     int32                       `json:"pfId"`
+	Type     dpuv1alpha1.DpuFunctionType `json:"type"`
+	Count    int32                       `json:"count"`
+	// Bridge is the OVS bridge the SF representors are attached to, if any
+	Bridge string `json:"bridge,omitempty"`
+}
+
+// devlinkPort is a port of the output of devlink -j port show, or of
+// mlxdevm -j port show
+type devlinkPort struct {
+	Netdev   string `json:"netdev"`
+	Flavour  string `json:"flavour"`
+	PfNum    *int32 `json:"pfnum"`
+	SfNum    *int32 `json:"sfnum"`
+	External bool   `json:"external"`
+	Function struct {
+		State   string `json:"state"`
+		OpState string `json:"opstate"`
+	} `json:"function"`
+}
+
+// functionPort is a VF or SF port of a host PF
+type functionPort struct {
+	devlinkPort
+	handle string
+	sfNum  int32
+}
+
+// enforceFunctions applies the functions exposed to the host by the DPU and
+// reports them in the status of their DpuResource
+func (a *OvsAgent) enforceFunctions(ctx context.Context, functions []FunctionSettings) error {
+	errs := []error{}
+	for _, f := range functions {
+		status := dpuv1alpha1.DpuResourceNodeStatus{Available: f.Count}
+		if !utils.SimulateHardware {
+			var err error
+			status, err = a.applyFunctions(ctx, f)
+			if err != nil {
+				ovsLogger.Error(err, "Fail to apply the functions", "resource", f.Resource)
+				status.Message = err.Error()
+			}
+		}
+		if err := a.reportFunctions(ctx, f.Resource, status); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// applyFunctions creates or removes the functions of the host PF, and
+// returns the functions available to the host along with the reason they
+// are not all available
+func (a *OvsAgent) applyFunctions(ctx context.Context, f FunctionSettings) (dpuv1alpha1.DpuResourceNodeStatus, error) {
+	status := dpuv1alpha1.DpuResourceNodeStatus{}
+	ports, err := a.devlinkPorts(ctx, "/sbin/devlink")
+	if err != nil {
+		return status, err
+	}
+	device := ""
+	for handle, port := range ports {
+		if port.Flavour == "pcipf" && port.External && port.PfNum != nil && *port.PfNum == f.PfID {
+			device = handle[:strings.LastIndex(handle, "/")]
+		}
+	}
+	if device == "" {
+		status.Message = fmt.Sprintf("host PF %d not found", f.PfID)
+		return status, nil
+	}
+	if f.Type == dpuv1alpha1.DpuFunctionSF {
+		return a.applyScalableFunctions(ctx, device, f)
+	}
+	return a.applyVirtualFunctions(ctx, device, functionPorts(ports, "pcivf", f.PfID), f)
+}
+
+// applyScalableFunctions adds the missing SFs of the host PF with mlxdevm,
+// with the lowest free sfnums, and deletes the ones with the highest sfnums
+// in excess. The representors of the SFs are attached to the OVS bridge of
+// the settings, if any.
+func (a *OvsAgent) applyScalableFunctions(ctx context.Context, device string, f FunctionSettings) (dpuv1alpha1.DpuResourceNodeStatus, error) {
+	status := dpuv1alpha1.DpuResourceNodeStatus{}
+	ports, err := a.devlinkPorts(ctx, mlxdevmPath)
+	if err != nil {
+		return status, err
+	}
+	sfs := functionPorts(ports, "pcisf", f.PfID)
+	used := map[int32]bool{}
+	for _, sf := range sfs {
+		used[sf.sfNum] = true
+	}
+	created := len(sfs)
+	for sfNum := int32(0); created < int(f.Count); sfNum++ {
+		if used[sfNum] {
+			continue
+		}
+		ovsLogger.Info("Add a scalable function", "device", device, "pf", f.PfID, "sfnum", sfNum)
+		out, err := a.hostCommand(ctx, mlxdevmPath, "-j", "port", "add", device, "flavour", "pcisf",
+			"pfnum", strconv.Itoa(int(f.PfID)), "sfnum", strconv.Itoa(int(sfNum)), "controller", "1")
+		if err != nil {
+			return status, err
+		}
+		added, err := parseDevlinkPorts(out)
+		if err != nil {
+			return status, err
+		}
+		for handle := range added {
+			if _, err := a.hostCommand(ctx, mlxdevmPath, "port", "function", "set", handle, "state", "active"); err != nil {
+				return status, err
+			}
+		}
+		created++
+	}
+	for i := len(sfs) - 1; i >= int(f.Count); i-- {
+		sf := sfs[i]
+		ovsLogger.Info("Delete a scalable function", "port", sf.handle, "sfnum", sf.sfNum)
+		if sf.Netdev != "" {
+			if _, err := a.vsctl(ctx, "--if-exists", "del-port", sf.Netdev); err != nil {
+				return status, err
+			}
+		}
+		if _, err := a.hostCommand(ctx, mlxdevmPath, "port", "function", "set", sf.handle, "state", "inactive"); err != nil {
+			return status, err
+		}
+		if _, err := a.hostCommand(ctx, mlxdevmPath, "port", "del", sf.handle); err != nil {
+			return status, err
+		}
+	}
+
+	// The SFs are listed again for their representors and states
+	ports, err = a.devlinkPorts(ctx, mlxdevmPath)
+	if err != nil {
+		return status, err
+	}
+	for _, sf := range functionPorts(ports, "pcisf", f.PfID) {
+		function := dpuv1alpha1.DpuFunctionStatus{
+			SfNum:       sf.sfNum,
+			Representor: sf.Netdev,
+			State:       sf.Function.State,
+			OpState:     sf.Function.OpState,
+		}
+		if f.Bridge != "" && sf.Netdev != "" {
+			if err := a.attachRepresentor(ctx, f.Bridge, sf.Netdev); err != nil {
+				return status, err
+			}
+			function.Bridge = f.Bridge
+		}
+		status.Functions = append(status.Functions, function)
+	}
+	status.Available = int32(len(status.Functions))
+	return status, nil
+}
+
+// attachRepresentor adds the representor to the OVS bridge, moving it from
+// another bridge if needed
+func (a *OvsAgent) attachRepresentor(ctx context.Context, bridge, netdev string) error {
+	out, err := a.vsctl(ctx, "list-ports", bridge)
+	if err != nil {
+		return err
+	}
+	for _, port := range strings.Fields(out) {
+		if port == netdev {
+			return nil
+		}
+	}
+	ovsLogger.Info("Attach a scalable function representor", "bridge", bridge, "representor", netdev)
+	_, err = a.vsctl(ctx, "--if-exists", "del-port", netdev, "--", "add-port", bridge, netdev)
+	return err
+}
+
+// applyVirtualFunctions sets the NUM_OF_VFS firmware parameter of the host
+// PF. The VFs are enabled by the host, the firmware parameter only applies
+// at the next power cycle.
+func (a *OvsAgent) applyVirtualFunctions(ctx context.Context, device string, vfs []functionPort, f FunctionSettings) (dpuv1alpha1.DpuResourceNodeStatus, error) {
+	status := dpuv1alpha1.DpuResourceNodeStatus{Available: int32(len(vfs))}
+	if status.Available == f.Count {
+		return status, nil
+	}
+	pci := strings.TrimPrefix(device, "pci/")
+	out, err := a.hostCommand(ctx, "mstconfig", "-d", pci, "query", "NUM_OF_VFS")
+	if err != nil {
+		return status, err
+	}
+	numVfs := int32(-1)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "NUM_OF_VFS" {
+			if n, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+				numVfs = int32(n)
+			}
+		}
+	}
+	if numVfs != f.Count {
+		ovsLogger.Info("Set the number of VFs of the host PF", "device", pci, "current", numVfs, "desired", f.Count)
+		if _, err := a.hostCommand(ctx, "mstconfig", "-y", "-d", pci, "set", fmt.Sprintf("NUM_OF_VFS=%d", f.Count)); err != nil {
+			return status, err
+		}
+		status.Message = fmt.Sprintf("NUM_OF_VFS set to %d, applied at the next power cycle of the host", f.Count)
+		return status, nil
+	}
+	status.Message = fmt.Sprintf("the host enabled %d of the %d VFs", status.Available, f.Count)
+	return status, nil
+}
+
+// reportFunctions records the functions of the node in the status of the
+// DpuResource, when they changed
+func (a *OvsAgent) reportFunctions(ctx context.Context, resource string, status dpuv1alpha1.DpuResourceNodeStatus) error {
+	if a.Client == nil {
+		return nil
+	}
+	status.Node = a.NodeName
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		res := &dpuv1alpha1.DpuResource{}
+		if err := a.Client.Get(ctx, types.NamespacedName{Namespace: a.Namespace, Name: resource}, res); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		nodes := []dpuv1alpha1.DpuResourceNodeStatus{}
+		for _, n := range res.Status.Nodes {
+			if n.Node != a.NodeName {
+				nodes = append(nodes, n)
+				continue
+			}
+			status.LastUpdateTime = n.LastUpdateTime
+			if equality.Semantic.DeepEqual(n, status) {
+				return nil
+			}
+		}
+		status.LastUpdateTime = metav1.Now()
+		nodes = append(nodes, status)
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Node < nodes[j].Node })
+		res.Status.Nodes = nodes
+		return a.Client.Status().Update(ctx, res)
+	})
+}
+
+// devlinkPorts returns the ports listed by the devlink or mlxdevm tool at
+// path, by handle
+func (a *OvsAgent) devlinkPorts(ctx context.Context, path string) (map[string]devlinkPort, error) {
+	out, err := a.hostCommand(ctx, path, "-j", "port", "show")
+	if err != nil {
+		return nil, err
+	}
+	return parseDevlinkPorts(out)
+}
+
+func parseDevlinkPorts(out string) (map[string]devlinkPort, error) {
+	ports := struct {
+		Port map[string]devlinkPort `json:"port"`
+	}{}
+	if strings.TrimSpace(out) == "" {
+		return map[string]devlinkPort{}, nil
+	}
+	if err := json.Unmarshal([]byte(out), &ports); err != nil {
+		return nil, fmt.Errorf("failed to parse the devlink ports: %v", err)
+	}
+	return ports.Port, nil
+}
+
+// functionPorts returns the external ports of a flavour of the host PF,
+// sorted by sfnum
+func functionPorts(ports map[string]devlinkPort, flavour string, pfID int32) []functionPort {
+	functions := []functionPort{}
+	for handle, port := range ports {
+		if port.Flavour != flavour || !port.External || port.PfNum == nil || *port.PfNum != pfID {
+			continue
+		}
+		fp := functionPort{devlinkPort: port, handle: handle}
+		if port.SfNum != nil {
+			fp.sfNum = *port.SfNum
+		}
+		functions = append(functions, fp)
+	}
+	sort.Slice(functions, func(i, j int) bool { return functions[i].sfNum < functions[j].sfNum })
+	return functions
+}
@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+var ovsLogger = ctrl.Log.WithName("ovs-agent")
+
+// OvsSettings are the desired keys of the map columns of the Open_vSwitch
+// table and the functions exposed to the hosts, as written by the operator
+// in the ConfigMap of the agent
+type OvsSettings struct {
+	OtherConfig map[string]string `json:"other_config,omitempty"`
+	ExternalIDs map[string]string `json:"external_ids,omitempty"`
+	// Functions are the functions exposed to the host by each DPU node
+	Functions map[string][]FunctionSettings `json:"functions,omitempty"`
+}
+
+// OvsAgent enforces the OVS settings of a DPU node, correcting the drift
+// caused by manual ovs-vsctl edits
+type OvsAgent struct {
+	// SettingsFile is the JSON file of the desired OvsSettings, it is
+	// re-read at each check so that the updates of the ConfigMap apply
+	// without restarting the agent
+	SettingsFile string
+	// HostRoot is the root of the host filesystem, ovs-vsctl is run
+	// chrooted in it
+	HostRoot string
+	// Interval is the period at which the settings are checked
+	Interval time.Duration
+	// Client updates the status of the DpuResources, they are not
+	// reported when it is nil
+	Client client.Client
+	// NodeName is the DPU node of the agent and Namespace the namespace of
+	// its DpuResources
+	NodeName  string
+	Namespace string
+}
+
+func NewOvsAgent(settingsFile string, interval time.Duration) *OvsAgent {
+	return &OvsAgent{
+		SettingsFile: settingsFile,
+		HostRoot:     "/host",
+		Interval:     interval,
+	}
+}
+
+// Run enforces the settings until ctx is done
+func (a *OvsAgent) Run(ctx context.Context) {
+	ovsLogger.Info("Start the OVS agent", "settings", a.SettingsFile, "interval", a.Interval)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := a.enforceSettings(ctx); err != nil {
+			ovsLogger.Error(err, "Fail to enforce the OVS settings")
+		}
+	}, a.Interval)
+}
+
+func (a *OvsAgent) enforceSettings(ctx context.Context) error {
+	b, err := os.ReadFile(a.SettingsFile)
+	if err != nil {
+		return err
+	}
+	settings := OvsSettings{}
+	if err := json.Unmarshal(b, &settings); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", a.SettingsFile, err)
+	}
+	if utils.SimulateHardware {
+		ovsLogger.V(1).Info("DPU hardware is simulated, skip the OVS settings", "settings", settings)
+		return a.enforceFunctions(ctx, settings.Functions[a.NodeName])
+	}
+	if err := a.enforceColumn(ctx, "other_config", settings.OtherConfig); err != nil {
+		return err
+	}
+	if err := a.enforceColumn(ctx, "external_ids", settings.ExternalIDs); err != nil {
+		return err
+	}
+	return a.enforceFunctions(ctx, settings.Functions[a.NodeName])
+}
+
+// enforceColumn sets the keys of the column of the Open_vSwitch table that
+// differ from the desired values
+func (a *OvsAgent) enforceColumn(ctx context.Context, column string, desired map[string]string) error {
+	keys := make([]string, 0, len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out, err := a.vsctl(ctx, "--if-exists", "get", "Open_vSwitch", ".", column+":"+k)
+		if err != nil {
+			return err
+		}
+		current := strings.Trim(strings.TrimSpace(out), `"`)
+		if current == desired[k] {
+			continue
+		}
+		ovsLogger.Info("Correct the drift of an OVS setting", "column", column, "key", k, "current", current, "desired", desired[k])
+		if _, err := a.vsctl(ctx, "set", "Open_vSwitch", ".", fmt.Sprintf("%s:%s=%q", column, k, desired[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vsctl runs the ovs-vsctl of the host
+func (a *OvsAgent) vsctl(ctx context.Context, args ...string) (string, error) {
+	return a.hostCommand(ctx, "ovs-vsctl", append([]string{"--timeout=15"}, args...)...)
+}
+
+// appctl runs the ovs-appctl of the host
+func (a *OvsAgent) appctl(ctx context.Context, args ...string) (string, error) {
+	return a.hostCommand(ctx, "ovs-appctl", append([]string{"--timeout=15"}, args...)...)
+}
+
+// hostCommand runs name chrooted in the host filesystem, from /bin unless it
+// is an absolute path
+func (a *OvsAgent) hostCommand(ctx context.Context, name string, args ...string) (string, error) {
+	path := name
+	if !strings.HasPrefix(name, "/") {
+		path = "/bin/" + name
+	}
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Chroot: a.HostRoot}
+	cmd.Dir = "/"
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("%s %s failed: %v: %s", name, strings.Join(args, " "), err, exitErr.Stderr)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
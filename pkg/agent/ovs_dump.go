@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// maxDumpSize bounds the size of the dumps written in a ConfigMap, below the
+// 1MiB limit of the objects
+const maxDumpSize = 900 * 1024
+
+// dumpCommand is a command of an OVS dump, its output being written in the
+// key of the dump ConfigMap
+type dumpCommand struct {
+	key  string
+	name string
+	args []string
+}
+
+// RunDumps checks every interval whether an OVS dump of the node is requested
+// in its dump ConfigMap, and takes it, until ctx is done
+func (a *OvsAgent) RunDumps(ctx context.Context, interval time.Duration) {
+	if a.Client == nil {
+		return
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := a.dump(ctx); err != nil {
+			ovsLogger.Error(err, "Fail to take the requested OVS dump")
+		}
+	}, interval)
+}
+
+func (a *OvsAgent) dump(ctx context.Context) error {
+	cm := &corev1.ConfigMap{}
+	name := types.NamespacedName{Namespace: a.Namespace, Name: utils.OvsDumpConfigMapPrefix + a.NodeName}
+	if err := a.Client.Get(ctx, name, cm); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	request := cm.Annotations[utils.OvsDumpAnnotation]
+	if request == "" || cm.Annotations[utils.OvsDumpCompletedAnnotation] == request {
+		return nil
+	}
+	ovsLogger.Info("Take an OVS dump", "request", request, "configmap", name.Name)
+	cm.Data = a.captureDump(ctx)
+	cm.Annotations[utils.OvsDumpCompletedAnnotation] = request
+	// A new request written meanwhile makes the update conflict, it is
+	// taken at the next check
+	return a.Client.Update(ctx, cm)
+}
+
+// captureDump runs the commands of an OVS dump, by key. The output of a
+// failed command is replaced with its error, and the outputs exceeding
+// maxDumpSize altogether are truncated.
+func (a *OvsAgent) captureDump(ctx context.Context) map[string]string {
+	commands := []dumpCommand{
+		{key: "ovs-vsctl-show.txt", name: "ovs-vsctl", args: []string{"--timeout=15", "show"}},
+		{key: "dpctl-dump-flows.txt", name: "ovs-appctl", args: []string{"--timeout=15", "dpctl/dump-flows", "-m"}},
+		{key: "dpctl-dump-flows-offloaded.txt", name: "ovs-appctl", args: []string{"--timeout=15", "dpctl/dump-flows", "type=offloaded"}},
+		{key: "upcall-show.txt", name: "ovs-appctl", args: []string{"--timeout=15", "upcall/show"}},
+		{key: "coverage-show.txt", name: "ovs-appctl", args: []string{"--timeout=15", "coverage/show"}},
+	}
+	bridges, err := a.vsctl(ctx, "list-br")
+	if err != nil {
+		ovsLogger.Error(err, "Fail to list the OVS bridges, their OpenFlow flows are not dumped")
+	}
+	for _, bridge := range strings.Fields(bridges) {
+		commands = append(commands, dumpCommand{
+			key:  "ofctl-dump-flows-" + bridge + ".txt",
+			name: "ovs-ofctl",
+			args: []string{"--timeout=15", "dump-flows", bridge},
+		})
+	}
+
+	data := map[string]string{}
+	size := 0
+	for _, c := range commands {
+		out, err := a.hostCommand(ctx, c.name, c.args...)
+		if err != nil {
+			out = err.Error() + "\n"
+		}
+		if remaining := maxDumpSize - size; len(out) > remaining {
+			if remaining < 0 {
+				remaining = 0
+			}
+			out = out[:remaining] + "\n... truncated\n"
+		}
+		size += len(out)
+		data[c.key] = out
+	}
+	return data
+}
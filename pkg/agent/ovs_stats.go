@@ -0,0 +1,137 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+var (
+	datapathFlows = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ovs_agent_datapath_flows",
+		Help: "Number of datapath flows of the DPU, by type: offloaded to the NIC or handled by the kernel datapath",
+	}, []string{"tenant_node", "type"})
+	conntrackEntries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ovs_agent_conntrack_entries",
+		Help: "Number of conntrack entries of the DPU datapath, by protocol",
+	}, []string{"tenant_node", "protocol"})
+	representorInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ovs_agent_representor_info",
+		Help: "Pod interface of the tenant node bound to a representor of the DPU, always 1",
+	}, []string{"tenant_node", "representor", "pod_namespace", "pod_name", "network", "mac"})
+	southboundConnected = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ovs_agent_ovn_southbound_connected",
+		Help: "Whether the ovn-controller of the DPU is connected to the southbound database, 1 or 0",
+	}, []string{"tenant_node"})
+)
+
+// ctProtocolRegex matches the protocol lines of ovs-appctl dpctl/ct-stats-show,
+// the connection states being further indented
+var ctProtocolRegex = regexp.MustCompile(`^    ([A-Za-z0-9]+): ([0-9]+)$`)
+
+// RunStatistics samples the datapath flows, the conntrack entries and the
+// pods bound to the representors of the DPU node every interval, and serves
+// them as metrics on bindAddress until ctx is done. The samples are labeled
+// with the tenant node of the DPU, so that the statistics of a tenant node
+// can be aggregated across the DPUs.
+func (a *OvsAgent) RunStatistics(ctx context.Context, nodeName, bindAddress string, interval time.Duration) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(datapathFlows, conntrackEntries, representorInfo, southboundConnected)
+	server := &http.Server{Addr: bindAddress, Handler: promhttp.HandlerFor(registry, promhttp.HandlerOpts{})}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			ovsLogger.Error(err, "Fail to serve the OVS statistics", "address", bindAddress)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	ovsLogger.Info("Start to sample the OVS statistics", "node", nodeName, "interval", interval)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := a.sampleStatistics(ctx, nodeName); err != nil {
+			ovsLogger.Error(err, "Fail to sample the OVS statistics")
+		}
+	}, interval)
+}
+
+func (a *OvsAgent) sampleStatistics(ctx context.Context, nodeName string) error {
+	if utils.SimulateHardware {
+		return nil
+	}
+	// The tenant node is looked up at each sample, as the env-overrides
+	// ConfigMap may be updated after the agent started
+	tenantNode, err := utils.GetMatchedTenantNode(nodeName)
+	if err != nil {
+		ovsLogger.V(1).Info("No tenant node for the DPU node", "node", nodeName, "reason", err.Error())
+		tenantNode = ""
+	}
+
+	flows := map[string]int{}
+	for _, flowType := range []string{"offloaded", "ovs"} {
+		out, err := a.appctl(ctx, "dpctl/dump-flows", "type="+flowType)
+		if err != nil {
+			return err
+		}
+		flows[flowType] = countLines(out)
+	}
+	out, err := a.appctl(ctx, "dpctl/ct-stats-show")
+	if err != nil {
+		return err
+	}
+	entries := parseConntrackStats(out)
+
+	datapathFlows.Reset()
+	for flowType, n := range flows {
+		datapathFlows.WithLabelValues(tenantNode, flowType).Set(float64(n))
+	}
+	conntrackEntries.Reset()
+	for protocol, n := range entries {
+		conntrackEntries.WithLabelValues(tenantNode, protocol).Set(float64(n))
+	}
+	return a.sampleRepresentors(ctx, tenantNode)
+}
+
+func countLines(out string) int {
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// parseConntrackStats returns the number of conntrack entries of each
+// protocol listed by ovs-appctl dpctl/ct-stats-show, e.g.
+//
+//	Connections Stats:
+//	    Total: 5
+//	    TCP: 3
+//	      ESTABLISHED: 3
+//	    UDP: 2
+func parseConntrackStats(out string) map[string]int {
+	entries := map[string]int{}
+	for _, line := range strings.Split(out, "\n") {
+		m := ctProtocolRegex.FindStringSubmatch(strings.TrimRight(line, " \t"))
+		if m == nil || m[1] == "Total" {
+			continue
+		}
+		n, err := strconv.Atoi(m[2])
+		if err != nil {
+			continue
+		}
+		entries[strings.ToLower(m[1])] = n
+	}
+	return entries
+}
@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// setPodCondition sets the condition of the pod of an agent, unless it is
+// already set, and returns whether it changed
+func setPodCondition(ctx context.Context, c client.Client, namespace, podName string, condition corev1.PodCondition) (bool, error) {
+	if c == nil || podName == "" {
+		return false, nil
+	}
+	pod := &corev1.Pod{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: podName}, pod); err != nil {
+		return false, err
+	}
+	patch := client.StrategicMergeFrom(pod.DeepCopy())
+	now := metav1.Now()
+	condition.LastProbeTime = now
+	condition.LastTransitionTime = now
+	found := false
+	for i, cond := range pod.Status.Conditions {
+		if cond.Type != condition.Type {
+			continue
+		}
+		if cond.Status == condition.Status && cond.Reason == condition.Reason && cond.Message == condition.Message {
+			return false, nil
+		}
+		if cond.Status == condition.Status {
+			condition.LastTransitionTime = cond.LastTransitionTime
+		}
+		pod.Status.Conditions[i] = condition
+		found = true
+	}
+	if !found {
+		pod.Status.Conditions = append(pod.Status.Conditions, condition)
+	}
+	return true, c.Status().Patch(ctx, pod, patch)
+}
@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// representorPort is a pod interface attached to OVS on the DPU, through the
+// representor of the VF or SF of the host given to the pod
+type representorPort struct {
+	representor string
+	namespace   string
+	pod         string
+	network     string
+	mac         string
+}
+
+// sampleRepresentors exports the pods of the tenant node bound to each
+// representor of the DPU, as set by ovnkube-node in the external IDs of the
+// OVS interfaces
+func (a *OvsAgent) sampleRepresentors(ctx context.Context, tenantNode string) error {
+	out, err := a.vsctl(ctx, "--format=json", "--columns=name,external_ids", "list", "Interface")
+	if err != nil {
+		return err
+	}
+	ports, err := parseRepresentorPorts(out)
+	if err != nil {
+		return err
+	}
+	representorInfo.Reset()
+	for _, p := range ports {
+		representorInfo.WithLabelValues(tenantNode, p.representor, p.namespace, p.pod, p.network, p.mac).Set(1)
+	}
+	return nil
+}
+
+// parseRepresentorPorts returns the interfaces of the ovs-vsctl JSON output
+// with an iface-id external ID, i.e. the logical switch port of a pod named
+// namespace_pod, prefixed with the network for the secondary networks
+func parseRepresentorPorts(out string) ([]representorPort, error) {
+	table := struct {
+		Data [][]json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal([]byte(out), &table); err != nil {
+		return nil, fmt.Errorf("failed to parse the OVS interfaces: %v", err)
+	}
+	ports := []representorPort{}
+	for _, row := range table.Data {
+		if len(row) != 2 {
+			continue
+		}
+		var name string
+		if err := json.Unmarshal(row[0], &name); err != nil {
+			continue
+		}
+		ids, err := parseOvsMap(row[1])
+		if err != nil {
+			continue
+		}
+		// Namespaces and pod names are DNS labels, without underscores
+		parts := strings.Split(ids["iface-id"], "_")
+		if len(parts) < 2 {
+			continue
+		}
+		network := ids["k8s.ovn.org/nad"]
+		if network == "" {
+			network = "default"
+		}
+		ports = append(ports, representorPort{
+			representor: name,
+			namespace:   parts[len(parts)-2],
+			pod:         parts[len(parts)-1],
+			network:     network,
+			mac:         ids["attached_mac"],
+		})
+	}
+	return ports, nil
+}
+
+// parseOvsMap parses an OVSDB map of the ovs-vsctl JSON output, e.g.
+// ["map",[["iface-id","default_nginx"]]]
+func parseOvsMap(raw json.RawMessage) (map[string]string, error) {
+	value := []json.RawMessage{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	if len(value) != 2 {
+		return nil, fmt.Errorf("unexpected OVSDB map %s", raw)
+	}
+	pairs := [][2]string{}
+	if err := json.Unmarshal(value[1], &pairs); err != nil {
+		return nil, err
+	}
+	m := map[string]string{}
+	for _, pair := range pairs {
+		m[pair[0]] = pair[1]
+	}
+	return m, nil
+}
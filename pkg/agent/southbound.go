@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+// ovnControllerPidFile is the pidfile of the ovn-controller of the
+// ovnkube-node pod, in the host filesystem
+const ovnControllerPidFile = "/var/run/ovn/ovn-controller.pid"
+
+// RunSouthboundCheck checks every interval whether the ovn-controller of the
+// DPU node is connected to the southbound database, until ctx is done. The
+// result is exported as a metric and set as a condition of the pod of the
+// agent, which the operator reflects on the DPU node.
+func (a *OvsAgent) RunSouthboundCheck(ctx context.Context, podName string, interval time.Duration) {
+	ovsLogger.Info("Start to check the southbound database connection", "interval", interval)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if utils.SimulateHardware {
+			return
+		}
+		condition := a.southboundCondition(ctx)
+		tenantNode, err := utils.GetMatchedTenantNode(a.NodeName)
+		if err != nil {
+			tenantNode = ""
+		}
+		southboundConnected.Reset()
+		if condition.Status == corev1.ConditionTrue {
+			southboundConnected.WithLabelValues(tenantNode).Set(1)
+		} else {
+			southboundConnected.WithLabelValues(tenantNode).Set(0)
+		}
+		changed, err := setPodCondition(ctx, a.Client, a.Namespace, podName, condition)
+		if err != nil {
+			ovsLogger.Error(err, "Fail to report the southbound database connection")
+		}
+		if changed && condition.Status != corev1.ConditionTrue {
+			ovsLogger.Info("ovn-controller is not connected to the southbound database", "reason", condition.Reason, "message", condition.Message)
+		}
+	}, interval)
+}
+
+// southboundCondition returns the condition of the connection of the
+// ovn-controller to the southbound database, queried with connection-status
+// on its unixctl socket
+func (a *OvsAgent) southboundCondition(ctx context.Context) corev1.PodCondition {
+	condition := corev1.PodCondition{Type: utils.OvnSouthboundPodCondition, Status: corev1.ConditionFalse}
+	pid, err := os.ReadFile(filepath.Join(a.HostRoot, ovnControllerPidFile))
+	if err != nil {
+		condition.Reason = "OvnControllerNotRunning"
+		condition.Message = err.Error()
+		return condition
+	}
+	target := fmt.Sprintf("/var/run/ovn/ovn-controller.%s.ctl", strings.TrimSpace(string(pid)))
+	out, err := a.appctl(ctx, "-t", target, "connection-status")
+	if err != nil {
+		condition.Reason = "OvnControllerNotResponding"
+		condition.Message = err.Error()
+		return condition
+	}
+	status := strings.TrimSpace(out)
+	if status != "connected" {
+		condition.Reason = "Disconnected"
+		condition.Message = "ovn-controller connection-status: " + status
+		return condition
+	}
+	condition.Status = corev1.ConditionTrue
+	condition.Reason = "Connected"
+	return condition
+}
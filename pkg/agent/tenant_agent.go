@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+var logger = ctrl.Log.WithName("tenant-agent")
+
+// tenantAgentFieldManager owns the annotation applied by the tenant agents
+const tenantAgentFieldManager = "dpu-tenant-agent"
+
+// TenantAgent publishes the serial number of the DPU installed in a tenant
+// worker as an annotation of its Node, so that the tenant node can be matched
+// with the DPU node of the infra cluster. As it runs on every worker, the
+// agent only writes the Node when the serial number changed or once per
+// Resync, at jittered times, so that hundreds of agents do not hit the API
+// server together.
+type TenantAgent struct {
+	client   client.Client
+	nodeName string
+	// DevicesDir is the sysfs PCI devices directory of the host
+	DevicesDir string
+	// Interval is the period at which the serial number is re-checked
+	Interval time.Duration
+	// Jitter is the fraction of Interval added at random to each period,
+	// and bounding the random delay of the first check
+	Jitter float64
+	// Resync is the period at which the annotation is applied even though
+	// the serial number did not change, to restore it if it was removed
+	Resync time.Duration
+
+	// published and publishedAt record the last serial number applied
+	published   string
+	publishedAt time.Time
+}
+
+func NewTenantAgent(cfg *rest.Config, nodeName string) (*TenantAgent, error) {
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &TenantAgent{
+		client:     c,
+		nodeName:   nodeName,
+		DevicesDir: utils.SysBusPciDevices,
+		Interval:   5 * time.Minute,
+		Jitter:     0.2,
+		Resync:     time.Hour,
+	}, nil
+}
+
+// Run publishes the serial number until ctx is done. The first check is
+// delayed at random, so that the agents of a rollout are spread over time.
+func (a *TenantAgent) Run(ctx context.Context) {
+	delay := time.Duration(rand.Float64() * a.Jitter * float64(a.Interval))
+	logger.Info("Start the tenant agent", "node", a.nodeName, "delay", delay)
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(delay):
+	}
+	wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+		if err := a.publishSerialNumber(ctx); err != nil {
+			logger.Error(err, "Fail to publish the DPU serial number", "node", a.nodeName)
+		}
+	}, a.Interval, a.Jitter, true)
+}
+
+func (a *TenantAgent) publishSerialNumber(ctx context.Context) error {
+	serial := utils.SimulatedDpuSerialNumber(a.nodeName)
+	if !utils.SimulateHardware {
+		var err error
+		if serial, err = utils.GetDpuSerialNumber(a.DevicesDir); err != nil {
+			return err
+		}
+	}
+	if serial == a.published && time.Since(a.publishedAt) < a.Resync {
+		return nil
+	}
+
+	// The annotation is server-side applied, without reading the Node
+	// first, and an unchanged annotation does not update the Node
+	node := &unstructured.Unstructured{}
+	node.SetAPIVersion("v1")
+	node.SetKind("Node")
+	node.SetName(a.nodeName)
+	node.SetAnnotations(map[string]string{utils.DpuSerialAnnotation: serial})
+	if err := a.client.Patch(ctx, node, client.Apply, client.FieldOwner(tenantAgentFieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+	if serial != a.published {
+		logger.Info("Published the DPU serial number", "node", a.nodeName, "serial", serial)
+	}
+	a.published = serial
+	a.publishedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// patchCounter records the patches of the Nodes instead of sending them
+type patchCounter struct {
+	client.Client
+	patches int
+}
+
+func (c *patchCounter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.patches++
+	return nil
+}
+
+// writeBluefieldDevice writes the sysfs files of a BlueField-2 PF whose VPD
+// holds the serial number
+func writeBluefieldDevice(t *testing.T, devicesDir, serial string) {
+	dir := filepath.Join(devicesDir, "0000:03:00.0")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ident := []byte("BlueField-2 DPU")
+	vpd := append([]byte{0x82, byte(len(ident)), 0}, ident...)
+	ro := append([]byte("SN"), byte(len(serial)))
+	ro = append(ro, serial...)
+	// The read-only resource holding the SN keyword, and the end tag
+	vpd = append(vpd, 0x90)
+	vpd = binary.LittleEndian.AppendUint16(vpd, uint16(len(ro)))
+	vpd = append(append(vpd, ro...), 0x78)
+	for name, content := range map[string][]byte{"vendor": []byte("0x15b3\n"), "device": []byte("0xa2d6\n"), "vpd": vpd} {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPublishSerialNumber(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	devicesDir := t.TempDir()
+	writeBluefieldDevice(t, devicesDir, "MT2000X00000")
+	c := &patchCounter{}
+	a := &TenantAgent{client: c, nodeName: "worker-0", DevicesDir: devicesDir, Interval: time.Minute, Resync: time.Hour}
+
+	g.Expect(a.publishSerialNumber(ctx)).To(Succeed())
+	g.Expect(c.patches).To(Equal(1))
+
+	// An unchanged serial number is not applied again before the resync
+	g.Expect(a.publishSerialNumber(ctx)).To(Succeed())
+	g.Expect(a.publishSerialNumber(ctx)).To(Succeed())
+	g.Expect(c.patches).To(Equal(1))
+
+	// A new serial number is applied right away
+	writeBluefieldDevice(t, devicesDir, "MT2000X00001")
+	g.Expect(a.publishSerialNumber(ctx)).To(Succeed())
+	g.Expect(c.patches).To(Equal(2))
+	g.Expect(a.published).To(Equal("MT2000X00001"))
+
+	// The annotation is applied again once per resync
+	a.publishedAt = time.Now().Add(-2 * time.Hour)
+	g.Expect(a.publishSerialNumber(ctx)).To(Succeed())
+	g.Expect(c.patches).To(Equal(3))
+}
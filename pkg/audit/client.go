@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+var logger = ctrl.Log.WithName("audit")
+
+// Client records every write performed through it against another cluster,
+// with the identity of the operator, the reason of the write and its target.
+// The reads are not recorded.
+type Client struct {
+	client.Client
+	// Cluster names the cluster written to, e.g. tenant
+	Cluster string
+	// Actor is the identity of the operator
+	Actor string
+	// Reason explains why the writes are performed
+	Reason string
+	// Regarding is the local object the writes are performed for, which
+	// the events are recorded on
+	Regarding runtime.Object
+	// Recorder records the writes as events when set
+	Recorder record.EventRecorder
+}
+
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	c.record("create", obj, err)
+	return err
+}
+
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+	c.record("update", obj, err)
+	return err
+}
+
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	c.record("patch", obj, err)
+	return err
+}
+
+func (c *Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	c.record("delete", obj, err)
+	return err
+}
+
+func (c *Client) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+	c.record("deletecollection", obj, err)
+	return err
+}
+
+// Status returns a writer recording the writes of the status subresource
+func (c *Client) Status() client.SubResourceWriter {
+	return &subResourceWriter{SubResourceWriter: c.Client.Status(), parent: c, subResource: "status"}
+}
+
+// SubResource returns a client recording the writes of the given subresource
+func (c *Client) SubResource(subResource string) client.SubResourceClient {
+	sc := c.Client.SubResource(subResource)
+	return &subResourceClient{
+		SubResourceReader: sc,
+		subResourceWriter: &subResourceWriter{SubResourceWriter: sc, parent: c, subResource: subResource},
+	}
+}
+
+// subResourceClient reads a subresource and records its writes
+type subResourceClient struct {
+	client.SubResourceReader
+	*subResourceWriter
+}
+
+// subResourceWriter records the writes of a subresource performed through
+// the parent client
+type subResourceWriter struct {
+	client.SubResourceWriter
+	parent      *Client
+	subResource string
+}
+
+func (w *subResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	err := w.SubResourceWriter.Create(ctx, obj, subResource, opts...)
+	w.parent.recordSubResource("create", w.subResource, obj, err)
+	return err
+}
+
+func (w *subResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	err := w.SubResourceWriter.Update(ctx, obj, opts...)
+	w.parent.recordSubResource("update", w.subResource, obj, err)
+	return err
+}
+
+func (w *subResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	err := w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+	w.parent.recordSubResource("patch", w.subResource, obj, err)
+	return err
+}
+
+func (c *Client) record(verb string, obj client.Object, err error) {
+	c.recordSubResource(verb, "", obj, err)
+}
+
+func (c *Client) recordSubResource(verb, subResource string, obj client.Object, err error) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, gvkErr := apiutil.GVKForObject(obj, c.Scheme()); gvkErr == nil {
+		kind = gvk.Kind
+	}
+	result := "succeeded"
+	if err != nil {
+		result = err.Error()
+	}
+	logger.Info("Cross-cluster write",
+		"cluster", c.Cluster,
+		"actor", c.Actor,
+		"verb", verb,
+		"kind", kind,
+		"subresource", subResource,
+		"namespace", obj.GetNamespace(),
+		"name", obj.GetName(),
+		"reason", c.Reason,
+		"result", result)
+
+	if c.Recorder == nil || c.Regarding == nil {
+		return
+	}
+	if subResource != "" {
+		kind = kind + "/" + subResource
+	}
+	eventType := corev1.EventTypeNormal
+	if err != nil {
+		eventType = corev1.EventTypeWarning
+	}
+	c.Recorder.Eventf(c.Regarding, eventType, "CrossClusterWrite", "%s %s %s/%s in the %s cluster: %s (%s)",
+		verb, kind, obj.GetNamespace(), obj.GetName(), c.Cluster, c.Reason, result)
+}
+
+// NewTenantClient returns a client recording the writes the operator performs
+// against the tenant cluster for the given reason
+func NewTenantClient(c client.Client, reason string, regarding runtime.Object, recorder record.EventRecorder) *Client {
+	return &Client{
+		Client:    c,
+		Cluster:   "tenant",
+		Actor:     fmt.Sprintf("system:serviceaccount:%s:%s", utils.Namespace, utils.ServiceAccount),
+		Reason:    reason,
+		Regarding: regarding,
+		Recorder:  recorder,
+	}
+}
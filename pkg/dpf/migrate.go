@@ -0,0 +1,165 @@
+package dpf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
+)
+
+const (
+	provisioningGroup = "provisioning.dpu.nvidia.com"
+	serviceGroup      = "svc.dpu.nvidia.com"
+	docaTelemetryName = "doca-telemetry"
+)
+
+// Options holds the settings of the DpuClusterConfigs that cannot be
+// derived from the DPF objects
+type Options struct {
+	// PoolName is the MachineConfigPool of the DPU nodes
+	PoolName string
+	// KubeConfigFile is the secret name of the tenant cluster kubeconfig
+	KubeConfigFile string
+	// Namespace overrides the namespace of the DPF objects when set
+	Namespace string
+}
+
+// ReadObjects decodes the multi-document YAML or JSON stream r
+func ReadObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
+	objs := []*unstructured.Unstructured{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bufio.NewReader(r), 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		if u.IsList() {
+			err := u.EachListItem(func(o runtime.Object) error {
+				objs = append(objs, o.(*unstructured.Unstructured))
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
+
+// Convert generates a DpuClusterConfig for each DPUSet found in objs.
+// DPUServices deploying the DOCA telemetry service in the namespace of a
+// DPUSet are mapped to its docaTelemetry. The returned warnings list the
+// settings that could not be migrated.
+func Convert(objs []*unstructured.Unstructured, opts Options) ([]*dpuv1alpha1.DpuClusterConfig, []string, error) {
+	cfgs := []*dpuv1alpha1.DpuClusterConfig{}
+	warnings := []string{}
+	telemetry := map[string]*dpuv1alpha1.DocaTelemetrySpec{}
+
+	for _, obj := range objs {
+		gv := obj.GroupVersionKind()
+		if gv.Group != serviceGroup || gv.Kind != "DPUService" {
+			continue
+		}
+		spec, w := convertDPUService(obj)
+		warnings = append(warnings, w...)
+		if spec != nil {
+			telemetry[obj.GetNamespace()] = spec
+		}
+	}
+
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		switch {
+		case gvk.Group == provisioningGroup && gvk.Kind == "DPUSet":
+			cfg, w, err := convertDPUSet(obj, opts)
+			if err != nil {
+				return nil, warnings, err
+			}
+			warnings = append(warnings, w...)
+			cfg.Spec.DocaTelemetry = telemetry[obj.GetNamespace()]
+			cfgs = append(cfgs, cfg)
+		case gvk.Group == serviceGroup && gvk.Kind == "DPUService":
+		default:
+			warnings = append(warnings, fmt.Sprintf("%s %s/%s: not supported, skipped", gvk.Kind, obj.GetNamespace(), obj.GetName()))
+		}
+	}
+	return cfgs, warnings, nil
+}
+
+func convertDPUSet(obj *unstructured.Unstructured, opts Options) (*dpuv1alpha1.DpuClusterConfig, []string, error) {
+	warnings := []string{}
+	ref := fmt.Sprintf("DPUSet %s/%s", obj.GetNamespace(), obj.GetName())
+
+	namespace := obj.GetNamespace()
+	if opts.Namespace != "" {
+		namespace = opts.Namespace
+	}
+	cfg := &dpuv1alpha1.DpuClusterConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: dpuv1alpha1.GroupVersion.String(),
+			Kind:       "DpuClusterConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: namespace,
+		},
+		Spec: dpuv1alpha1.DpuClusterConfigSpec{
+			PoolName:       opts.PoolName,
+			KubeConfigFile: opts.KubeConfigFile,
+		},
+	}
+
+	// The DPU nodes are labeled with the nodeLabels of the DPU template
+	nodeLabels, _, err := unstructured.NestedStringMap(obj.Object, "spec", "dpuTemplate", "spec", "cluster", "nodeLabels")
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: invalid nodeLabels: %v", ref, err)
+	}
+	if len(nodeLabels) > 0 {
+		cfg.Spec.NodeSelector = &metav1.LabelSelector{MatchLabels: nodeLabels}
+	} else {
+		warnings = append(warnings, fmt.Sprintf("%s: no DPU node labels, nodeSelector left empty", ref))
+	}
+
+	if bfb, found, _ := unstructured.NestedString(obj.Object, "spec", "dpuTemplate", "spec", "bfb", "name"); found {
+		warnings = append(warnings, fmt.Sprintf("%s: BFB %q is not managed by this operator", ref, bfb))
+	}
+	if flavor, found, _ := unstructured.NestedString(obj.Object, "spec", "dpuTemplate", "spec", "dpuFlavor"); found {
+		warnings = append(warnings, fmt.Sprintf("%s: DPUFlavor %q is not managed by this operator", ref, flavor))
+	}
+	return cfg, warnings, nil
+}
+
+func convertDPUService(obj *unstructured.Unstructured) (*dpuv1alpha1.DocaTelemetrySpec, []string) {
+	ref := fmt.Sprintf("DPUService %s/%s", obj.GetNamespace(), obj.GetName())
+	chart, _, _ := unstructured.NestedString(obj.Object, "spec", "helmChart", "source", "chart")
+	if !strings.Contains(chart, docaTelemetryName) && !strings.Contains(obj.GetName(), docaTelemetryName) {
+		return nil, []string{fmt.Sprintf("%s: chart %q not supported, skipped", ref, chart)}
+	}
+
+	repository, _, _ := unstructured.NestedString(obj.Object, "spec", "helmChart", "values", "image", "repository")
+	tag, _, _ := unstructured.NestedString(obj.Object, "spec", "helmChart", "values", "image", "tag")
+	if repository == "" {
+		return nil, []string{fmt.Sprintf("%s: no image in the chart values, docaTelemetry not migrated", ref)}
+	}
+	image := repository
+	if tag != "" {
+		image = repository + ":" + tag
+	}
+	return &dpuv1alpha1.DocaTelemetrySpec{Image: image}, nil
+}
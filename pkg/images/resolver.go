@@ -0,0 +1,460 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+var logger = ctrl.Log.WithName("image-resolver")
+
+const (
+	dockerHub         = "docker.io"
+	dockerHubEndpoint = "registry-1.docker.io"
+
+	cacheTTL      = time.Hour
+	errorCacheTTL = 5 * time.Minute
+	// maxBodySize bounds the manifests and image configs read
+	maxBodySize = 4 << 20
+)
+
+var (
+	imageDigestMirrorSetGVK = schema.GroupVersionKind{
+		Group:   "config.openshift.io",
+		Version: "v1",
+		Kind:    "ImageDigestMirrorSet",
+	}
+	imageContentSourcePolicyGVK = schema.GroupVersionKind{
+		Group:   "operator.openshift.io",
+		Version: "v1alpha1",
+		Kind:    "ImageContentSourcePolicy",
+	}
+	challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	pullSecret     = types.NamespacedName{Namespace: "openshift-config", Name: "pull-secret"}
+
+	manifestMediaTypes = []string{
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}
+)
+
+// Resolver pins image tags to the digests they point to, so that the pod
+// specs are reproducible and the pulls are redirected by the digest mirrors
+// of disconnected clusters. The digests are looked up on the mirrors of the
+// ImageDigestMirrorSets and ImageContentSourcePolicies first.
+type Resolver struct {
+	Reader     client.Reader
+	Platform   *utils.Platform
+	HTTPClient *http.Client
+
+	mu        sync.Mutex
+	cache     map[string]cachedImage
+	archCache map[string]cachedArchitectures
+}
+
+type cachedImage struct {
+	image   string
+	err     error
+	expires time.Time
+}
+
+type cachedArchitectures struct {
+	architectures []string
+	err           error
+	expires       time.Time
+}
+
+func NewResolver(reader client.Reader, platform *utils.Platform) *Resolver {
+	return &Resolver{
+		Reader:     reader,
+		Platform:   platform,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      map[string]cachedImage{},
+		archCache:  map[string]cachedArchitectures{},
+	}
+}
+
+// Resolve returns image pinned to the digest of its tag. Images pinned
+// already are returned as is. On failure, image is returned as is along with
+// the error.
+func (r *Resolver) Resolve(ctx context.Context, image string) (string, error) {
+	if strings.Contains(image, "@") {
+		return image, nil
+	}
+
+	r.mu.Lock()
+	c, ok := r.cache[image]
+	r.mu.Unlock()
+	if ok && time.Now().Before(c.expires) {
+		if c.err != nil {
+			return image, c.err
+		}
+		return c.image, nil
+	}
+
+	resolved, err := r.resolve(ctx, image)
+	c = cachedImage{image: resolved, err: err, expires: time.Now().Add(cacheTTL)}
+	if err != nil {
+		c.expires = time.Now().Add(errorCacheTTL)
+	}
+	r.mu.Lock()
+	r.cache[image] = c
+	r.mu.Unlock()
+	if err != nil {
+		return image, err
+	}
+	return resolved, nil
+}
+
+func (r *Resolver) resolve(ctx context.Context, image string) (string, error) {
+	name, tag := splitTag(image)
+	auths, err := r.getPullSecretAuths(ctx)
+	if err != nil {
+		logger.Error(err, "Fail to read the cluster pull secret, resolving anonymously")
+	}
+	candidates, err := r.getCandidates(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		digest, err := r.getDigest(ctx, candidate, tag, auths)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// The source name is kept, the runtime redirects the pull of the
+		// digest to the mirrors
+		return name + "@" + digest, nil
+	}
+	return "", fmt.Errorf("failed to resolve %s: %v", image, lastErr)
+}
+
+// getCandidates returns the repositories the image name can be pulled from.
+// The mirrors are first, as the source may not be reachable from a
+// disconnected cluster.
+func (r *Resolver) getCandidates(ctx context.Context, name string) ([]string, error) {
+	mirrors, err := r.getMirrors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []string{}
+	for source, sourceMirrors := range mirrors {
+		if name == source || strings.HasPrefix(name, source+"/") {
+			for _, m := range sourceMirrors {
+				candidates = append(candidates, m+strings.TrimPrefix(name, source))
+			}
+		}
+	}
+	return append(candidates, name), nil
+}
+
+// Architectures returns the CPU architectures image is available for, read
+// from its manifest list, or from its config for a single-architecture image
+func (r *Resolver) Architectures(ctx context.Context, image string) ([]string, error) {
+	r.mu.Lock()
+	c, ok := r.archCache[image]
+	r.mu.Unlock()
+	if ok && time.Now().Before(c.expires) {
+		return c.architectures, c.err
+	}
+
+	architectures, err := r.getArchitectures(ctx, image)
+	c = cachedArchitectures{architectures: architectures, err: err, expires: time.Now().Add(cacheTTL)}
+	if err != nil {
+		c.expires = time.Now().Add(errorCacheTTL)
+	}
+	r.mu.Lock()
+	r.archCache[image] = c
+	r.mu.Unlock()
+	return architectures, err
+}
+
+func (r *Resolver) getArchitectures(ctx context.Context, image string) ([]string, error) {
+	name, reference := splitTag(image)
+	if i := strings.Index(image, "@"); i >= 0 {
+		name, reference = image[:i], image[i+1:]
+	}
+	auths, err := r.getPullSecretAuths(ctx)
+	if err != nil {
+		logger.Error(err, "Fail to read the cluster pull secret, reading anonymously")
+	}
+	candidates, err := r.getCandidates(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		manifest := struct {
+			Manifests []struct {
+				Platform struct {
+					Architecture string `json:"architecture"`
+				} `json:"platform"`
+			} `json:"manifests"`
+			Config struct {
+				Digest string `json:"digest"`
+			} `json:"config"`
+		}{}
+		if lastErr = r.getJSON(ctx, candidate, "manifests/"+reference, auths, &manifest); lastErr != nil {
+			continue
+		}
+		if len(manifest.Manifests) > 0 {
+			architectures := []string{}
+			for _, m := range manifest.Manifests {
+				architectures = append(architectures, m.Platform.Architecture)
+			}
+			return architectures, nil
+		}
+		config := struct {
+			Architecture string `json:"architecture"`
+		}{}
+		if lastErr = r.getJSON(ctx, candidate, "blobs/"+manifest.Config.Digest, auths, &config); lastErr != nil {
+			continue
+		}
+		return []string{config.Architecture}, nil
+	}
+	return nil, fmt.Errorf("failed to read the architectures of %s: %v", image, lastErr)
+}
+
+// splitTag splits image into its name and tag, latest by default
+func splitTag(image string) (string, string) {
+	i := strings.LastIndex(image, ":")
+	if i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
+
+// splitRegistry splits an image name into the registry host and the
+// repository
+func splitRegistry(name string) (string, string) {
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0], parts[1]
+	}
+	if len(parts) == 1 {
+		return dockerHub, "library/" + name
+	}
+	return dockerHub, name
+}
+
+// getMirrors returns the mirrors of each source repository
+func (r *Resolver) getMirrors(ctx context.Context) (map[string][]string, error) {
+	mirrors := map[string][]string{}
+	for _, m := range []struct {
+		gvk   schema.GroupVersionKind
+		field string
+	}{
+		{imageDigestMirrorSetGVK, "imageDigestMirrors"},
+		{imageContentSourcePolicyGVK, "repositoryDigestMirrors"},
+	} {
+		if !r.Platform.Serves(m.gvk) {
+			continue
+		}
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(m.gvk.GroupVersion().WithKind(m.gvk.Kind + "List"))
+		if err := r.Reader.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			entries, _, _ := unstructured.NestedSlice(item.Object, "spec", m.field)
+			for _, e := range entries {
+				entry, ok := e.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				source, _, _ := unstructured.NestedString(entry, "source")
+				sourceMirrors, _, _ := unstructured.NestedStringSlice(entry, "mirrors")
+				mirrors[source] = append(mirrors[source], sourceMirrors...)
+			}
+		}
+	}
+	return mirrors, nil
+}
+
+// getPullSecretAuths returns the base64 encoded credentials of each registry
+// found in the cluster pull secret
+func (r *Resolver) getPullSecretAuths(ctx context.Context) (map[string]string, error) {
+	auths := map[string]string{}
+	s := &corev1.Secret{}
+	if err := r.Reader.Get(ctx, pullSecret, s); err != nil {
+		return auths, client.IgnoreNotFound(err)
+	}
+	config := struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(s.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+		return auths, err
+	}
+	for registry, a := range config.Auths {
+		auths[registry] = a.Auth
+	}
+	return auths, nil
+}
+
+// getDigest returns the digest of the manifest of name:tag
+func (r *Resolver) getDigest(ctx context.Context, name, tag string, auths map[string]string) (string, error) {
+	resp, _, err := r.request(ctx, http.MethodHead, name, "manifests/"+tag, auths)
+	if err != nil {
+		return "", err
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("no digest returned for %s:%s", name, tag)
+	}
+	return digest, nil
+}
+
+// getJSON decodes the manifest or the blob at path of the repository name
+func (r *Resolver) getJSON(ctx context.Context, name, path string, auths map[string]string, v interface{}) error {
+	_, body, err := r.request(ctx, http.MethodGet, name, path, auths)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// request sends a request to path of the repository name on its registry,
+// answering the authentication challenge of the registry, and returns the
+// response along with its body
+func (r *Resolver) request(ctx context.Context, method, name, path string, auths map[string]string) (*http.Response, []byte, error) {
+	registry, repository := splitRegistry(name)
+	auth := auths[registry]
+	endpoint := registry
+	if registry == dockerHub {
+		endpoint = dockerHubEndpoint
+		if auth == "" {
+			auth = auths["https://index.docker.io/v1/"]
+		}
+	}
+	requestURL := fmt.Sprintf("https://%s/v2/%s/%s", endpoint, repository, path)
+
+	resp, body, err := r.do(ctx, method, requestURL, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := r.authorize(ctx, resp.Header.Get("WWW-Authenticate"), auth)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp, body, err = r.do(ctx, method, requestURL, authorization); err != nil {
+			return nil, nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status %s for %s", resp.Status, requestURL)
+	}
+	return resp, body, nil
+}
+
+func (r *Resolver) do(ctx context.Context, method, requestURL, authorization string) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := r.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
+
+// authorize answers the authentication challenge of a registry, returning
+// the value of the Authorization header
+func (r *Resolver) authorize(ctx context.Context, challenge, auth string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if auth == "" {
+			return "", fmt.Errorf("no credentials for basic authentication")
+		}
+		return "Basic " + auth, nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", err
+	}
+	q := tokenURL.Query()
+	for _, p := range []string{"service", "scope"} {
+		if params[p] != "" {
+			q.Set(p, params[p])
+		}
+	}
+	tokenURL.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if auth != "" {
+		if _, err := base64.StdEncoding.DecodeString(auth); err == nil {
+			req.Header.Set("Authorization", "Basic "+auth)
+		}
+	}
+	resp, err := r.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s for %s", resp.Status, tokenURL.Redacted())
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header such as
+// Bearer realm="https://auth.example.com/token",service="registry"
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	for _, m := range challengeParam.FindAllStringSubmatch(rest, -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+	return scheme, params
+}
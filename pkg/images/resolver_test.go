@@ -0,0 +1,243 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/dpu-network-operator/pkg/utils"
+)
+
+const (
+	testDigest       = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	testConfigDigest = "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	testToken        = "registry-token"
+)
+
+var testAuth = base64.StdEncoding.EncodeToString([]byte("user:password"))
+
+// testRegistry is a registry serving the manifests of its repositories,
+// behind a bearer token authentication
+type testRegistry struct {
+	*httptest.Server
+	// manifests are the manifests of each repository:reference
+	manifests map[string]interface{}
+	// configs are the image configs of each repository:digest
+	configs map[string]interface{}
+
+	mu       sync.Mutex
+	requests int
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	reg := &testRegistry{manifests: map[string]interface{}{}, configs: map[string]interface{}{}}
+	reg.Server = httptest.NewTLSServer(http.HandlerFunc(reg.serve))
+	t.Cleanup(reg.Close)
+	return reg
+}
+
+// host returns the registry host, such as 127.0.0.1:34567
+func (reg *testRegistry) host() string {
+	return strings.TrimPrefix(reg.URL, "https://")
+}
+
+func (reg *testRegistry) requestCount() int {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	return reg.requests
+}
+
+func (reg *testRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	reg.mu.Lock()
+	reg.requests++
+	reg.mu.Unlock()
+
+	if req.URL.Path == "/token" {
+		if req.Header.Get("Authorization") != "Basic "+testAuth {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": testToken})
+		return
+	}
+	if req.Header.Get("Authorization") != "Bearer "+testToken {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, reg.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	var obj interface{}
+	var ok bool
+	if i := strings.Index(path, "/manifests/"); i >= 0 {
+		obj, ok = reg.manifests[path[:i]+":"+path[i+len("/manifests/"):]]
+		w.Header().Set("Docker-Content-Digest", testDigest)
+	} else if i := strings.Index(path, "/blobs/"); i >= 0 {
+		obj, ok = reg.configs[path[:i]+":"+path[i+len("/blobs/"):]]
+	}
+	if !ok {
+		w.Header().Del("Docker-Content-Digest")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if req.Method == http.MethodHead {
+		return
+	}
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+// newTestResolver returns a resolver trusting the registry, reading the
+// cluster pull secret and objs
+func newTestResolver(reg *testRegistry, objs ...client.Object) *Resolver {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	dockerConfig, _ := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{reg.host(): map[string]string{"auth": testAuth}},
+	})
+	objs = append(objs, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: pullSecret.Namespace, Name: pullSecret.Name},
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig},
+	})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	r := NewResolver(c, utils.NewPlatform(imageDigestMirrorSetGVK.GroupVersion().String()))
+	r.HTTPClient = reg.Client()
+	return r
+}
+
+// newTestMirrorSet returns an ImageDigestMirrorSet mirroring source to mirror
+func newTestMirrorSet(source, mirror string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"imageDigestMirrors": []interface{}{
+				map[string]interface{}{"source": source, "mirrors": []interface{}{mirror}},
+			},
+		},
+	}}
+	obj.SetGroupVersionKind(imageDigestMirrorSetGVK)
+	obj.SetName("mirrors")
+	return obj
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	reg := newTestRegistry(t)
+	reg.manifests["dpu/ovnkube:4.14"] = map[string]interface{}{}
+	reg.manifests["mirror/ovnkube:4.14"] = map[string]interface{}{}
+
+	tests := []struct {
+		name     string
+		image    string
+		objs     []client.Object
+		resolved string
+		err      bool
+		requests int
+	}{
+		{
+			name:     "digest found",
+			image:    reg.host() + "/dpu/ovnkube:4.14",
+			resolved: reg.host() + "/dpu/ovnkube@" + testDigest,
+			// The manifest is requested again with the token
+			requests: 3,
+		},
+		{
+			// The source is not reachable, only its mirror is
+			name:     "digest found on the mirror",
+			image:    "source.invalid/dpu/ovnkube:4.14",
+			objs:     []client.Object{newTestMirrorSet("source.invalid/dpu", reg.host()+"/mirror")},
+			resolved: "source.invalid/dpu/ovnkube@" + testDigest,
+			requests: 3,
+		},
+		{
+			name:     "image not found",
+			image:    reg.host() + "/dpu/ovnkube:4.15",
+			err:      true,
+			requests: 3,
+		},
+		{
+			name:     "image pinned already",
+			image:    reg.host() + "/dpu/ovnkube@" + testDigest,
+			resolved: reg.host() + "/dpu/ovnkube@" + testDigest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newTestResolver(reg, tt.objs...)
+			before := reg.requestCount()
+
+			// The second resolution, or failure, is read from the cache
+			for i := 0; i < 2; i++ {
+				resolved, err := r.Resolve(ctx, tt.image)
+				if tt.err {
+					g.Expect(err).To(HaveOccurred())
+					g.Expect(resolved).To(Equal(tt.image))
+				} else {
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(resolved).To(Equal(tt.resolved))
+				}
+			}
+			g.Expect(reg.requestCount() - before).To(Equal(tt.requests))
+		})
+	}
+}
+
+func TestArchitectures(t *testing.T) {
+	ctx := context.Background()
+	reg := newTestRegistry(t)
+	reg.manifests["dpu/multiarch:4.14"] = map[string]interface{}{
+		"manifests": []interface{}{
+			map[string]interface{}{"platform": map[string]interface{}{"architecture": "amd64"}},
+			map[string]interface{}{"platform": map[string]interface{}{"architecture": "arm64"}},
+		},
+	}
+	reg.manifests["dpu/amd64:4.14"] = map[string]interface{}{
+		"config": map[string]interface{}{"digest": testConfigDigest},
+	}
+	reg.manifests["dpu/amd64:"+testDigest] = reg.manifests["dpu/amd64:4.14"]
+	reg.configs["dpu/amd64:"+testConfigDigest] = map[string]interface{}{"architecture": "amd64"}
+
+	tests := []struct {
+		name          string
+		image         string
+		architectures []string
+		err           bool
+	}{
+		{name: "manifest list", image: reg.host() + "/dpu/multiarch:4.14", architectures: []string{"amd64", "arm64"}},
+		// The image cannot run on the DPUs
+		{name: "architecture mismatch", image: reg.host() + "/dpu/amd64:4.14", architectures: []string{"amd64"}},
+		{name: "pinned image", image: reg.host() + "/dpu/amd64@" + testDigest, architectures: []string{"amd64"}},
+		{name: "image not found", image: reg.host() + "/dpu/missing:4.14", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newTestResolver(reg)
+			architectures, err := r.Architectures(ctx, tt.image)
+			if tt.err {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(architectures).To(Equal(tt.architectures))
+			if len(tt.architectures) == 1 {
+				g.Expect(architectures).NotTo(ContainElement(utils.DpuArchitecture))
+			}
+		})
+	}
+}
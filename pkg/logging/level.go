@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/klog/v2"
+	crzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// Level is the verbosity of the operator logs. It can be changed at runtime,
+// and applies to the controller-runtime logger, which the admiral syncers log
+// to, as well as to klog, which client-go logs to.
+//
+// The verbosity follows the logr convention: 0 logs the informational
+// messages, higher values enable the debug messages logged with V(n).
+type Level struct {
+	atomic zap.AtomicLevel
+	mu     sync.Mutex
+	klog   *flag.FlagSet
+}
+
+// NewLevel returns the level configured by the zap options, i.e. by the
+// --zap-log-level flag, and makes the options use it.
+func NewLevel(opts *crzap.Options) *Level {
+	l := &Level{
+		atomic: zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		klog:   flag.NewFlagSet("klog", flag.ContinueOnError),
+	}
+	if opts.Development {
+		l.atomic.SetLevel(zapcore.DebugLevel)
+	}
+	if configured, ok := opts.Level.(zap.AtomicLevel); ok {
+		l.atomic.SetLevel(configured.Level())
+	}
+	klog.InitFlags(l.klog)
+	l.SetVerbosity(l.Verbosity())
+	opts.Level = l
+	return l
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (l *Level) Enabled(lvl zapcore.Level) bool {
+	return l.atomic.Enabled(lvl)
+}
+
+// Verbosity returns the current verbosity
+func (l *Level) Verbosity() int {
+	return -int(l.atomic.Level())
+}
+
+// SetVerbosity changes the verbosity of the operator and klog logs
+func (l *Level) SetVerbosity(v int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.atomic.SetLevel(zapcore.Level(-v))
+	if v < 0 {
+		v = 0
+	}
+	_ = l.klog.Set("v", strconv.Itoa(v))
+}
+
+type levelPayload struct {
+	Verbosity *int `json:"verbosity"`
+}
+
+// ServeHTTP returns the current verbosity on GET, and changes it on PUT,
+// e.g. with {"verbosity": 2}
+func (l *Level) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		payload := levelPayload{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, fmt.Sprintf("invalid body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if payload.Verbosity == nil {
+			http.Error(w, "expected a body like {\"verbosity\": 2}", http.StatusBadRequest)
+			return
+		}
+		l.SetVerbosity(*payload.Verbosity)
+	default:
+		http.Error(w, "only GET and PUT are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	v := l.Verbosity()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(levelPayload{Verbosity: &v})
+}
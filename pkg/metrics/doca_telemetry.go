@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	docaTelemetryPrefix   = "dpu_doca_"
+	docaTelemetryAppLabel = "doca-telemetry"
+	docaTelemetryPortName = "metrics"
+	scrapeTimeout         = 5 * time.Second
+)
+
+var logger = logf.Log.WithName("metrics")
+
+// DocaTelemetryCollector forwards the counters exported by the DOCA telemetry
+// service pods to the operator metrics endpoint. The metric names are
+// prefixed with dpu_doca_ and every sample is labeled with the DPU node it
+// comes from.
+type DocaTelemetryCollector struct {
+	// Reader lists the DOCA telemetry pods
+	Reader     client.Reader
+	HTTPClient *http.Client
+}
+
+func NewDocaTelemetryCollector(reader client.Reader) *DocaTelemetryCollector {
+	return &DocaTelemetryCollector{
+		Reader:     reader,
+		HTTPClient: &http.Client{Timeout: scrapeTimeout},
+	}
+}
+
+// Describe sends no descriptor, the forwarded metrics are only known once
+// they are scraped.
+func (c *DocaTelemetryCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c *DocaTelemetryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+	pods := &corev1.PodList{}
+	if err := c.Reader.List(ctx, pods, client.MatchingLabels{"app": docaTelemetryAppLabel}); err != nil {
+		logger.Error(err, "failed to list the DOCA telemetry pods")
+		return
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		port := podPort(pod, docaTelemetryPortName)
+		if port == 0 {
+			logger.Error(fmt.Errorf("no %s port", docaTelemetryPortName), "failed to scrape the DOCA telemetry pod", "pod", pod.Name, "namespace", pod.Namespace)
+			continue
+		}
+		families, err := scrape(c.HTTPClient, pod, port)
+		if err != nil {
+			logger.Error(err, "failed to scrape the DOCA telemetry pod", "pod", pod.Name, "namespace", pod.Namespace)
+			continue
+		}
+		for _, mf := range families {
+			forward(ch, mf, docaTelemetryPrefix, pod.Spec.NodeName)
+		}
+	}
+}
+
+// podPort returns the port of pod named name, 0 when it has none
+func podPort(pod *corev1.Pod, name string) int32 {
+	for _, container := range pod.Spec.Containers {
+		for _, p := range container.Ports {
+			if p.Name == name {
+				return p.ContainerPort
+			}
+		}
+	}
+	return 0
+}
+
+// scrape reads the metrics exported on port of pod
+func scrape(httpClient *http.Client, pod *corev1.Pod, port int32) (map[string]*dto.MetricFamily, error) {
+	url := "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port))) + "/metrics"
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+// forward sends the counters and gauges of mf to ch with their names
+// prefixed, histograms and summaries are not forwarded.
+func forward(ch chan<- prometheus.Metric, mf *dto.MetricFamily, prefix, nodeName string) {
+	var valueType prometheus.ValueType
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		valueType = prometheus.CounterValue
+	case dto.MetricType_GAUGE:
+		valueType = prometheus.GaugeValue
+	case dto.MetricType_UNTYPED:
+		valueType = prometheus.UntypedValue
+	default:
+		return
+	}
+	for _, m := range mf.GetMetric() {
+		labelNames := []string{"dpu_node"}
+		labelValues := []string{nodeName}
+		for _, l := range m.GetLabel() {
+			labelNames = append(labelNames, l.GetName())
+			labelValues = append(labelValues, l.GetValue())
+		}
+		var value float64
+		switch valueType {
+		case prometheus.CounterValue:
+			value = m.GetCounter().GetValue()
+		case prometheus.GaugeValue:
+			value = m.GetGauge().GetValue()
+		default:
+			value = m.GetUntyped().GetValue()
+		}
+		desc := prometheus.NewDesc(prefix+mf.GetName(), mf.GetHelp(), labelNames, nil)
+		metric, err := prometheus.NewConstMetric(desc, valueType, value, labelValues...)
+		if err != nil {
+			logger.Error(err, "failed to forward metric", "name", mf.GetName())
+			continue
+		}
+		ch <- metric
+	}
+}
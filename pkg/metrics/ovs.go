@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ovsMetricsPrefix = "dpu_"
+	// OvsMetricsPortName is the name of the port of the ovnkube-node pods of
+	// the DPU nodes exporting the OVS and ovn-controller metrics. The
+	// ovnkube-node pods of the infra cluster itself have no such port.
+	OvsMetricsPortName = "dpu-ovs-metrics"
+	// OvsAgentPortName is the name of the port of the OVS agent pods
+	// exporting the datapath flows and conntrack statistics
+	OvsAgentPortName = "dpu-ovs-stats"
+)
+
+// ovsMetricsSources are the app label and the port name of the pods whose
+// metrics are forwarded by the OvsMetricsCollector
+var ovsMetricsSources = []struct {
+	app  string
+	port string
+}{
+	{app: "ovnkube-node", port: OvsMetricsPortName},
+	{app: "dpu-ovs-agent", port: OvsAgentPortName},
+}
+
+// OvsMetricsCollector forwards the OVS and ovn-controller metrics exported by
+// the ovnkube-node pods of the DPU nodes to the operator metrics endpoint,
+// e.g. the datapath flows, the hardware offload and the ovn-controller
+// statistics, along with the statistics sampled by the OVS agent. The metric
+// names are prefixed with dpu_ and every sample is labeled with the DPU node
+// it comes from.
+type OvsMetricsCollector struct {
+	// Reader lists the ovnkube-node and OVS agent pods
+	Reader     client.Reader
+	HTTPClient *http.Client
+}
+
+func NewOvsMetricsCollector(reader client.Reader) *OvsMetricsCollector {
+	return &OvsMetricsCollector{
+		Reader:     reader,
+		HTTPClient: &http.Client{Timeout: scrapeTimeout},
+	}
+}
+
+// Describe sends no descriptor, the forwarded metrics are only known once
+// they are scraped.
+func (c *OvsMetricsCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c *OvsMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, source := range ovsMetricsSources {
+		c.collect(ch, source.app, source.port)
+	}
+}
+
+func (c *OvsMetricsCollector) collect(ch chan<- prometheus.Metric, app, portName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+	pods := &corev1.PodList{}
+	if err := c.Reader.List(ctx, pods, client.MatchingLabels{"app": app}); err != nil {
+		logger.Error(err, "failed to list the pods", "app", app)
+		return
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		port := podPort(pod, portName)
+		if port == 0 {
+			continue
+		}
+		families, err := scrape(c.HTTPClient, pod, port)
+		if err != nil {
+			logger.Error(err, "failed to scrape the OVS metrics", "pod", pod.Name, "namespace", pod.Namespace)
+			continue
+		}
+		for _, mf := range families {
+			forward(ch, mf, ovsMetricsPrefix, pod.Spec.NodeName)
+		}
+	}
+}
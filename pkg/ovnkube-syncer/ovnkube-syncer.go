@@ -1,7 +1,10 @@
 package ovnkubesyncer
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	resourceSyncer "github.com/submariner-io/admiral/pkg/syncer"
@@ -14,13 +17,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
-	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	dpuv1alpha1 "github.com/openshift/dpu-network-operator/api/v1alpha1"
 	"github.com/openshift/dpu-network-operator/pkg/utils"
 )
 
+var logger = ctrl.Log.WithName("ovnkube-syncer")
+
 type SyncerConfig struct {
 	// LocalRestConfig the REST config used to access the local resources to sync.
 	LocalRestConfig *rest.Config
@@ -39,8 +43,8 @@ type SyncerConfig struct {
 	// TenantRestConfig the REST config used to access the broker resources to sync.
 	TenantRestConfig *rest.Config
 
-	// TenantClient the client used to access local resources to sync. This is optional and is provided for unit testing
-	// in lieu of the TenantRestConfig. If not specified, one is created from the TenantRestConfig.
+	// TenantClient the client used to access tenant resources to sync. This is optional and lets the operator share
+	// its tenant client with the syncer, or the unit tests provide one. If not specified, one is created from the TenantRestConfig.
 	TenantClient dynamic.Interface
 
 	// TenantNamespace the namespace in the broker to which resources from the local source will be synced.
@@ -56,6 +60,11 @@ type OvnkubeSyncer struct {
 	syncerConfig    SyncerConfig
 	owner           *dpuv1alpha1.DpuClusterConfig
 	scheme          *runtime.Scheme
+
+	mu sync.Mutex
+	// synced records the tenant version of the mirrored objects, keyed by
+	// kind and name
+	synced map[string]dpuv1alpha1.SyncedObjectStatus
 }
 
 func New(config SyncerConfig, owner *dpuv1alpha1.DpuClusterConfig, scheme *runtime.Scheme) (*OvnkubeSyncer, error) {
@@ -84,6 +93,7 @@ func New(config SyncerConfig, owner *dpuv1alpha1.DpuClusterConfig, scheme *runti
 		syncerConfig: config,
 		owner:        owner,
 		scheme:       scheme,
+		synced:       map[string]dpuv1alpha1.SyncedObjectStatus{},
 	}
 
 	return syncer, nil
@@ -91,7 +101,7 @@ func New(config SyncerConfig, owner *dpuv1alpha1.DpuClusterConfig, scheme *runti
 
 func (s *OvnkubeSyncer) Start(stopCh <-chan struct{}) error {
 	var err error
-	klog.Info("Starting the ovnkube syncer")
+	logger.Info("Starting the ovnkube syncer")
 	waitForCacheSync := true
 
 	s.SecretSyncer, err = resourceSyncer.NewResourceSyncer(&resourceSyncer.ResourceSyncerConfig{
@@ -128,20 +138,20 @@ func (s *OvnkubeSyncer) Start(stopCh <-chan struct{}) error {
 		return err
 	}
 
-	klog.Info("Starting serviceaccount syncer")
+	logger.Info("Starting serviceaccount syncer")
 
-	klog.Info("Starting secret syncer")
+	logger.Info("Starting secret syncer")
 	err = s.SecretSyncer.Start(stopCh)
 	if err != nil {
 		return err
 	}
-	klog.Info("Starting configmap syncer")
+	logger.Info("Starting configmap syncer")
 	err = s.ConfigmapSyncer.Start(stopCh)
 	if err != nil {
 		return err
 	}
 
-	klog.Info("ovnkube syncer started")
+	logger.Info("ovnkube syncer started")
 
 	return nil
 }
@@ -151,6 +161,7 @@ func (s *OvnkubeSyncer) shouldSyncSecret(obj runtime.Object, numRequeues int, op
 	secret.Namespace = s.syncerConfig.LocalNamespace
 	switch secret.Name {
 	case utils.SecretNameOvnCert:
+		s.recordSync("Secret", secret.Name, secret.ResourceVersion, secret.Data)
 		// clear owner
 		secret.OwnerReferences = []metav1.OwnerReference{}
 		if err := ctrl.SetControllerReference(s.owner, secret, s.scheme); err != nil {
@@ -165,7 +176,15 @@ func (s *OvnkubeSyncer) shouldSyncConfigMap(obj runtime.Object, numRequeues int,
 	cm := obj.(*corev1.ConfigMap)
 	cm.Namespace = s.syncerConfig.LocalNamespace
 	switch cm.Name {
-	case utils.CmNameOvnCa, utils.CmNameOvnkubeConfig:
+	case utils.CmNameOvnCa, utils.CmNameOvnkubeConfig, utils.CmNameSignerCa:
+		data := map[string][]byte{}
+		for k, v := range cm.Data {
+			data[k] = []byte(v)
+		}
+		for k, v := range cm.BinaryData {
+			data[k] = v
+		}
+		s.recordSync("ConfigMap", cm.Name, cm.ResourceVersion, data)
 		// clear owner
 		cm.OwnerReferences = []metav1.OwnerReference{}
 		if err := ctrl.SetControllerReference(s.owner, cm, s.scheme); err != nil {
@@ -175,3 +194,50 @@ func (s *OvnkubeSyncer) shouldSyncConfigMap(obj runtime.Object, numRequeues int,
 	}
 	return nil, false
 }
+
+// recordSync records the version of a tenant object being mirrored. The sync
+// time is only updated when the tenant object changed.
+func (s *OvnkubeSyncer) recordSync(kind, name, resourceVersion string, data map[string][]byte) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write(data[k])
+	}
+	hash := fmt.Sprintf("%x", h.Sum(nil))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := kind + "/" + name
+	if prev, ok := s.synced[key]; ok && prev.ResourceVersion == resourceVersion && prev.Hash == hash {
+		return
+	}
+	s.synced[key] = dpuv1alpha1.SyncedObjectStatus{
+		Kind:            kind,
+		Name:            name,
+		ResourceVersion: resourceVersion,
+		Hash:            hash,
+		LastSyncTime:    metav1.Now(),
+	}
+}
+
+// SyncedObjects returns the versions of the mirrored objects, sorted by kind
+// and name
+func (s *OvnkubeSyncer) SyncedObjects() []dpuv1alpha1.SyncedObjectStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.synced))
+	for k := range s.synced {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	objs := make([]dpuv1alpha1.SyncedObjectStatus, 0, len(keys))
+	for _, k := range keys {
+		objs = append(objs, s.synced[k])
+	}
+	return objs
+}
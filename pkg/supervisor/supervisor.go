@@ -0,0 +1,142 @@
+package supervisor
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// crashLoopThreshold is the number of consecutive failures after which a task
+// is reported as crash looping
+const crashLoopThreshold = 3
+
+// The delays are variables so that the tests can shorten them, they are read
+// when the task starts
+var (
+	initialBackoff = time.Second
+	maxBackoff     = 5 * time.Minute
+	// stablePeriod is how long a run must last for the previous failures
+	// to be forgotten
+	stablePeriod = 10 * time.Minute
+)
+
+var logger = ctrl.Log.WithName("supervisor")
+
+var taskRestarts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "dpu_operator_background_task_restarts_total",
+		Help: "Number of restarts of the background tasks of the operator after a failure or a panic.",
+	},
+	[]string{"task"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(taskRestarts)
+}
+
+// Task is a background task run by Go. A run that fails or panics is
+// restarted with an exponential backoff, until the stop channel is closed.
+type Task struct {
+	name string
+	run  func(stopCh <-chan struct{}) error
+
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	stablePeriod   time.Duration
+
+	mu       sync.Mutex
+	failures int
+	lastErr  error
+}
+
+// Go runs the task in a goroutine. run must return once its stop channel is
+// closed; returning nil before that ends the task, returning an error or
+// panicking restarts it. The stop channel of a failed run is closed before
+// the next run starts, so that the goroutines it started are stopped.
+func Go(name string, stopCh <-chan struct{}, run func(stopCh <-chan struct{}) error) *Task {
+	t := &Task{
+		name:           name,
+		run:            run,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		stablePeriod:   stablePeriod,
+	}
+	go t.supervise(stopCh)
+	return t
+}
+
+// CrashLooping returns true along with the last error when the task failed
+// several times in a row
+func (t *Task) CrashLooping() (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.failures >= crashLoopThreshold, t.lastErr
+}
+
+func (t *Task) supervise(stopCh <-chan struct{}) {
+	backoff := t.initialBackoff
+	for {
+		runStopCh := make(chan struct{})
+		done := make(chan error, 1)
+		go func() {
+			done <- t.safeRun(runStopCh)
+		}()
+
+		stable := time.NewTimer(t.stablePeriod)
+		var err error
+	wait:
+		for {
+			select {
+			case err = <-done:
+				break wait
+			case <-stable.C:
+				t.mu.Lock()
+				t.failures = 0
+				t.mu.Unlock()
+				backoff = t.initialBackoff
+			case <-stopCh:
+				close(runStopCh)
+				stable.Stop()
+				<-done
+				return
+			}
+		}
+		close(runStopCh)
+		stable.Stop()
+		if err == nil {
+			return
+		}
+
+		t.mu.Lock()
+		t.failures++
+		t.lastErr = err
+		t.mu.Unlock()
+		taskRestarts.WithLabelValues(t.name).Inc()
+		logger.Error(err, "Background task failed, restarting", "task", t.name, "backoff", backoff.String())
+
+		select {
+		case <-time.After(backoff):
+		case <-stopCh:
+			return
+		}
+		backoff *= 2
+		if backoff > t.maxBackoff {
+			backoff = t.maxBackoff
+		}
+	}
+}
+
+func (t *Task) safeRun(stopCh <-chan struct{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Info("Background task panicked", "task", t.name, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return t.run(stopCh)
+}
@@ -0,0 +1,184 @@
+package supervisor
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// restartCount reads the restart counter of the task named name
+func restartCount(g *WithT, name string) float64 {
+	m := &dto.Metric{}
+	g.Expect(taskRestarts.WithLabelValues(name).Write(m)).To(Succeed())
+	return m.GetCounter().GetValue()
+}
+
+// shortDelays shortens the backoff and the stable period for the test
+func shortDelays(t *testing.T, backoff, max, stable time.Duration) {
+	previousBackoff, previousMax, previousStable := initialBackoff, maxBackoff, stablePeriod
+	initialBackoff, maxBackoff, stablePeriod = backoff, max, stable
+	t.Cleanup(func() {
+		initialBackoff, maxBackoff, stablePeriod = previousBackoff, previousMax, previousStable
+	})
+}
+
+// runRecorder records the start times of the runs of a task
+type runRecorder struct {
+	mu     sync.Mutex
+	starts []time.Time
+}
+
+func (r *runRecorder) start() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.starts = append(r.starts, time.Now())
+	return len(r.starts)
+}
+
+func (r *runRecorder) runs() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.starts)
+}
+
+func (r *runRecorder) gaps() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	gaps := []time.Duration{}
+	for i := 1; i < len(r.starts); i++ {
+		gaps = append(gaps, r.starts[i].Sub(r.starts[i-1]))
+	}
+	return gaps
+}
+
+func TestFailingTask(t *testing.T) {
+	g := NewWithT(t)
+	shortDelays(t, 20*time.Millisecond, 80*time.Millisecond, time.Hour)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	rec := &runRecorder{}
+
+	task := Go("failing", stopCh, func(stopCh <-chan struct{}) error {
+		rec.start()
+		return errors.New("failed")
+	})
+
+	g.Eventually(rec.runs, 2*time.Second, 5*time.Millisecond).Should(BeNumerically(">=", 5))
+	crashLooping, err := task.CrashLooping()
+	g.Expect(crashLooping).To(BeTrue())
+	g.Expect(err).To(MatchError("failed"))
+	g.Expect(restartCount(g, "failing")).To(BeNumerically(">=", 5))
+	// The backoff doubles from initialBackoff up to maxBackoff
+	gaps := rec.gaps()
+	for i, min := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond, 80 * time.Millisecond} {
+		g.Expect(gaps[i]).To(BeNumerically(">=", min), "restart %d", i+1)
+	}
+	g.Expect(gaps[3]).To(BeNumerically("<", 160*time.Millisecond), "the backoff is capped")
+}
+
+func TestPanickingTask(t *testing.T) {
+	g := NewWithT(t)
+	shortDelays(t, time.Millisecond, time.Millisecond, time.Hour)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	rec := &runRecorder{}
+
+	task := Go("panicking", stopCh, func(stopCh <-chan struct{}) error {
+		if rec.start() <= crashLoopThreshold {
+			panic("nil map")
+		}
+		<-stopCh
+		return nil
+	})
+
+	g.Eventually(rec.runs, time.Second, time.Millisecond).Should(Equal(crashLoopThreshold + 1))
+	crashLooping, err := task.CrashLooping()
+	g.Expect(crashLooping).To(BeTrue())
+	g.Expect(err).To(MatchError(ContainSubstring("panic: nil map")))
+}
+
+func TestTaskStableReset(t *testing.T) {
+	g := NewWithT(t)
+	shortDelays(t, time.Millisecond, time.Millisecond, 50*time.Millisecond)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	rec := &runRecorder{}
+	release := make(chan struct{})
+
+	// The task fails until it runs for longer than the stable period
+	task := Go("flapping", stopCh, func(stopCh <-chan struct{}) error {
+		run := rec.start()
+		if run <= crashLoopThreshold {
+			return errors.New("failed")
+		}
+		if run == crashLoopThreshold+1 {
+			select {
+			case <-release:
+				return errors.New("failed again")
+			case <-stopCh:
+				return nil
+			}
+		}
+		<-stopCh
+		return nil
+	})
+
+	g.Eventually(rec.runs, time.Second, time.Millisecond).Should(Equal(crashLoopThreshold + 1))
+	crashLooping, _ := task.CrashLooping()
+	g.Expect(crashLooping).To(BeTrue())
+	// The failures are forgotten once the run lasted the stable period
+	g.Eventually(func() bool {
+		crashLooping, _ := task.CrashLooping()
+		return crashLooping
+	}, time.Second, 5*time.Millisecond).Should(BeFalse())
+
+	close(release)
+	g.Eventually(rec.runs, time.Second, time.Millisecond).Should(Equal(crashLoopThreshold + 2))
+	crashLooping, err := task.CrashLooping()
+	g.Expect(crashLooping).To(BeFalse())
+	g.Expect(err).To(MatchError("failed again"))
+}
+
+func TestTaskStop(t *testing.T) {
+	g := NewWithT(t)
+	shortDelays(t, time.Millisecond, time.Millisecond, time.Hour)
+	stopCh := make(chan struct{})
+	runStopped := make(chan struct{})
+	rec := &runRecorder{}
+
+	Go("stopped", stopCh, func(runStopCh <-chan struct{}) error {
+		rec.start()
+		<-runStopCh
+		close(runStopped)
+		return nil
+	})
+
+	g.Eventually(rec.runs, time.Second, time.Millisecond).Should(Equal(1))
+	close(stopCh)
+	g.Eventually(runStopped, time.Second).Should(BeClosed())
+	g.Consistently(rec.runs, 50*time.Millisecond, 5*time.Millisecond).Should(Equal(1))
+}
+
+func TestTaskEnds(t *testing.T) {
+	g := NewWithT(t)
+	shortDelays(t, time.Millisecond, time.Millisecond, time.Hour)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	rec := &runRecorder{}
+
+	// A run returning nil before the stop ends the task
+	task := Go("ended", stopCh, func(stopCh <-chan struct{}) error {
+		rec.start()
+		return nil
+	})
+
+	g.Eventually(rec.runs, time.Second, time.Millisecond).Should(Equal(1))
+	g.Consistently(rec.runs, 50*time.Millisecond, 5*time.Millisecond).Should(Equal(1))
+	crashLooping, err := task.CrashLooping()
+	g.Expect(crashLooping).To(BeFalse())
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,252 @@
+package tenantrbac
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/k8snetworkplumbingwg/sriov-network-operator/pkg/apply"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Name is the name of the ServiceAccount, roles and bindings of the
+	// operator identity in the tenant cluster
+	Name = "dpu-network-operator"
+
+	tokenSecretName = Name + "-token"
+
+	// tenantAgentName is the name of the DaemonSet, ServiceAccount and
+	// roles of the tenant agent
+	tenantAgentName = "dpu-tenant-agent"
+
+	// connectivityCheckName is the name of the DaemonSet, Service,
+	// ServiceAccount and role of the connectivity check
+	connectivityCheckName = "dpu-connectivity-check"
+)
+
+// Objects returns the ServiceAccount and the RBAC the operator needs in the
+// tenant cluster, namespace being the namespace of ovn-kubernetes:
+//   - read the ovnkube-config and ovn-ca ConfigMaps and the ovn-cert Secret
+//     mirrored by the tenant syncer
+//   - find the ovnkube-master pods and DaemonSet
+//   - read, label and taint the tenant Nodes, set their DpuNetworkReady
+//     condition and manage their NodeMaintenances
+//   - request the SelfNodeRemediation of the tenant Nodes of unreachable DPUs
+//   - check the NetworkAttachmentDefinitions of the representor networks
+//   - watch the network operator config, e.g. for its gateway mode
+//   - request the per-node certificates of ovnkube-node and approve them
+//   - deploy the tenant agent, with its ServiceAccount and roles
+//   - deploy ovnkube in dpu-host mode on the tenant workers
+//   - deploy the connectivity check, with its Service, ServiceAccount and
+//     role, and read the status of its pods
+//   - renew the token of its own ServiceAccount
+func Objects(namespace string) []client.Object {
+	return []client.Object{
+		&corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: namespace},
+		},
+		&corev1.Secret{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        tokenSecretName,
+				Namespace:   namespace,
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: Name},
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+		},
+		&rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: namespace},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"configmaps", "secrets"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{"apps"},
+					Resources: []string{"daemonsets"},
+					Verbs:     []string{"get", "create", "update", "patch", "delete"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"serviceaccounts"},
+					Verbs:     []string{"get", "create", "update", "patch", "delete"},
+				},
+				{
+					APIGroups:     []string{""},
+					Resources:     []string{"serviceaccounts/token"},
+					ResourceNames: []string{Name},
+					Verbs:         []string{"create"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"services"},
+					Verbs:     []string{"get", "create", "update", "patch", "delete"},
+				},
+				{
+					APIGroups: []string{rbacv1.GroupName},
+					Resources: []string{"roles", "rolebindings"},
+					Verbs:     []string{"get", "create", "update", "patch", "delete"},
+				},
+				{
+					APIGroups:     []string{rbacv1.GroupName},
+					Resources:     []string{"roles"},
+					ResourceNames: []string{connectivityCheckName},
+					Verbs:         []string{"bind", "escalate"},
+				},
+			},
+		},
+		&rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: namespace},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: Name},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: Name, Namespace: namespace}},
+		},
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: Name},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"pods"},
+					Verbs:     []string{"list"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"nodes"},
+					Verbs:     []string{"get", "patch"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"nodes/status"},
+					Verbs:     []string{"patch"},
+				},
+				{
+					APIGroups: []string{"nodemaintenance.medik8s.io", "nodemaintenance.kubevirt.io"},
+					Resources: []string{"nodemaintenances"},
+					Verbs:     []string{"get", "create", "delete"},
+				},
+				{
+					APIGroups: []string{"self-node-remediation.medik8s.io"},
+					Resources: []string{"selfnoderemediationtemplates"},
+					Verbs:     []string{"get"},
+				},
+				{
+					APIGroups: []string{"self-node-remediation.medik8s.io"},
+					Resources: []string{"selfnoderemediations"},
+					Verbs:     []string{"get", "create", "delete"},
+				},
+				{
+					APIGroups: []string{"k8s.cni.cncf.io"},
+					Resources: []string{"network-attachment-definitions"},
+					Verbs:     []string{"get"},
+				},
+				{
+					APIGroups: []string{"operator.openshift.io"},
+					Resources: []string{"networks"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{"certificates.k8s.io"},
+					Resources: []string{"certificatesigningrequests"},
+					Verbs:     []string{"get", "list", "watch", "create"},
+				},
+				{
+					APIGroups: []string{"certificates.k8s.io"},
+					Resources: []string{"certificatesigningrequests/approval"},
+					Verbs:     []string{"update"},
+				},
+				{
+					APIGroups: []string{rbacv1.GroupName},
+					Resources: []string{"clusterroles", "clusterrolebindings"},
+					Verbs:     []string{"get", "create", "update", "patch", "delete"},
+				},
+				{
+					APIGroups:     []string{rbacv1.GroupName},
+					Resources:     []string{"clusterroles"},
+					ResourceNames: []string{tenantAgentName},
+					Verbs:         []string{"bind", "escalate"},
+				},
+				{
+					APIGroups:     []string{"certificates.k8s.io"},
+					Resources:     []string{"signers"},
+					ResourceNames: []string{certificatesv1.KubeAPIServerClientSignerName},
+					Verbs:         []string{"approve"},
+				},
+			},
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: Name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: Name},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: Name, Namespace: namespace}},
+		},
+	}
+}
+
+// Apply creates or updates the objects returned by Objects in the tenant
+// cluster
+func Apply(ctx context.Context, c client.Client, namespace string) error {
+	for _, obj := range Objects(namespace) {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		if err := apply.ApplyObject(ctx, c, &unstructured.Unstructured{Object: content}); err != nil {
+			return fmt.Errorf("failed to apply %s %s: %v", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// Kubeconfig returns a kubeconfig of the tenant cluster authenticating with
+// the token of the operator ServiceAccount. The server and CA are the ones of
+// cfg.
+func Kubeconfig(ctx context.Context, c client.Client, cfg *rest.Config, namespace string) ([]byte, error) {
+	s := &corev1.Secret{}
+	// The token is filled asynchronously by the token controller
+	err := wait.PollImmediateWithContext(ctx, time.Second, time.Minute, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, types.NamespacedName{Name: tokenSecretName, Namespace: namespace}, s); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return len(s.Data[corev1.ServiceAccountTokenKey]) > 0, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the token of the %s service account: %v", Name, err)
+	}
+
+	ca := s.Data[corev1.ServiceAccountRootCAKey]
+	if len(cfg.CAData) > 0 {
+		ca = cfg.CAData
+	}
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters["tenant"] = &clientcmdapi.Cluster{
+		Server:                   cfg.Host,
+		CertificateAuthorityData: ca,
+		InsecureSkipTLSVerify:    cfg.Insecure,
+	}
+	kubeconfig.AuthInfos[Name] = &clientcmdapi.AuthInfo{
+		Token: string(s.Data[corev1.ServiceAccountTokenKey]),
+	}
+	kubeconfig.Contexts["tenant"] = &clientcmdapi.Context{
+		Cluster:   "tenant",
+		AuthInfo:  Name,
+		Namespace: namespace,
+	}
+	kubeconfig.CurrentContext = "tenant"
+	return clientcmd.Write(*kubeconfig)
+}
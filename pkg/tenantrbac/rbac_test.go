@@ -0,0 +1,217 @@
+package tenantrbac
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const testNamespace = "openshift-ovn-kubernetes"
+
+// access is a request of the operator to the tenant cluster
+type access struct {
+	group    string
+	resource string
+	name     string
+	verb     string
+	// namespaced accesses are allowed by the Role or the ClusterRole,
+	// the others by the ClusterRole only
+	namespaced bool
+}
+
+// operatorAccesses are the requests the operator sends to the tenant
+// cluster, other than the ones applying the tenant manifests
+var operatorAccesses = []access{
+	// The tenant syncer mirrors the ovnkube-config and ovn-ca ConfigMaps and
+	// the ovn-cert Secret
+	{resource: "configmaps", verb: "get", namespaced: true},
+	{resource: "configmaps", verb: "list", namespaced: true},
+	{resource: "configmaps", verb: "watch", namespaced: true},
+	{resource: "secrets", verb: "get", namespaced: true},
+	{resource: "secrets", verb: "list", namespaced: true},
+	{resource: "secrets", verb: "watch", namespaced: true},
+	// The ovnkube-master pods and DaemonSet are looked up for the upgrades
+	{resource: "pods", verb: "list"},
+	{group: "apps", resource: "daemonsets", name: "ovnkube-master", verb: "get", namespaced: true},
+	// The tenant Nodes are labelled, annotated and tainted, and their
+	// DpuNetworkReady condition is set
+	{resource: "nodes", verb: "get"},
+	{resource: "nodes", verb: "patch"},
+	{resource: "nodes/status", verb: "patch"},
+	// The tenant Nodes are drained along with their DPU
+	{group: "nodemaintenance.medik8s.io", resource: "nodemaintenances", verb: "get"},
+	{group: "nodemaintenance.medik8s.io", resource: "nodemaintenances", verb: "create"},
+	{group: "nodemaintenance.medik8s.io", resource: "nodemaintenances", verb: "delete"},
+	{group: "nodemaintenance.kubevirt.io", resource: "nodemaintenances", verb: "get"},
+	{group: "nodemaintenance.kubevirt.io", resource: "nodemaintenances", verb: "create"},
+	{group: "nodemaintenance.kubevirt.io", resource: "nodemaintenances", verb: "delete"},
+	// The tenant Nodes of unreachable DPUs are remediated, in the namespace
+	// of the remediation template
+	{group: "self-node-remediation.medik8s.io", resource: "selfnoderemediationtemplates", verb: "get"},
+	{group: "self-node-remediation.medik8s.io", resource: "selfnoderemediations", verb: "get"},
+	{group: "self-node-remediation.medik8s.io", resource: "selfnoderemediations", verb: "create"},
+	{group: "self-node-remediation.medik8s.io", resource: "selfnoderemediations", verb: "delete"},
+	// The representor networks are checked
+	{group: "k8s.cni.cncf.io", resource: "network-attachment-definitions", verb: "get", namespaced: true},
+	// The network operator config is watched for the gateway mode
+	{group: "operator.openshift.io", resource: "networks", name: "cluster", verb: "get"},
+	{group: "operator.openshift.io", resource: "networks", verb: "list"},
+	{group: "operator.openshift.io", resource: "networks", verb: "watch"},
+	// The per-node certificates of ovnkube-node are requested and approved
+	{group: "certificates.k8s.io", resource: "certificatesigningrequests", verb: "get"},
+	{group: "certificates.k8s.io", resource: "certificatesigningrequests", verb: "list"},
+	{group: "certificates.k8s.io", resource: "certificatesigningrequests", verb: "watch"},
+	{group: "certificates.k8s.io", resource: "certificatesigningrequests", verb: "create"},
+	{group: "certificates.k8s.io", resource: "certificatesigningrequests/approval", verb: "update"},
+	{group: "certificates.k8s.io", resource: "signers", name: certificatesv1.KubeAPIServerClientSignerName, verb: "approve"},
+	// The pods of the connectivity check are read
+	{resource: "pods", verb: "list", namespaced: true},
+	// The token of the operator ServiceAccount is renewed
+	{resource: "serviceaccounts/token", name: Name, verb: "create", namespaced: true},
+}
+
+// tenantManifests are the manifests the operator applies to the tenant
+// cluster, relative to the root of the repository
+var tenantManifests = []string{
+	"bindata/tenant-agent",
+	"bindata/ovnkube-dpu-host",
+	"bindata/connectivity-check",
+}
+
+// kindResources are the resources of the kinds of the tenant manifests
+var kindResources = map[string]string{
+	"DaemonSet":          "daemonsets",
+	"Service":            "services",
+	"ServiceAccount":     "serviceaccounts",
+	"Role":               "roles",
+	"RoleBinding":        "rolebindings",
+	"ClusterRole":        "clusterroles",
+	"ClusterRoleBinding": "clusterrolebindings",
+}
+
+var (
+	apiVersionField = regexp.MustCompile(`(?m)^apiVersion: *(\S+)`)
+	kindField       = regexp.MustCompile(`(?m)^kind: *(\S+)`)
+	nameField       = regexp.MustCompile(`(?m)^metadata:\n  name: *(\S+)`)
+	namespaceField  = regexp.MustCompile(`(?m)^  namespace: `)
+)
+
+// manifestAccesses returns the requests sent to apply and delete the objects
+// of the tenant manifests of dir: the get, create and update of
+// apply.ApplyObject and the delete of the cleanup. The roles are also bound,
+// and created with the permissions they grant.
+func manifestAccesses(t *testing.T, dir string) []access {
+	g := NewWithT(t)
+	files, err := filepath.Glob(filepath.Join("..", "..", dir, "*.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(files).NotTo(BeEmpty())
+
+	accesses := []access{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		g.Expect(err).NotTo(HaveOccurred())
+		for _, doc := range strings.Split(string(content), "\n---") {
+			kind := kindField.FindStringSubmatch(doc)
+			if kind == nil {
+				continue
+			}
+			apiVersion := apiVersionField.FindStringSubmatch(doc)
+			g.Expect(apiVersion).NotTo(BeNil(), "apiVersion of %s in %s", kind[1], file)
+			gv, err := schema.ParseGroupVersion(apiVersion[1])
+			g.Expect(err).NotTo(HaveOccurred())
+			resource, ok := kindResources[kind[1]]
+			g.Expect(ok).To(BeTrue(), "unknown kind %s in %s", kind[1], file)
+			name := nameField.FindStringSubmatch(doc)
+			g.Expect(name).NotTo(BeNil(), "name of %s in %s", kind[1], file)
+			namespaced := namespaceField.MatchString(doc)
+
+			for _, verb := range []string{"get", "create", "update", "delete"} {
+				accesses = append(accesses, access{group: gv.Group, resource: resource, name: name[1], verb: verb, namespaced: namespaced})
+			}
+			if kind[1] == "Role" || kind[1] == "ClusterRole" {
+				for _, verb := range []string{"bind", "escalate"} {
+					accesses = append(accesses, access{group: gv.Group, resource: resource, name: name[1], verb: verb, namespaced: namespaced})
+				}
+			}
+		}
+	}
+	return accesses
+}
+
+// allows tells whether one of rules allows a
+func allows(rules []rbacv1.PolicyRule, a access) bool {
+	for _, rule := range rules {
+		if matches(rule.APIGroups, a.group) && matches(rule.Resources, a.resource) && matches(rule.Verbs, a.verb) &&
+			(len(rule.ResourceNames) == 0 || (a.name != "" && matches(rule.ResourceNames, a.name))) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == rbacv1.ResourceAll {
+			return true
+		}
+	}
+	return false
+}
+
+func TestObjectsCoverOperatorAccesses(t *testing.T) {
+	g := NewWithT(t)
+	var role *rbacv1.Role
+	var clusterRole *rbacv1.ClusterRole
+	for _, obj := range Objects(testNamespace) {
+		switch o := obj.(type) {
+		case *rbacv1.Role:
+			role = o
+		case *rbacv1.ClusterRole:
+			clusterRole = o
+		}
+	}
+	g.Expect(role).NotTo(BeNil())
+	g.Expect(clusterRole).NotTo(BeNil())
+
+	accesses := append([]access{}, operatorAccesses...)
+	for _, dir := range tenantManifests {
+		accesses = append(accesses, manifestAccesses(t, dir)...)
+	}
+	for _, a := range accesses {
+		allowed := allows(clusterRole.Rules, a) || (a.namespaced && allows(role.Rules, a))
+		g.Expect(allowed).To(BeTrue(), "%s %s.%s %s is not allowed", a.verb, a.resource, a.group, a.name)
+	}
+}
+
+func TestAllows(t *testing.T) {
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}},
+		{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{Name}, Verbs: []string{"create"}},
+	}
+	tests := []struct {
+		name    string
+		access  access
+		allowed bool
+	}{
+		{name: "allowed", access: access{resource: "nodes", verb: "get"}, allowed: true},
+		{name: "other verb", access: access{resource: "nodes", verb: "delete"}},
+		{name: "other group", access: access{group: "apps", resource: "nodes", verb: "get"}},
+		{name: "subresource", access: access{resource: "nodes/status", verb: "get"}},
+		{name: "resource name", access: access{resource: "serviceaccounts/token", name: Name, verb: "create"}, allowed: true},
+		{name: "other resource name", access: access{resource: "serviceaccounts/token", name: "default", verb: "create"}},
+		{name: "any resource name", access: access{resource: "serviceaccounts/token", verb: "create"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(allows(rules, tt.access)).To(Equal(tt.allowed))
+		})
+	}
+}
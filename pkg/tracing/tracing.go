@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Enabled turns the spans on, they are no-ops otherwise
+var Enabled bool
+
+var logger = ctrl.Log.WithName("tracing")
+
+var spanDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "dpu_operator_span_duration_seconds",
+		Help:    "Duration of the reconciles, renders, applies and tenant cluster API calls of the operator.",
+		Buckets: []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60},
+	},
+	[]string{"span", "result"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(spanDuration)
+}
+
+type spanKey struct{}
+
+// Span measures an operation of the operator. The spans started from a
+// context carrying a span share its trace ID, so that the steps of a slow
+// reconcile can be told apart in the logs. The trace ID is only logged, it is
+// not an OpenTelemetry trace ID.
+type Span struct {
+	name          string
+	traceID       string
+	parent        string
+	start         time.Time
+	keysAndValues []interface{}
+}
+
+// Start starts a span named after the operation, e.g. reconcile or apply,
+// and returns a context carrying it. It returns a nil span, whose End is a
+// no-op, when the tracing is disabled.
+func Start(ctx context.Context, name string, keysAndValues ...interface{}) (context.Context, *Span) {
+	if !Enabled {
+		return ctx, nil
+	}
+	span := &Span{
+		name:          name,
+		start:         time.Now(),
+		keysAndValues: keysAndValues,
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		span.traceID = parent.traceID
+		span.parent = parent.name
+	} else {
+		span.traceID = newTraceID()
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// End ends the span, err being the outcome of the operation
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	duration := time.Since(s.start)
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	spanDuration.WithLabelValues(s.name, result).Observe(duration.Seconds())
+	keysAndValues := append([]interface{}{
+		"span", s.name,
+		"trace", s.traceID,
+		"parent", s.parent,
+		"duration", duration.String(),
+		"result", result,
+	}, s.keysAndValues...)
+	logger.V(1).Info("Span ended", keysAndValues...)
+}
+
+func newTraceID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// WrapTransport makes the clients built from the REST config record a span
+// for each API call to the given cluster
+func WrapTransport(cfg *rest.Config, cluster string) *rest.Config {
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &roundTripper{next: rt, cluster: cluster}
+	})
+	return cfg
+}
+
+type roundTripper struct {
+	next    http.RoundTripper
+	cluster string
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The watches last as long as the informers are running
+	if req.URL.Query().Get("watch") == "true" {
+		return t.next.RoundTrip(req)
+	}
+	_, span := Start(req.Context(), t.cluster+"-api", "method", req.Method, "path", req.URL.Path)
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+		span.End(&statusError{resp.Status})
+		return resp, err
+	}
+	span.End(err)
+	return resp, err
+}
+
+type statusError struct {
+	status string
+}
+
+func (e *statusError) Error() string {
+	return e.status
+}
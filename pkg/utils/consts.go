@@ -1,14 +1,80 @@
 package utils
 
 const (
-	CmNameOvnkubeConfig   = "ovnkube-config"
-	CmNameTenantCLusterCA = "tenant-cluster-ca.crt"
-	CmNameOvnCa           = "ovn-ca"
+	CmNameOvnkubeConfig    = "ovnkube-config"
+	CmNameTenantCLusterCA  = "tenant-cluster-ca.crt"
+	CmNameOvnCa            = "ovn-ca"
+	CmNameSignerCa         = "signer-ca"
+	CmNameDpuNodeOverrides = "dpu-node-overrides"
 
-	SecretNameOvnCert = "ovn-cert"
+	SecretNameOvnCert          = "ovn-cert"
+	SecretNameTenantKubeconfig = "dpu-tenant-kubeconfig"
+
+	DpuSerialAnnotation = "dpu.openshift.io/dpu-serial"
+
+	// OvsDumpAnnotation set on a DPU node requests an OVS dump of the node,
+	// a new dump being taken whenever its value changes. The dump is written
+	// in the OvsDumpConfigMapPrefix<node> ConfigMap, annotated with
+	// OvsDumpCompletedAnnotation once complete.
+	OvsDumpAnnotation          = "dpu.openshift.io/ovs-dump"
+	OvsDumpCompletedAnnotation = "dpu.openshift.io/ovs-dump-completed"
+	OvsDumpConfigMapPrefix     = "dpu-ovs-dump-"
+
+	// OvnkubeConfigHashAnnotation is set on the pod template of the
+	// DaemonSets reading the ovnkube-config, so that they roll out when the
+	// tenant cluster changes it, e.g. its MTU
+	OvnkubeConfigHashAnnotation = "dpu.openshift.io/ovnkube-config-hash"
+
+	// OvnSouthboundPodCondition is the condition of the OVS agent pods
+	// telling whether the ovn-controller of their DPU node is connected to
+	// the southbound database
+	OvnSouthboundPodCondition = "dpu.openshift.io/ovn-southbound-connected"
+
+	// PodToPodCondition and PodToServiceCondition are the conditions of the
+	// connectivity checker pods telling whether they reach the other checker
+	// pods, and the ClusterIP service in front of them
+	PodToPodCondition     = "dpu.openshift.io/pod-to-pod"
+	PodToServiceCondition = "dpu.openshift.io/pod-to-service"
+
+	// TenantNodeDpuLabel and TenantNodeDpuSerialLabel are set on the tenant
+	// nodes backed by a DPU, for the tenant side schedulers and dashboards
+	TenantNodeDpuLabel       = "network.openshift.io/dpu"
+	TenantNodeDpuSerialLabel = "network.openshift.io/dpu-serial"
+
+	// TenantNodeNetworkUnavailableTaint is kept on the tenant nodes until the
+	// network of their DPU is ready, when the tenant nodes are tainted
+	TenantNodeNetworkUnavailableTaint = "network.openshift.io/dpu-network-unavailable"
+
+	// DpuArchitecture is the CPU architecture of the Arm cores of the DPUs
+	DpuArchitecture = "arm64"
+
+	KubeconfigKey              = "config"
+	HostedClusterKubeconfigKey = "kubeconfig"
+	// ValueKubeconfigKey is the key of the kubeconfig secrets of Cluster API
+	ValueKubeconfigKey = "value"
 
 	OvnkubeNodeManifestPath = "./bindata/ovnkube-node"
+	MachineConfigPath       = "./bindata/machine-config"
+	SwitchdevDaemonPath     = "./bindata/switchdev-daemon"
+	OpiBridgeManifestPath   = "./bindata/opi-bridge"
+	OpiBridgeDsName         = "opi-bridge"
+	DocaTelemetryPath       = "./bindata/doca-telemetry"
+	DocaTelemetryDsName     = "doca-telemetry"
+	TenantAgentPath         = "./bindata/tenant-agent"
+	TenantAgentDsName       = "dpu-tenant-agent"
+	OvnkubeDpuHostPath      = "./bindata/ovnkube-dpu-host"
+	OvnkubeDpuHostDsName    = "ovnkube-node-dpu-host"
+	OvsAgentPath            = "./bindata/ovs-agent"
+	OvsAgentName            = "dpu-ovs-agent"
+	ConnectivityCheckPath   = "./bindata/connectivity-check"
+	ConnectivityCheckName   = "dpu-connectivity-check"
+	NetworkPolicyPath       = "./bindata/network-policy"
+	OvsdbRelayPath          = "./bindata/ovsdb-relay"
+	OvsdbRelayName          = "ovsdb-relay"
+	OvsdbRelayPolicyName    = "allow-ovsdb-relay"
+	CniConfigMapName        = "dpu-cni-config"
 	SaNameOvnkubeNode       = "ovn-kubernetes-node"
-	LocalOvnkbueNamespace   = "openshift-ovn-kubernetes"
-	LocalOvnkbueNodeDsName  = "ovnkube-node"
+	OvnkubeNodeDsName       = "ovnkube-node"
+	OvnIPsecDsName          = "ovn-ipsec"
+	DefaultTenantNamespace  = "openshift-ovn-kubernetes"
 )
@@ -6,8 +6,20 @@ import (
 
 var TenantNamespace string
 var Namespace string
+var ServiceAccount string
+
+// PodName is the name of the operator pod
+var PodName string
+
+// SimulateHardware is a feature gate making the operator work on clusters
+// without BlueField cards: the serial numbers are fabricated and the steps
+// configuring the NICs are skipped. It is meant for development and demos.
+var SimulateHardware bool
 
 func init() {
 	TenantNamespace = os.Getenv("TENANT_NAMESPACE")
 	Namespace = os.Getenv("NAMESPACE")
+	ServiceAccount = os.Getenv("SERVICE_ACCOUNT")
+	PodName = os.Getenv("POD_NAME")
+	SimulateHardware = os.Getenv("SIMULATE_DPU_HARDWARE") == "true"
 }
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// KubeconfigKeys are the keys under which the kubeconfig secrets commonly
+// hold the kubeconfig: "config" for the ones created by hand, "kubeconfig"
+// for HyperShift and "value" for Cluster API
+var KubeconfigKeys = []string{KubeconfigKey, HostedClusterKubeconfigKey, ValueKubeconfigKey}
+
+// GetSecretKubeconfig returns the kubeconfig held by the secret along with
+// its key, the preferred key being looked up before KubeconfigKeys
+func GetSecretKubeconfig(s *corev1.Secret, preferred string) (string, []byte, error) {
+	for _, key := range append([]string{preferred}, KubeconfigKeys...) {
+		if bytes, ok := s.Data[key]; ok {
+			return key, bytes, nil
+		}
+	}
+	found := []string{}
+	for key := range s.Data {
+		found = append(found, key)
+	}
+	sort.Strings(found)
+	return "", nil, fmt.Errorf("none of the keys %s found in secret %s/%s, which has the keys [%s]",
+		strings.Join(KubeconfigKeys, ", "), s.Namespace, s.Name, strings.Join(found, ", "))
+}
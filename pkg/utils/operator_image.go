@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OperatorContainerName is the name of the container of the operator pod
+// running the manager
+const OperatorContainerName = "manager"
+
+// GetOperatorImage returns the image of the manager container of the
+// operator pod, so that the operator can run other pods from its own image
+func GetOperatorImage(ctx context.Context, c client.Reader, namespace, podName string) (string, error) {
+	if podName == "" {
+		return "", fmt.Errorf("POD_NAME is not set")
+	}
+	pod := &corev1.Pod{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: podName}, pod); err != nil {
+		return "", err
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == OperatorContainerName {
+			return container.Image, nil
+		}
+	}
+	return "", fmt.Errorf("container %s not found in pod %s/%s", OperatorContainerName, namespace, podName)
+}
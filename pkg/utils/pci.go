@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SysBusPciDevices = "/sys/bus/pci/devices"
+
+	mellanoxVendorID = "0x15b3"
+
+	vpdTagReadOnly = 0x90
+	vpdTagEnd      = 0x78
+)
+
+// bluefieldDeviceIDs are the PCI device IDs of the BlueField network
+// controllers, as seen from the host and from the Arm cores
+var bluefieldDeviceIDs = map[string]bool{
+	"0xa2d2": true, // BlueField
+	"0xa2d6": true, // BlueField-2
+	"0xa2dc": true, // BlueField-3
+}
+
+// SimulatedDpuSerialNumber fabricates a stable serial number for the
+// simulated DPU of a node
+func SimulatedDpuSerialNumber(nodeName string) string {
+	return fmt.Sprintf("SIM%X", sha256.Sum256([]byte(nodeName)))[:15]
+}
+
+// GetDpuSerialNumber returns the serial number of the BlueField DPU found
+// under the sysfs PCI devices directory, read from the VPD of its PF
+func GetDpuSerialNumber(devicesDir string) (string, error) {
+	devices, err := os.ReadDir(devicesDir)
+	if err != nil {
+		return "", err
+	}
+	for _, d := range devices {
+		dir := filepath.Join(devicesDir, d.Name())
+		if readSysfsValue(dir, "vendor") != mellanoxVendorID || !bluefieldDeviceIDs[readSysfsValue(dir, "device")] {
+			continue
+		}
+		vpd, err := os.ReadFile(filepath.Join(dir, "vpd"))
+		if err != nil {
+			return "", fmt.Errorf("failed to read the VPD of %s: %v", d.Name(), err)
+		}
+		serial, err := parseVpdSerialNumber(vpd)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse the VPD of %s: %v", d.Name(), err)
+		}
+		return serial, nil
+	}
+	return "", fmt.Errorf("no BlueField device found in %s", devicesDir)
+}
+
+func readSysfsValue(dir, name string) string {
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// parseVpdSerialNumber returns the SN keyword of the read-only section of
+// the PCI Vital Product Data
+func parseVpdSerialNumber(vpd []byte) (string, error) {
+	for i := 0; i < len(vpd); {
+		tag := vpd[i]
+		if tag == vpdTagEnd {
+			break
+		}
+		if tag&0x80 == 0 {
+			// Small resource, the length is in the 3 lowest bits
+			i += 1 + int(tag&0x07)
+			continue
+		}
+		if i+3 > len(vpd) {
+			break
+		}
+		length := int(binary.LittleEndian.Uint16(vpd[i+1 : i+3]))
+		start, end := i+3, i+3+length
+		if end > len(vpd) {
+			return "", fmt.Errorf("truncated resource 0x%x", tag)
+		}
+		// The identifier string and the VPD-W resources are skipped
+		if tag == vpdTagReadOnly {
+			for j := start; j+3 <= end; {
+				keyword := string(vpd[j : j+2])
+				n := int(vpd[j+2])
+				if j+3+n > end {
+					break
+				}
+				if keyword == "SN" {
+					return strings.TrimSpace(string(vpd[j+3 : j+3+n])), nil
+				}
+				j += 3 + n
+			}
+		}
+		i = end
+	}
+	return "", fmt.Errorf("no serial number in VPD")
+}
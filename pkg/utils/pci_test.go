@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// vpdResource returns a large VPD resource of tag holding data
+func vpdResource(tag byte, data []byte) []byte {
+	b := binary.LittleEndian.AppendUint16([]byte{tag}, uint16(len(data)))
+	return append(b, data...)
+}
+
+// vpdKeyword returns a VPD keyword of a read-only or read-write resource
+func vpdKeyword(keyword, value string) []byte {
+	return append([]byte{keyword[0], keyword[1], byte(len(value))}, value...)
+}
+
+func concat(parts ...[]byte) []byte {
+	b := []byte{}
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func TestParseVpdSerialNumber(t *testing.T) {
+	ident := vpdResource(0x82, []byte("BlueField-2 DPU"))
+	readOnly := vpdResource(vpdTagReadOnly, concat(vpdKeyword("PN", "MBF2H332A-AEEOT"), vpdKeyword("SN", "MT2109X00000 "), vpdKeyword("RV", "\\x00")))
+	end := []byte{vpdTagEnd}
+	tests := []struct {
+		name   string
+		vpd    []byte
+		serial string
+		err    bool
+	}{
+		{
+			name:   "valid",
+			vpd:    concat(ident, readOnly, end),
+			serial: "MT2109X00000",
+		},
+		{
+			name:   "read-write resource before the read-only one",
+			vpd:    concat(ident, vpdResource(0x91, vpdKeyword("SN", "NOTTHIS")), readOnly, end),
+			serial: "MT2109X00000",
+		},
+		{
+			name:   "small resource before the read-only one",
+			vpd:    concat([]byte{0x02, 0xaa, 0xbb}, readOnly, end),
+			serial: "MT2109X00000",
+		},
+		{
+			name: "truncated large resource",
+			vpd:  concat(ident, readOnly[:len(readOnly)-5]),
+			err:  true,
+		},
+		{
+			name: "truncated large resource header",
+			vpd:  concat(ident, []byte{vpdTagReadOnly, 0x10}),
+			err:  true,
+		},
+		{
+			name: "missing SN keyword",
+			vpd:  concat(ident, vpdResource(vpdTagReadOnly, vpdKeyword("PN", "MBF2H332A-AEEOT")), end),
+			err:  true,
+		},
+		{
+			name: "keyword length beyond the resource",
+			vpd:  concat(ident, vpdResource(vpdTagReadOnly, []byte{'S', 'N', 0xff, 'M', 'T'}), end),
+			err:  true,
+		},
+		{
+			name: "resource length beyond the VPD",
+			vpd:  concat(ident, []byte{vpdTagReadOnly, 0xff, 0xff}, vpdKeyword("SN", "MT2109X00000")),
+			err:  true,
+		},
+		{
+			name: "small resource length beyond the VPD",
+			vpd:  []byte{0x07},
+			err:  true,
+		},
+		{
+			name: "keyword header cut by the resource end",
+			vpd:  concat(vpdResource(vpdTagReadOnly, []byte{'S', 'N'}), end),
+			err:  true,
+		},
+		{
+			name: "data after the end tag",
+			vpd:  concat(ident, end, readOnly),
+			err:  true,
+		},
+		{
+			name: "empty",
+			vpd:  []byte{},
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			serial, err := parseVpdSerialNumber(tt.vpd)
+			if tt.err {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(serial).To(Equal(tt.serial))
+		})
+	}
+}
+
+func TestParseVpdSerialNumberTruncated(t *testing.T) {
+	vpd := concat(vpdResource(0x82, []byte("BlueField-2 DPU")), vpdResource(vpdTagReadOnly, vpdKeyword("SN", "MT2109X00000")), []byte{vpdTagEnd})
+	// Every truncation of the VPD, as read from a faulty firmware, fails
+	// without panicking until the SN keyword is complete
+	for n := 0; n < len(vpd)-1; n++ {
+		if _, err := parseVpdSerialNumber(vpd[:n]); err == nil {
+			t.Errorf("no error for the VPD truncated to %d bytes", n)
+		}
+	}
+}
+
+func TestGetDpuSerialNumber(t *testing.T) {
+	g := NewWithT(t)
+	devicesDir := t.TempDir()
+	writeDevice := func(name, vendor, device string, vpd []byte) {
+		dir := filepath.Join(devicesDir, name)
+		g.Expect(os.MkdirAll(dir, 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(dir, "vendor"), []byte(vendor+"\n"), 0o644)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(dir, "device"), []byte(device+"\n"), 0o644)).To(Succeed())
+		if vpd != nil {
+			g.Expect(os.WriteFile(filepath.Join(dir, "vpd"), vpd, 0o644)).To(Succeed())
+		}
+	}
+
+	_, err := GetDpuSerialNumber(devicesDir)
+	g.Expect(err).To(HaveOccurred())
+
+	// A ConnectX NIC is not a DPU
+	writeDevice("0000:01:00.0", mellanoxVendorID, "0x101d", nil)
+	writeDevice("0000:03:00.0", mellanoxVendorID, "0xa2d6", concat(vpdResource(vpdTagReadOnly, vpdKeyword("SN", "MT2109X00000")), []byte{vpdTagEnd}))
+	serial, err := GetDpuSerialNumber(devicesDir)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(serial).To(Equal("MT2109X00000"))
+}
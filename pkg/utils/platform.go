@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	machineConfigGroup = "machineconfiguration.openshift.io"
+	securityGroup      = "security.openshift.io"
+)
+
+// Platform describes the APIs served by the infra cluster. A MicroShift infra
+// cluster does not serve the MachineConfig nor the SecurityContextConstraints
+// APIs.
+type Platform struct {
+	groupVersions map[string]bool
+}
+
+// DetectPlatform queries the API server of the infra cluster for the served
+// API group versions.
+func DetectPlatform(cfg *rest.Config) (*Platform, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := dc.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+	groupVersions := []string{}
+	for _, g := range groups.Groups {
+		for _, v := range g.Versions {
+			groupVersions = append(groupVersions, v.GroupVersion)
+		}
+	}
+	return NewPlatform(groupVersions...), nil
+}
+
+// NewPlatform returns a Platform serving the given group versions, such as
+// config.openshift.io/v1
+func NewPlatform(groupVersions ...string) *Platform {
+	p := &Platform{groupVersions: map[string]bool{}}
+	for _, gv := range groupVersions {
+		p.groupVersions[gv] = true
+	}
+	return p
+}
+
+// Serves returns true if the group version of gvk is served by the cluster.
+func (p *Platform) Serves(gvk schema.GroupVersionKind) bool {
+	return p.groupVersions[gvk.GroupVersion().String()]
+}
+
+// HasMachineConfig returns true if the MachineConfig API is available, in
+// which case the DPU host configuration is applied by the MCO.
+func (p *Platform) HasMachineConfig() bool {
+	return p.groupVersions[machineConfigGroup+"/v1"]
+}
+
+// HasSecurityContextConstraints returns true if the SCC API is available
+func (p *Platform) HasSecurityContextConstraints() bool {
+	return p.groupVersions[securityGroup+"/v1"]
+}
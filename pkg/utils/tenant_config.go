@@ -2,12 +2,133 @@ package utils
 
 import (
 	"fmt"
+	"sync"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var TenantRestConfig *rest.Config
+// TenantRestConfigStore holds the REST configs of the tenant clusters, keyed
+// by the namespace of the DpuClusterConfig serving the tenant cluster, along
+// with the clients built from them and the reachability of the tenant
+// clusters reported by their heartbeat.
+type TenantRestConfigStore struct {
+	mu          sync.RWMutex
+	configs     map[string]*rest.Config
+	clients     map[string]*TenantClientSet
+	unreachable map[string]error
+}
+
+// TenantClientSet holds the clients of a tenant cluster shared by the
+// controllers, so that the discovery and the connections of the tenant
+// cluster are not redone on each reconcile
+type TenantClientSet struct {
+	Client  client.Client
+	Dynamic dynamic.Interface
+}
+
+func NewTenantRestConfigStore() *TenantRestConfigStore {
+	return &TenantRestConfigStore{configs: map[string]*rest.Config{}, clients: map[string]*TenantClientSet{}, unreachable: map[string]error{}}
+}
+
+// Get returns the REST config of the tenant cluster served from namespace,
+// nil if there is none.
+func (s *TenantRestConfigStore) Get(namespace string) *rest.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.configs[namespace]
+}
+
+// Set replaces the REST config of the tenant cluster served from namespace,
+// dropping the clients built from the previous one.
+func (s *TenantRestConfigStore) Set(namespace string, cfg *rest.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.configs[namespace] != cfg {
+		delete(s.clients, namespace)
+	}
+	s.configs[namespace] = cfg
+}
+
+func (s *TenantRestConfigStore) Delete(namespace string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.configs, namespace)
+	delete(s.clients, namespace)
+	delete(s.unreachable, namespace)
+}
+
+// Clients returns the clients of the tenant cluster served from namespace,
+// building them on first use. nil is returned if there is no REST config for
+// namespace.
+func (s *TenantRestConfigStore) Clients(namespace string) (*TenantClientSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if clients, ok := s.clients[namespace]; ok {
+		return clients, nil
+	}
+	cfg, ok := s.configs[namespace]
+	if !ok {
+		return nil, nil
+	}
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client of the tenant cluster: %w", err)
+	}
+	d, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the dynamic client of the tenant cluster: %w", err)
+	}
+	clients := &TenantClientSet{Client: c, Dynamic: d}
+	s.clients[namespace] = clients
+	return clients, nil
+}
+
+// InvalidateClients drops the clients of the tenant cluster served from
+// namespace, e.g. once the tenant cluster rejected their credentials or
+// served new APIs, so that they are built again on next use.
+func (s *TenantRestConfigStore) InvalidateClients(namespace string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, namespace)
+}
+
+// InvalidateClientsOf drops the clients whose typed client is c, and returns
+// the namespace of their tenant cluster
+func (s *TenantRestConfigStore) InvalidateClientsOf(c client.Client) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for namespace, clients := range s.clients {
+		if clients.Client == c {
+			delete(s.clients, namespace)
+			return namespace, true
+		}
+	}
+	return "", false
+}
+
+// SetUnreachable records the error of the heartbeat of the tenant cluster
+// served from namespace, nil once the tenant cluster is reachable again.
+func (s *TenantRestConfigStore) SetUnreachable(namespace string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		delete(s.unreachable, namespace)
+		return
+	}
+	s.unreachable[namespace] = err
+}
+
+// Unreachable returns the error of the heartbeat of the tenant cluster served
+// from namespace, nil if it is reachable or not probed.
+func (s *TenantRestConfigStore) Unreachable(namespace string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.unreachable[namespace]
+}
 
 type Config struct {
 	TenantHostname string `mapstructure:"TENANT_K8S_NODE"`
@@ -4,14 +4,14 @@ import (
 	"context"
 	"fmt"
 	"github.com/barkimedes/go-deepcopy"
+	"github.com/go-logr/logr"
 	pkgerrors "github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func GetOrCreateObject(cl client.Client, expected client.Object, log logrus.FieldLogger) (client.Object, error) {
+func GetOrCreateObject(cl client.Client, expected client.Object, log logr.Logger) (client.Object, error) {
 	namespacedName := types.NamespacedName{
 		Name:      expected.GetName(),
 		Namespace: expected.GetNamespace(),
@@ -24,7 +24,7 @@ func GetOrCreateObject(cl client.Client, expected client.Object, log logrus.Fiel
 		if err = cl.Create(context.TODO(), expected); err != nil {
 			return nil, pkgerrors.Wrapf(err, msgSuffix)
 		}
-		log.Infof("Created %s", msgSuffix)
+		log.Info("Created object", "type", fmt.Sprintf("%T", expected), "name", expected.GetName(), "namespace", expected.GetNamespace())
 		obj = expected
 	}
 